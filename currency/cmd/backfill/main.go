@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"log/slog"
+	"my-currency-service/currency/internal/backfill"
+	"my-currency-service/currency/internal/clients/currency"
+	"my-currency-service/currency/internal/config"
+	"my-currency-service/currency/internal/db"
+	"my-currency-service/currency/internal/dto"
+	"my-currency-service/currency/internal/logger"
+	"my-currency-service/currency/internal/repository"
+	"my-currency-service/currency/internal/service"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Флаги объявляются до config.MustLoad: он сам вызывает flag.Parse
+var (
+	pairsFlag     = flag.String("pairs", "", `comma separated pairs, e.g. "EUR/USD,EUR/GBP"`)
+	fromFlag      = flag.String("from", "", "first day, YYYY-MM-DD")
+	toFlag        = flag.String("to", "", "last day, YYYY-MM-DD; defaults to yesterday")
+	providerFlag  = flag.String("provider", "", "rate provider; defaults to api.provider")
+	chunkDaysFlag = flag.Int("chunk-days", 0, "days per provider request; defaults to a provider specific size")
+	stateFlag     = flag.String("state", "backfill-state.json", "file with finished windows, used to resume")
+	timeoutFlag   = flag.Duration("timeout", 2*time.Minute, "timeout of one window")
+)
+
+func main() {
+	if err := run(); err != nil {
+		log.Fatal(err.Error())
+	}
+}
+
+func run() error {
+	cfg := config.MustLoad()
+
+	loggerInstance, err := logger.SetupLogger(cfg.Service.Env)
+	if err != nil {
+		return fmt.Errorf("error creating logger: %v", err)
+	}
+
+	job, err := parseJob()
+	if err != nil {
+		return err
+	}
+
+	conn, err := db.NewDatabaseConnection(cfg.Database)
+	if err != nil {
+		return fmt.Errorf("error creating repository: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	repo := repository.NewPostgresRepository(conn)
+
+	provider, err := currency.NewProvider(cfg.API.ForProvider(job.Provider), loggerInstance)
+	if err != nil {
+		return fmt.Errorf("error creating rate provider: %v", err)
+	}
+	job.Provider = provider.Name()
+
+	svc := service.NewCurrency(cfg.Rates, repo, provider, nil, nil, nil, loggerInstance)
+
+	state, err := backfill.LoadState(*stateFlag)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	loggerInstance.Info("starting backfill",
+		slog.String("provider", job.Provider),
+		slog.String("pairs", *pairsFlag),
+		slog.String("from", job.From.Format(time.DateOnly)),
+		slog.String("to", job.To.Format(time.DateOnly)),
+		slog.String("state", *stateFlag))
+
+	report, err := backfill.NewRunner(svc, state, loggerInstance).Run(ctx, job)
+
+	loggerInstance.Info("backfill finished",
+		slog.Int("windows", report.Windows),
+		slog.Int("skipped", report.Skipped),
+		slog.Int("failed", report.Failed),
+		slog.Int("records", report.Records))
+
+	if err != nil {
+		return fmt.Errorf("backfill: %w", err)
+	}
+
+	return nil
+}
+
+func parseJob() (backfill.Job, error) {
+	job := backfill.Job{
+		Provider:  strings.ToLower(strings.TrimSpace(*providerFlag)),
+		ChunkDays: *chunkDaysFlag,
+		Timeout:   *timeoutFlag,
+	}
+
+	for _, raw := range strings.Split(*pairsFlag, ",") {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+
+		base, target, ok := strings.Cut(raw, "/")
+		if !ok || base == "" || target == "" {
+			return job, fmt.Errorf("invalid pair %q, expected BASE/TARGET", raw)
+		}
+		job.Pairs = append(job.Pairs, dto.CurrencyPairDTO{
+			BaseCurrency:   strings.ToUpper(base),
+			TargetCurrency: strings.ToUpper(target),
+		})
+	}
+	if len(job.Pairs) == 0 {
+		return job, fmt.Errorf("-pairs is required")
+	}
+
+	if *fromFlag == "" {
+		return job, fmt.Errorf("-from is required")
+	}
+	from, err := time.Parse(time.DateOnly, *fromFlag)
+	if err != nil {
+		return job, fmt.Errorf("invalid -from: %w", err)
+	}
+	job.From = from
+
+	job.To = time.Now().UTC().AddDate(0, 0, -1)
+	if *toFlag != "" {
+		to, err := time.Parse(time.DateOnly, *toFlag)
+		if err != nil {
+			return job, fmt.Errorf("invalid -to: %w", err)
+		}
+		job.To = to
+	}
+
+	return job, nil
+}
@@ -5,20 +5,87 @@ import (
 	"fmt"
 	"log"
 	"log/slog"
+	"my-currency-service/currency/internal/calendar"
 	"my-currency-service/currency/internal/clients/currency"
 	"my-currency-service/currency/internal/config"
 	"my-currency-service/currency/internal/db"
+	"my-currency-service/currency/internal/events"
+	"my-currency-service/currency/internal/lock"
 	"my-currency-service/currency/internal/logger"
 	"my-currency-service/currency/internal/repository"
 	"my-currency-service/currency/internal/service"
 	"my-currency-service/currency/internal/worker"
+	"net/http"
+	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
 	"github.com/go-co-op/gocron"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
+var (
+	missingObservations = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "currency_missing_observations",
+			Help: "Publishing days in the reconciliation period without a stored rate",
+		},
+		[]string{"job", "base_currency", "target_currency"},
+	)
+
+	lastSuccess = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "currency_job_last_success_timestamp",
+			Help: "Unix time of the last successful fetch of the pair by the job",
+		},
+		[]string{"job", "base_currency", "target_currency"},
+	)
+
+	lockHeld = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "currency_worker_lock_held",
+			Help: "Whether the replica holds the lock of the job and runs it",
+		},
+		[]string{"job", "replica"},
+	)
+
+	providerCircuitState = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "currency_provider_circuit_state",
+			Help: "Circuit breaker state of the rate provider: 0 closed, 1 half-open, 2 open",
+		},
+		[]string{"provider"},
+	)
+
+	providerRetries = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "currency_provider_retries_total",
+			Help: "Total number of repeated rate provider requests",
+		},
+		[]string{"provider", "reason"},
+	)
+
+	providerRetriesAbandoned = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "currency_provider_retries_abandoned_total",
+			Help: "Total number of failed rate provider requests given up without a further attempt",
+		},
+		[]string{"provider", "reason"},
+	)
+)
+
+// metrics registration
+func init() {
+	prometheus.MustRegister(missingObservations)
+	prometheus.MustRegister(lastSuccess)
+	prometheus.MustRegister(lockHeld)
+	prometheus.MustRegister(providerCircuitState)
+	prometheus.MustRegister(providerRetries)
+	prometheus.MustRegister(providerRetriesAbandoned)
+}
+
 func main() {
 	if err := run(); err != nil {
 		log.Fatal(err.Error())
@@ -47,20 +114,77 @@ func run() error {
 		return fmt.Errorf("error creating logger: %v", err)
 	}
 
-	//client
-	client, err := currency.New(cfg.API, loggerInstance)
+	//events: сохранённые курсы передаются серверу через LISTEN/NOTIFY
+	bus := events.NewBus(events.NewPostgresNotifier(conn), loggerInstance)
+
+	calendars, err := calendar.New(cfg.Calendar)
 	if err != nil {
-		return fmt.Errorf("error creating client: %v", err)
+		return fmt.Errorf("error loading calendars: %v", err)
 	}
 
-	//svc
-	svc := service.NewCurrency(repo, client, loggerInstance)
+	currency.SetMetrics(currency.NewMetrics(providerCircuitState, providerRetries, providerRetriesAbandoned))
+
+	//svc: по сервису на каждый набор провайдеров из задач
+	services := make(map[string]worker.CurrencyService)
+	for _, job := range cfg.Worker.Jobs {
+		key := job.ProviderKey()
+		if _, ok := services[key]; ok {
+			continue
+		}
+
+		provider, err := currency.NewJobProvider(cfg.API, job, loggerInstance)
+		if err != nil {
+			return fmt.Errorf("error creating rate provider: %v", err)
+		}
+		services[key] = service.NewCurrency(cfg.Rates, repo, provider, bus, calendars, nil, loggerInstance)
+	}
 
 	//cron
 	c := gocron.NewScheduler(time.UTC)
 
-	//TODO: сделать worker модуль и от него реализовать cron
-	currencyWorker := worker.NewCurrency(cfg.Worker, svc, c, loggerInstance)
+	//lock: при нескольких репликах каждое задание выполняет одна
+	var locker worker.Locker
+	if cfg.Worker.Lock.Enabled {
+		replicaID := cfg.Worker.Lock.ReplicaID
+		if replicaID == "" {
+			if replicaID, err = os.Hostname(); err != nil {
+				return fmt.Errorf("error getting replica id: %v", err)
+			}
+		}
+
+		postgresLocker := lock.NewPostgresLocker(conn, replicaID, lockHeld, loggerInstance)
+		// Локи отпускаются при остановке, не дожидаясь обрыва сессии
+		defer postgresLocker.Close()
+		locker = postgresLocker
+	}
+
+	//history: запуски заданий пишутся в job_runs
+	var history worker.RunRecorder
+	if cfg.Worker.History.Enabled {
+		history = repo
+	}
+
+	currencyWorker, err := worker.NewCurrency(cfg.Worker, services, c, locker, history,
+		worker.NewMetrics(missingObservations, lastSuccess), loggerInstance)
+	if err != nil {
+		return fmt.Errorf("error creating worker: %v", err)
+	}
+
+	if cfg.Worker.MetricsPort != 0 {
+		go func() {
+			mux := http.NewServeMux()
+			mux.Handle("/metrics", promhttp.Handler())
+			loggerInstance.Info("Prometheus metrics server running", slog.Int("port", cfg.Worker.MetricsPort))
+			server := &http.Server{
+				Addr:              fmt.Sprintf(":%d", cfg.Worker.MetricsPort),
+				Handler:           mux,
+				ReadHeaderTimeout: 10 * time.Second,
+			}
+			if err := server.ListenAndServe(); err != nil {
+				loggerInstance.Error("error starting Prometheus metrics server", slog.Any("error", err))
+			}
+		}()
+	}
 
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
@@ -1,13 +1,22 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"my-currency-service/currency/internal/auth"
+	"my-currency-service/currency/internal/calendar"
 	currencyClient "my-currency-service/currency/internal/clients/currency"
 	"my-currency-service/currency/internal/config"
+	"my-currency-service/currency/internal/events"
+	"my-currency-service/currency/internal/gateway"
 	"my-currency-service/currency/internal/handler"
+	"my-currency-service/currency/internal/interceptor"
 	"my-currency-service/currency/internal/logger"
+	"my-currency-service/currency/internal/ratelimit"
 	"my-currency-service/currency/internal/repository"
+	"my-currency-service/currency/internal/servertls"
 	"my-currency-service/currency/internal/service"
 	"my-currency-service/pkg/currency"
 	"net"
@@ -15,24 +24,28 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"my-currency-service/currency/internal/db"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/health"
 	"google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
 )
 
 var (
+	startTime = time.Now()
+
 	requestCount = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Name: "currency_requests_total",
 			Help: "Total number of requets handled by the currency service",
 		},
-		[]string{"method"},
+		[]string{"method", "code"},
 	)
 
 	requestDuration = prometheus.NewHistogramVec(
@@ -41,12 +54,45 @@ var (
 			Help:    "Histogram of repsonse times for requests",
 			Buckets: prometheus.DefBuckets,
 		},
-		[]string{"method"},
+		[]string{"method", "code"},
+	)
+
+	rateLimitRejections = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "currency_rate_limit_rejections_total",
+			Help: "Total number of requests rejected by rate limits and daily quotas",
+		},
+		[]string{"method", "reason"},
+	)
+
+	providerCircuitState = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "currency_provider_circuit_state",
+			Help: "Circuit breaker state of the rate provider: 0 closed, 1 half-open, 2 open",
+		},
+		[]string{"provider"},
+	)
+
+	providerRetries = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "currency_provider_retries_total",
+			Help: "Total number of repeated rate provider requests",
+		},
+		[]string{"provider", "reason"},
+	)
+
+	providerRetriesAbandoned = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "currency_provider_retries_abandoned_total",
+			Help: "Total number of failed rate provider requests given up without a further attempt",
+		},
+		[]string{"provider", "reason"},
 	)
 
-	appUptime = prometheus.NewGauge(
+	appUptime = prometheus.NewGaugeFunc(
 		prometheus.GaugeOpts{Name: "currency_service_uptime_seconds",
 			Help: "Time since service start in seconds"},
+		func() float64 { return time.Since(startTime).Seconds() },
 	)
 )
 
@@ -54,7 +100,11 @@ var (
 func init() {
 	prometheus.MustRegister(requestCount)
 	prometheus.MustRegister(requestDuration)
+	prometheus.MustRegister(rateLimitRejections)
 	prometheus.MustRegister(appUptime)
+	prometheus.MustRegister(providerCircuitState)
+	prometheus.MustRegister(providerRetries)
+	prometheus.MustRegister(providerRetriesAbandoned)
 }
 
 func main() {
@@ -79,22 +129,44 @@ func main() {
 	}
 
 	repo := repository.NewPostgresRepository(conn)
-	CurrencyClient, err := currencyClient.New(cfg.API, log)
+	currencyClient.SetMetrics(currencyClient.NewMetrics(providerCircuitState, providerRetries, providerRetriesAbandoned))
+	provider, err := currencyClient.NewProvider(cfg.API, log)
 	if err != nil {
-		log.Error("error while create client", slog.Any("error", err))
+		log.Error("error while create rate provider", slog.Any("error", err))
 		os.Exit(1)
 	}
 
-	svc := service.NewCurrency(repo, CurrencyClient, log)
+	// Курсы сохраняет cron, сюда они приходят через LISTEN/NOTIFY
+	bus := events.NewBus(nil, log)
 
-	//middleware
+	listenCtx, stopListening := context.WithCancel(context.Background())
+	defer stopListening()
+	go func() {
+		if err := events.ListenPostgres(listenCtx, cfg.Database.ToDSN(), bus, log); err != nil {
+			log.Error("rate listener stopped", slog.Any("error", err))
+		}
+	}()
+
+	calendars, err := calendar.New(cfg.Calendar)
+	if err != nil {
+		log.Error("error while load calendars", slog.Any("error", err))
+		os.Exit(1)
+	}
 
-	currencyServer := handler.NewCurrencyServer(svc,
-		log,
-		requestCount,
-		requestDuration,
-		&appUptime,
-		/*metrics*/) // TODO: implement metrics
+	svc := service.NewCurrency(cfg.Rates, repo, provider, bus, calendars, repo, log)
+
+	currencyServer := handler.NewCurrencyServer(svc, log)
+
+	var authenticator *auth.Authenticator
+	if cfg.Auth.Enabled {
+		authenticator, err = auth.NewAuthenticator(cfg.Auth, log)
+		if err != nil {
+			log.Error("error while create authenticator", slog.Any("error", err))
+			os.Exit(1)
+		}
+	} else {
+		log.Warn("authentication is disabled, the API is open to anyone who can reach the port")
+	}
 
 	go func() {
 		http.Handle("/metrics", promhttp.Handler())
@@ -104,7 +176,29 @@ func main() {
 		}
 	}()
 
-	application := New(log, currencyServer, cfg.Service.ServerPort)
+	var limiter *ratelimit.Interceptor
+	if cfg.RateLimit.Enabled {
+		limiter = ratelimit.NewInterceptor(ratelimit.NewLimiter(cfg.RateLimit), rateLimitRejections)
+	}
+
+	var tlsCreds credentials.TransportCredentials
+	if cfg.Service.TLS.Enabled {
+		reloader, err := servertls.NewReloader(cfg.Service.TLS, log)
+		if err != nil {
+			log.Error("error while load TLS certificate", slog.Any("error", err))
+			os.Exit(1)
+		}
+		go reloader.Run(listenCtx)
+		tlsCreds = reloader.Credentials()
+	} else {
+		log.Warn("TLS is disabled, gRPC traffic is not encrypted")
+	}
+
+	application, err := New(log, currencyServer, authenticator, limiter, tlsCreds, cfg.Service.ServerPort, cfg.Service.HTTPPort)
+	if err != nil {
+		log.Error("error while create application", slog.Any("error", err))
+		os.Exit(1)
+	}
 
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, syscall.SIGTERM, syscall.SIGINT)
@@ -112,6 +206,9 @@ func main() {
 	errCh := make(chan error, 1)
 	// Starting Application
 	go func() { errCh <- application.Run() }()
+	if application.httpServer != nil {
+		go func() { errCh <- application.RunGateway() }()
+	}
 	select {
 	case sig := <-stop:
 		// Graceful shutdown
@@ -122,6 +219,10 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Подписки закрываются заранее, иначе GracefulStop ждёт их бесконечно
+	stopListening()
+	bus.Close()
+
 	application.Stop()
 	log.Info("application stopped")
 
@@ -131,17 +232,29 @@ type App struct {
 	log            *slog.Logger
 	currencyServer *handler.CurrencyServer
 	gRPCServer     *grpc.Server
+	httpServer     *http.Server
 	port           int
+	httpPort       int
 }
 
-// New creates new gRPC server app.
+// New creates new gRPC server app. Nil tlsCreds leaves the listener in
+// plaintext. A non-zero httpPort adds the HTTP/JSON gateway; its calls
+// reach the gRPC server through an in-process connection.
 func New(
 	log *slog.Logger,
 	currencyServer *handler.CurrencyServer,
-	//authService authgrpc.Auth,
+	authenticator *auth.Authenticator,
+	limiter *ratelimit.Interceptor,
+	tlsCreds credentials.TransportCredentials,
 	port int,
-) *App {
-	gRPCServer := grpc.NewServer()
+	httpPort int,
+) (*App, error) {
+	//middleware
+	opts := interceptor.ServerOptions(log, interceptor.NewMetrics(requestCount, requestDuration), authenticator, limiter)
+	if tlsCreds != nil {
+		opts = append(opts, grpc.Creds(tlsCreds))
+	}
+	gRPCServer := grpc.NewServer(opts...)
 
 	currency.RegisterCurrencyServiceServer(gRPCServer, currencyServer)
 
@@ -150,14 +263,33 @@ func New(
 	healthServer.SetServingStatus("", grpc_health_v1.HealthCheckResponse_SERVING)
 	reflection.Register(gRPCServer)
 
-	//authgrpc.Register(gRPCServer, authService) //TODO: авторизация
-
-	return &App{
+	app := &App{
 		log:            log,
 		currencyServer: currencyServer,
 		gRPCServer:     gRPCServer,
 		port:           port,
+		httpPort:       httpPort,
+	}
+
+	if httpPort != 0 {
+		conn, err := gateway.InProcess(gRPCServer)
+		if err != nil {
+			return nil, err
+		}
+
+		gw, err := gateway.New(conn, log)
+		if err != nil {
+			return nil, err
+		}
+
+		app.httpServer = &http.Server{
+			Addr:              fmt.Sprintf(":%d", httpPort),
+			Handler:           gw,
+			ReadHeaderTimeout: 10 * time.Second,
+		}
 	}
+
+	return app, nil
 }
 
 func (a *App) Run() error {
@@ -182,6 +314,24 @@ func (a *App) Run() error {
 	return nil
 }
 
+// RunGateway serves the HTTP/JSON gateway.
+func (a *App) RunGateway() error {
+	const op = "grpcapp.RunGateway"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int("port", a.httpPort),
+	)
+
+	log.Info("HTTP gateway is running", slog.String("openapi", gateway.OpenAPIPath))
+
+	if err := a.httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
 // Stop stops gRPC server
 func (a *App) Stop() {
 	const op = "grpcapp.Stop"
@@ -189,5 +339,11 @@ func (a *App) Stop() {
 	a.log.With(slog.String("op", op)).
 		Info("stopping gRPC server", slog.Int("port", a.port))
 
+	if a.httpServer != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_ = a.httpServer.Shutdown(ctx)
+	}
+
 	a.gRPCServer.GracefulStop()
 }
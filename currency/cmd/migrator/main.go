@@ -1,13 +1,21 @@
 package main
 
 import (
+	"context"
 	"database/sql"
+	"flag"
 	"fmt"
 	"my-currency-service/currency/internal/config"
 	"my-currency-service/currency/internal/db"
+	"my-currency-service/currency/internal/iso4217"
 	migrator "my-currency-service/currency/internal/migrations"
 )
 
+// Флаг объявляется до config.MustLoad: он сам вызывает flag.Parse
+var legacyTargetFlag = flag.String("legacy-target", "",
+	"target currency the first worker was configured with (worker.currency_pair.target_currency); "+
+		"converts its date keyed rates from exchange_rates_legacy into rates with this base currency")
+
 func main() {
 
 	// Recover Migrator
@@ -16,22 +24,56 @@ func main() {
 	// Get the DB instance
 	cfg := config.MustLoad()
 
+	if *legacyTargetFlag != "" && !iso4217.Valid(*legacyTargetFlag) {
+		panic(fmt.Sprintf("unknown legacy target currency %q", *legacyTargetFlag))
+	}
+
 	conn, err := db.NewDatabaseConnection(cfg.Database)
 	if err != nil {
 		panic(err)
 	}
 
+	// Мигратор закрывает соединение сам
+	err = m.ApplyMigrations(conn)
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Printf("Migrations applied!!")
+
+	conn, err = db.NewDatabaseConnection(cfg.Database)
+	if err != nil {
+		panic(err)
+	}
+
 	defer func(conn *sql.DB) {
-		err := conn.Close()
-		if err != nil {
+		_ = conn.Close()
+	}(conn)
 
+	if err := convertLegacyRates(context.Background(), conn, *legacyTargetFlag); err != nil {
+		panic(err)
+	}
+}
+
+// convertLegacyRates converts the date keyed rates of the first worker when
+// their target currency is given, and otherwise reports that they are left.
+func convertLegacyRates(ctx context.Context, conn *sql.DB, target string) error {
+	if target != "" {
+		converted, err := migrator.ConvertLegacyRates(ctx, conn, target)
+		if err != nil {
+			return err
 		}
-	}(conn)
+		fmt.Printf("\nConverted %d legacy rates with base %s\n", converted, target)
+	}
 
-	err = m.ApplyMigrations(conn)
+	left, err := migrator.CountLegacyRates(ctx, conn)
 	if err != nil {
-		panic(err)
+		return err
+	}
+	if left > 0 {
+		fmt.Printf("\n%d legacy rates in exchange_rates_legacy have no target currency; "+
+			"run the migrator with --legacy-target set to worker.currency_pair.target_currency of the old config\n", left)
 	}
 
-	fmt.Printf("Migrations applied!!")
+	return nil
 }
@@ -0,0 +1,86 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"my-currency-service/currency/internal/config"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const apiKeyHashPrefix = "sha256:"
+
+// HashAPIKey returns the hash of key in the form stored in the config.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return apiKeyHashPrefix + hex.EncodeToString(sum[:])
+}
+
+type apiKey struct {
+	name   string
+	hash   []byte
+	scopes []string
+}
+
+// APIKeys checks static API keys against their SHA-256 hashes.
+type APIKeys struct {
+	keys []apiKey
+}
+
+// NewAPIKeys loads keys from the config and, if set, from the keys file.
+func NewAPIKeys(cfg config.AuthConfig) (*APIKeys, error) {
+	entries := cfg.APIKeys
+	if cfg.APIKeysFile != "" {
+		data, err := os.ReadFile(cfg.APIKeysFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read API keys file: %w", err)
+		}
+
+		var fileEntries []config.APIKeyConfig
+		if err := yaml.Unmarshal(data, &fileEntries); err != nil {
+			return nil, fmt.Errorf("failed to parse API keys file %s: %w", cfg.APIKeysFile, err)
+		}
+		entries = append(entries, fileEntries...)
+	}
+
+	keys := &APIKeys{keys: make([]apiKey, 0, len(entries))}
+	for _, entry := range entries {
+		hexHash, ok := strings.CutPrefix(entry.Hash, apiKeyHashPrefix)
+		if !ok {
+			return nil, fmt.Errorf("API key %q: hash must start with %q", entry.Name, apiKeyHashPrefix)
+		}
+		hash, err := hex.DecodeString(hexHash)
+		if err != nil || len(hash) != sha256.Size {
+			return nil, fmt.Errorf("API key %q: invalid SHA-256 hash", entry.Name)
+		}
+		if entry.Name == "" {
+			return nil, fmt.Errorf("API key with hash %s has no name", entry.Hash)
+		}
+
+		keys.keys = append(keys.keys, apiKey{name: entry.Name, hash: hash, scopes: entry.Scopes})
+	}
+
+	return keys, nil
+}
+
+// Authenticate returns the identity of key. Every stored hash is compared
+// so that the time taken does not depend on which key matched.
+func (k *APIKeys) Authenticate(key string) (Identity, error) {
+	sum := sha256.Sum256([]byte(key))
+
+	var found *apiKey
+	for i := range k.keys {
+		if subtle.ConstantTimeCompare(sum[:], k.keys[i].hash) == 1 {
+			found = &k.keys[i]
+		}
+	}
+	if found == nil {
+		return Identity{}, fmt.Errorf("%w: unknown API key", ErrInvalidCredentials)
+	}
+
+	return Identity{Subject: found.name, Method: "api_key", Scopes: found.scopes}, nil
+}
@@ -0,0 +1,46 @@
+// Package auth authenticates gRPC callers with API keys, JWTs or client
+// certificates and checks the scopes required by each method.
+package auth
+
+import (
+	"context"
+	"errors"
+	"slices"
+)
+
+// Scopes granted to callers.
+const (
+	ScopeRead  = "rates:read"
+	ScopeAdmin = "rates:admin"
+)
+
+var (
+	ErrNoCredentials      = errors.New("no credentials")
+	ErrInvalidCredentials = errors.New("invalid credentials")
+)
+
+// Identity is an authenticated caller.
+type Identity struct {
+	Subject string
+	// Method is how the caller was authenticated: "api_key", "jwt" or "mtls".
+	Method string
+	Scopes []string
+}
+
+// HasScope reports whether the identity was granted scope. The admin
+// scope implies every other scope.
+func (i Identity) HasScope(scope string) bool {
+	return slices.Contains(i.Scopes, scope) || slices.Contains(i.Scopes, ScopeAdmin)
+}
+
+type identityKey struct{}
+
+func NewContext(ctx context.Context, identity Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+// FromContext returns the caller identity set by the auth interceptor.
+func FromContext(ctx context.Context) (Identity, bool) {
+	identity, ok := ctx.Value(identityKey{}).(Identity)
+	return identity, ok
+}
@@ -0,0 +1,264 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"log/slog"
+	"math/big"
+	"my-currency-service/currency/internal/config"
+	"my-currency-service/pkg/currency"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	testAPIKey = "s3cr3t-key"
+	testSecret = "hs256-secret"
+	testKid    = "key-1"
+)
+
+func writeJWKS(t *testing.T, key *rsa.PublicKey) string {
+	t.Helper()
+
+	jwks := map[string]any{"keys": []map[string]string{{
+		"kty": "RSA",
+		"kid": testKid,
+		"use": "sig",
+		"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}}}
+	data, err := json.Marshal(jwks)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	return path
+}
+
+func newTestAuthenticator(t *testing.T) (*Authenticator, *rsa.PrivateKey) {
+	t.Helper()
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	authenticator, err := NewAuthenticator(config.AuthConfig{
+		Enabled: true,
+		APIKeys: []config.APIKeyConfig{
+			{Name: "dashboard", Hash: HashAPIKey(testAPIKey), Scopes: []string{ScopeRead}},
+		},
+		JWT: config.JWTConfig{
+			HS256Secret: testSecret,
+			JWKSFile:    writeJWKS(t, &privateKey.PublicKey),
+			Issuer:      "https://auth.example.com",
+			Audience:    "currency-service",
+		},
+		ClientCerts: []config.ClientCertConfig{
+			{Subject: "billing", Scopes: []string{ScopeRead}},
+		},
+	}, slog.Default())
+	require.NoError(t, err)
+
+	return authenticator, privateKey
+}
+
+func signToken(t *testing.T, method jwt.SigningMethod, key any, kid string, claims jwt.MapClaims) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+	return signed
+}
+
+func validClaims(scope string) jwt.MapClaims {
+	return jwt.MapClaims{
+		"sub":   "reporting",
+		"iss":   "https://auth.example.com",
+		"aud":   "currency-service",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"scope": scope,
+	}
+}
+
+func incoming(pairs ...string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs(pairs...))
+}
+
+func TestAuthorize_APIKey(t *testing.T) {
+	authenticator, _ := newTestAuthenticator(t)
+
+	ctx, err := authenticator.Authorize(incoming("x-api-key", testAPIKey), currency.CurrencyService_GetRate_FullMethodName)
+	require.NoError(t, err)
+
+	identity, ok := FromContext(ctx)
+	require.True(t, ok)
+	assert.Equal(t, "dashboard", identity.Subject)
+	assert.Equal(t, "api_key", identity.Method)
+
+	_, err = authenticator.Authorize(incoming("x-api-key", "wrong"), currency.CurrencyService_GetRate_FullMethodName)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestAuthorize_JWT(t *testing.T) {
+	authenticator, privateKey := newTestAuthenticator(t)
+
+	tests := []struct {
+		name  string
+		token string
+		code  codes.Code
+	}{
+		{"HS256", signToken(t, jwt.SigningMethodHS256, []byte(testSecret), "", validClaims(ScopeRead)), codes.OK},
+		{"RS256 from JWKS", signToken(t, jwt.SigningMethodRS256, privateKey, testKid, validClaims(ScopeRead)), codes.OK},
+		{"RS256 without kid", signToken(t, jwt.SigningMethodRS256, privateKey, "", validClaims(ScopeRead)), codes.OK},
+		{"unknown kid", signToken(t, jwt.SigningMethodRS256, privateKey, "other", validClaims(ScopeRead)), codes.Unauthenticated},
+		{"wrong secret", signToken(t, jwt.SigningMethodHS256, []byte("other"), "", validClaims(ScopeRead)), codes.Unauthenticated},
+		{"unsupported algorithm", signToken(t, jwt.SigningMethodHS512, []byte(testSecret), "", validClaims(ScopeRead)), codes.Unauthenticated},
+		{"expired", signToken(t, jwt.SigningMethodHS256, []byte(testSecret), "", func() jwt.MapClaims {
+			c := validClaims(ScopeRead)
+			c["exp"] = time.Now().Add(-time.Minute).Unix()
+			return c
+		}()), codes.Unauthenticated},
+		{"wrong audience", signToken(t, jwt.SigningMethodHS256, []byte(testSecret), "", func() jwt.MapClaims {
+			c := validClaims(ScopeRead)
+			c["aud"] = "other-service"
+			return c
+		}()), codes.Unauthenticated},
+		{"missing scope", signToken(t, jwt.SigningMethodHS256, []byte(testSecret), "", validClaims("profile")), codes.PermissionDenied},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, err := authenticator.Authorize(incoming("authorization", "Bearer "+tt.token), currency.CurrencyService_Convert_FullMethodName)
+
+			require.Equal(t, tt.code, status.Code(err))
+			if tt.code == codes.OK {
+				identity, _ := FromContext(ctx)
+				assert.Equal(t, "reporting", identity.Subject)
+				assert.Equal(t, "jwt", identity.Method)
+			}
+		})
+	}
+}
+
+// withClientCert returns ctx of a connection whose client certificate
+// with commonName passed verification.
+func withClientCert(ctx context.Context, commonName string) context.Context {
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: commonName}}
+	return peer.NewContext(ctx, &peer.Peer{
+		Addr: &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 40000},
+		AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{
+			PeerCertificates: []*x509.Certificate{cert},
+			VerifiedChains:   [][]*x509.Certificate{{cert}},
+		}},
+	})
+}
+
+func TestAuthorize_ClientCert(t *testing.T) {
+	authenticator, _ := newTestAuthenticator(t)
+
+	ctx, err := authenticator.Authorize(withClientCert(context.Background(), "billing"), currency.CurrencyService_GetRate_FullMethodName)
+	require.NoError(t, err)
+
+	identity, _ := FromContext(ctx)
+	assert.Equal(t, Identity{Subject: "billing", Method: "mtls", Scopes: []string{ScopeRead}}, identity)
+
+	// Ключ в заголовке важнее сертификата
+	ctx, err = authenticator.Authorize(withClientCert(incoming("x-api-key", testAPIKey), "billing"), currency.CurrencyService_GetRate_FullMethodName)
+	require.NoError(t, err)
+	identity, _ = FromContext(ctx)
+	assert.Equal(t, "dashboard", identity.Subject)
+
+	// Сертификат подписан CA, но клиента нет в client_certs
+	_, err = authenticator.Authorize(withClientCert(context.Background(), "unknown"), currency.CurrencyService_GetRate_FullMethodName)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = authenticator.Authorize(withClientCert(context.Background(), "billing"), "/currency.CurrencyService/Unknown")
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+func TestPeerIdentity_Unverified(t *testing.T) {
+	// Сертификат без проверенной цепочки не даёт личности
+	ctx := peer.NewContext(context.Background(), &peer.Peer{
+		AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{
+			PeerCertificates: []*x509.Certificate{{Subject: pkix.Name{CommonName: "billing"}}},
+		}},
+	})
+
+	_, ok := PeerIdentity(ctx)
+	assert.False(t, ok)
+}
+
+func TestAuthorize_Scopes(t *testing.T) {
+	authenticator, _ := newTestAuthenticator(t)
+
+	// Незарегистрированный метод требует admin
+	_, err := authenticator.Authorize(incoming("x-api-key", testAPIKey), "/currency.CurrencyService/Unknown")
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	admin := signToken(t, jwt.SigningMethodHS256, []byte(testSecret), "", validClaims(ScopeAdmin))
+	_, err = authenticator.Authorize(incoming("authorization", "Bearer "+admin), "/currency.CurrencyService/Unknown")
+	assert.NoError(t, err)
+
+	// admin включает чтение
+	_, err = authenticator.Authorize(incoming("authorization", "Bearer "+admin), currency.CurrencyService_GetRate_FullMethodName)
+	assert.NoError(t, err)
+}
+
+func TestAuthorize_MissingCredentials(t *testing.T) {
+	authenticator, _ := newTestAuthenticator(t)
+
+	_, err := authenticator.Authorize(context.Background(), currency.CurrencyService_GetRate_FullMethodName)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = authenticator.Authorize(incoming("authorization", "Basic dXNlcjpwYXNz"), currency.CurrencyService_GetRate_FullMethodName)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// Пробы health доступны без ключа
+	_, err = authenticator.Authorize(context.Background(), "/grpc.health.v1.Health/Check")
+	assert.NoError(t, err)
+}
+
+func TestNewAPIKeys_File(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(
+		"- name: \"billing\"\n  hash: \""+HashAPIKey("billing-key")+"\"\n  scopes: [\"rates:admin\"]\n"), 0o600))
+
+	keys, err := NewAPIKeys(config.AuthConfig{APIKeysFile: path})
+	require.NoError(t, err)
+
+	identity, err := keys.Authenticate("billing-key")
+	require.NoError(t, err)
+	assert.Equal(t, "billing", identity.Subject)
+	assert.True(t, identity.HasScope(ScopeRead))
+}
+
+func TestNewAPIKeys_InvalidHash(t *testing.T) {
+	_, err := NewAPIKeys(config.AuthConfig{APIKeys: []config.APIKeyConfig{{Name: "plain", Hash: testAPIKey}}})
+	assert.Error(t, err)
+}
+
+func TestNewAuthenticator_NothingConfigured(t *testing.T) {
+	_, err := NewAuthenticator(config.AuthConfig{Enabled: true}, slog.Default())
+	assert.Error(t, err)
+}
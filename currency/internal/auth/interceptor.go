@@ -0,0 +1,165 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"my-currency-service/currency/internal/config"
+	"my-currency-service/pkg/currency"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Заголовки с учётными данными
+const (
+	apiKeyHeader        = "x-api-key"
+	authorizationHeader = "authorization"
+	bearerPrefix        = "bearer "
+)
+
+// methodScopes lists the scope required by each method. Methods missing
+// here require ScopeAdmin, so a new RPC is closed until it is listed.
+var methodScopes = map[string]string{
+	currency.CurrencyService_GetRate_FullMethodName:        ScopeRead,
+	currency.CurrencyService_Convert_FullMethodName:        ScopeRead,
+	currency.CurrencyService_GetLatestRate_FullMethodName:  ScopeRead,
+	currency.CurrencyService_BatchGetRates_FullMethodName:  ScopeRead,
+	currency.CurrencyService_SubscribeRates_FullMethodName: ScopeRead,
+	currency.CurrencyService_GetGapReport_FullMethodName:   ScopeAdmin,
+	currency.CurrencyService_GetJobRuns_FullMethodName:     ScopeAdmin,
+
+	"/grpc.reflection.v1.ServerReflection/ServerReflectionInfo":      ScopeRead,
+	"/grpc.reflection.v1alpha.ServerReflection/ServerReflectionInfo": ScopeRead,
+}
+
+// Сервисы без аутентификации: пробы балансировщика не передают ключей
+var publicServices = []string{"/grpc.health.v1.Health/"}
+
+// Authenticator is a gRPC interceptor that authenticates callers and
+// checks method scopes. The identity is put into the context.
+type Authenticator struct {
+	apiKeys     *APIKeys
+	jwts        *JWTs
+	clientCerts *ClientCerts
+	logger      *slog.Logger
+}
+
+func NewAuthenticator(cfg config.AuthConfig, logger *slog.Logger) (*Authenticator, error) {
+	apiKeys, err := NewAPIKeys(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	jwts, err := NewJWTs(cfg.JWT)
+	if err != nil {
+		return nil, err
+	}
+
+	clientCerts, err := NewClientCerts(cfg.ClientCerts)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(apiKeys.keys) == 0 && !jwts.Configured() && len(clientCerts.scopes) == 0 {
+		return nil, errors.New("auth is enabled but no API keys, JWT keys or client certificates are configured")
+	}
+
+	return &Authenticator{
+		apiKeys:     apiKeys,
+		jwts:        jwts,
+		clientCerts: clientCerts,
+		logger:      logger,
+	}, nil
+}
+
+func (a *Authenticator) Unary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := a.Authorize(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func (a *Authenticator) Stream() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := a.Authorize(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// Authorize authenticates the caller of fullMethod from the incoming
+// metadata and returns ctx with the identity.
+func (a *Authenticator) Authorize(ctx context.Context, fullMethod string) (context.Context, error) {
+	for _, prefix := range publicServices {
+		if strings.HasPrefix(fullMethod, prefix) {
+			return ctx, nil
+		}
+	}
+
+	identity, err := a.authenticate(ctx)
+	if err != nil {
+		a.logger.WarnContext(ctx, "authentication failed",
+			slog.String("method", fullMethod),
+			slog.Any("error", err))
+		return nil, status.Error(codes.Unauthenticated, "valid API key, bearer token or client certificate is required")
+	}
+
+	scope, ok := methodScopes[fullMethod]
+	if !ok {
+		scope = ScopeAdmin
+	}
+	if !identity.HasScope(scope) {
+		a.logger.WarnContext(ctx, "permission denied",
+			slog.String("method", fullMethod),
+			slog.String("subject", identity.Subject),
+			slog.String("scope", scope))
+		return nil, status.Errorf(codes.PermissionDenied, "scope %s is required", scope)
+	}
+
+	return NewContext(ctx, identity), nil
+}
+
+func (a *Authenticator) authenticate(ctx context.Context) (Identity, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	if keys := md.Get(apiKeyHeader); len(keys) > 0 {
+		return a.apiKeys.Authenticate(keys[0])
+	}
+
+	if values := md.Get(authorizationHeader); len(values) > 0 {
+		if len(values[0]) <= len(bearerPrefix) || !strings.EqualFold(values[0][:len(bearerPrefix)], bearerPrefix) {
+			return Identity{}, fmt.Errorf("%w: unsupported authorization scheme", ErrInvalidCredentials)
+		}
+		if !a.jwts.Configured() {
+			return Identity{}, fmt.Errorf("%w: bearer tokens are not accepted", ErrInvalidCredentials)
+		}
+		return a.jwts.Authenticate(values[0][len(bearerPrefix):])
+	}
+
+	// Сертификат проверяется последним: ключ или токен в заголовке клиент передал явно
+	if identity, ok := PeerIdentity(ctx); ok {
+		return a.clientCerts.Authenticate(identity)
+	}
+
+	return Identity{}, ErrNoCredentials
+}
+
+// serverStream replaces the context of a stream with the authorized one.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,179 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"my-currency-service/currency/internal/config"
+	"os"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// JWTs verifies HS256 and RS256 tokens against a shared secret and keys
+// from a local JWKS file.
+type JWTs struct {
+	hmacKeys map[string][]byte
+	rsaKeys  map[string]*rsa.PublicKey
+	parser   *jwt.Parser
+}
+
+// claims are the registered claims plus the scopes, either an OAuth2
+// space-separated "scope" string or an "scp" array.
+type claims struct {
+	jwt.RegisteredClaims
+	Scope string   `json:"scope"`
+	Scp   []string `json:"scp"`
+}
+
+func NewJWTs(cfg config.JWTConfig) (*JWTs, error) {
+	j := &JWTs{
+		hmacKeys: make(map[string][]byte),
+		rsaKeys:  make(map[string]*rsa.PublicKey),
+	}
+
+	if cfg.HS256Secret != "" {
+		j.hmacKeys[""] = []byte(cfg.HS256Secret)
+	}
+	if cfg.JWKSFile != "" {
+		if err := j.loadJWKS(cfg.JWKSFile); err != nil {
+			return nil, err
+		}
+	}
+
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg(), jwt.SigningMethodRS256.Alg()}),
+		jwt.WithExpirationRequired(),
+	}
+	if cfg.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(cfg.Issuer))
+	}
+	if cfg.Audience != "" {
+		opts = append(opts, jwt.WithAudience(cfg.Audience))
+	}
+	j.parser = jwt.NewParser(opts...)
+
+	return j, nil
+}
+
+// Configured reports whether any verification key is set.
+func (j *JWTs) Configured() bool {
+	return len(j.hmacKeys) > 0 || len(j.rsaKeys) > 0
+}
+
+// Authenticate verifies token and returns the identity of its subject.
+func (j *JWTs) Authenticate(token string) (Identity, error) {
+	var c claims
+	if _, err := j.parser.ParseWithClaims(token, &c, j.key); err != nil {
+		return Identity{}, fmt.Errorf("%w: %w", ErrInvalidCredentials, err)
+	}
+	if c.Subject == "" {
+		return Identity{}, fmt.Errorf("%w: token has no subject", ErrInvalidCredentials)
+	}
+
+	scopes := c.Scp
+	if c.Scope != "" {
+		scopes = append(scopes, strings.Fields(c.Scope)...)
+	}
+
+	return Identity{Subject: c.Subject, Method: "jwt", Scopes: scopes}, nil
+}
+
+// key selects the verification key by algorithm and kid. A token without
+// kid is accepted when exactly one key of its algorithm is configured.
+func (j *JWTs) key(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	switch token.Method.Alg() {
+	case jwt.SigningMethodHS256.Alg():
+		return lookupKey(j.hmacKeys, kid)
+	case jwt.SigningMethodRS256.Alg():
+		return lookupKey(j.rsaKeys, kid)
+	}
+	return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+}
+
+func lookupKey[K any](keys map[string]K, kid string) (K, error) {
+	if key, ok := keys[kid]; ok {
+		return key, nil
+	}
+	if kid == "" && len(keys) == 1 {
+		for _, key := range keys {
+			return key, nil
+		}
+	}
+
+	var zero K
+	return zero, fmt.Errorf("no key for kid %q", kid)
+}
+
+// jwk is a JSON Web Key (RFC 7517) limited to RSA and symmetric keys.
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	K   string `json:"k"`
+}
+
+func (j *JWTs) loadJWKS(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read JWKS file: %w", err)
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return fmt.Errorf("failed to parse JWKS file %s: %w", path, err)
+	}
+
+	for _, key := range set.Keys {
+		// Ключи шифрования для проверки подписи не используются
+		if key.Use != "" && key.Use != "sig" {
+			continue
+		}
+
+		switch key.Kty {
+		case "RSA":
+			publicKey, err := key.rsaPublicKey()
+			if err != nil {
+				return fmt.Errorf("JWKS key %q: %w", key.Kid, err)
+			}
+			j.rsaKeys[key.Kid] = publicKey
+		case "oct":
+			secret, err := base64.RawURLEncoding.DecodeString(key.K)
+			if err != nil || len(secret) == 0 {
+				return fmt.Errorf("JWKS key %q: invalid k", key.Kid)
+			}
+			j.hmacKeys[key.Kid] = secret
+		default:
+			return fmt.Errorf("JWKS key %q: unsupported key type %q", key.Kid, key.Kty)
+		}
+	}
+
+	return nil
+}
+
+func (key jwk) rsaPublicKey() (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(key.N)
+	if err != nil || len(n) == 0 {
+		return nil, errors.New("invalid modulus")
+	}
+	e, err := base64.RawURLEncoding.DecodeString(key.E)
+	if err != nil || len(e) == 0 || len(e) > 4 {
+		return nil, errors.New("invalid exponent")
+	}
+
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(n),
+		E: int(new(big.Int).SetBytes(e).Int64()),
+	}, nil
+}
@@ -0,0 +1,69 @@
+package auth
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"my-currency-service/currency/internal/config"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+// PeerIdentity returns the identity of a caller whose client certificate
+// was verified during the TLS handshake. The subject is the certificate
+// CommonName; scopes are granted by the authenticator.
+func PeerIdentity(ctx context.Context) (Identity, bool) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return Identity{}, false
+	}
+
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.VerifiedChains) == 0 || len(tlsInfo.State.VerifiedChains[0]) == 0 {
+		return Identity{}, false
+	}
+
+	return Identity{Subject: certSubject(tlsInfo.State.VerifiedChains[0][0]), Method: "mtls"}, true
+}
+
+// certSubject returns the CommonName or, if it is empty, the whole
+// distinguished name.
+func certSubject(cert *x509.Certificate) string {
+	if cert.Subject.CommonName != "" {
+		return cert.Subject.CommonName
+	}
+	return cert.Subject.String()
+}
+
+// ClientCerts grants scopes to callers authenticated by a client certificate.
+type ClientCerts struct {
+	scopes map[string][]string
+}
+
+func NewClientCerts(entries []config.ClientCertConfig) (*ClientCerts, error) {
+	certs := &ClientCerts{scopes: make(map[string][]string, len(entries))}
+	for _, entry := range entries {
+		if entry.Subject == "" {
+			return nil, fmt.Errorf("client certificate entry has no subject")
+		}
+		if _, ok := certs.scopes[entry.Subject]; ok {
+			return nil, fmt.Errorf("client certificate %q is listed twice", entry.Subject)
+		}
+		certs.scopes[entry.Subject] = entry.Scopes
+	}
+
+	return certs, nil
+}
+
+// Authenticate returns the identity with the scopes of its subject. A
+// certificate signed by the CA but not listed is rejected.
+func (c *ClientCerts) Authenticate(identity Identity) (Identity, error) {
+	scopes, ok := c.scopes[identity.Subject]
+	if !ok {
+		return Identity{}, fmt.Errorf("%w: client certificate %q is not allowed", ErrInvalidCredentials, identity.Subject)
+	}
+
+	identity.Scopes = scopes
+	return identity, nil
+}
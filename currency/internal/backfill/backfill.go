@@ -0,0 +1,194 @@
+// Package backfill loads historical rates period by period and remembers
+// finished windows, so an interrupted run resumes where it stopped.
+package backfill
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"my-currency-service/currency/internal/clients/currency"
+	"my-currency-service/currency/internal/dto"
+	"time"
+)
+
+const dateLayout = "2006-01-02"
+
+// Окна по умолчанию: ECB и ЦБ отдают период одним запросом, JSON-провайдер
+// запрашивает каждый день отдельно и ограничен 366 днями
+var defaultChunkDays = map[string]int{
+	currency.ProviderECB:  366,
+	currency.ProviderCBR:  366,
+	currency.ProviderJSON: 31,
+}
+
+const fallbackChunkDays = 31
+
+// ChunkDays returns the window size suited to the provider.
+func ChunkDays(provider string) int {
+	if days, ok := defaultChunkDays[provider]; ok {
+		return days
+	}
+	return fallbackChunkDays
+}
+
+type Service interface {
+	BackfillCurrencyRates(ctx context.Context, req *dto.CurrencyRequestDTO) (int, error)
+}
+
+// Window is an inclusive range of days.
+type Window struct {
+	From time.Time
+	To   time.Time
+}
+
+func (w Window) String() string {
+	return w.From.Format(dateLayout) + ".." + w.To.Format(dateLayout)
+}
+
+// Windows splits [from, to] into windows of at most days days.
+func Windows(from, to time.Time, days int) []Window {
+	from, to = truncateDay(from), truncateDay(to)
+	if days <= 0 || from.After(to) {
+		return nil
+	}
+
+	var windows []Window
+	for start := from; !start.After(to); start = start.AddDate(0, 0, days) {
+		end := start.AddDate(0, 0, days-1)
+		if end.After(to) {
+			end = to
+		}
+		windows = append(windows, Window{From: start, To: end})
+	}
+
+	return windows
+}
+
+// Job is a backfill of several pairs over one period.
+type Job struct {
+	Provider  string
+	Pairs     []dto.CurrencyPairDTO
+	From      time.Time
+	To        time.Time
+	ChunkDays int
+	// Время на одно окно
+	Timeout time.Duration
+}
+
+// Report sums up a run.
+type Report struct {
+	Windows int
+	Skipped int
+	Failed  int
+	Records int
+}
+
+// Runner fetches the windows of a job one after another and records each
+// finished window in the state.
+type Runner struct {
+	service Service
+	state   *State
+	logger  *slog.Logger
+}
+
+func NewRunner(service Service, state *State, logger *slog.Logger) *Runner {
+	return &Runner{
+		service: service,
+		state:   state,
+		logger:  logger,
+	}
+}
+
+// Run loads every window of the job that the state does not mark as done.
+// A failed window is logged and left for the next run; cancelling ctx
+// stops the run after the window in progress.
+func (r *Runner) Run(ctx context.Context, job Job) (Report, error) {
+	if len(job.Pairs) == 0 {
+		return Report{}, errors.New("no pairs to backfill")
+	}
+	if job.From.After(job.To) {
+		return Report{}, fmt.Errorf("from %s is after to %s", job.From.Format(dateLayout), job.To.Format(dateLayout))
+	}
+
+	chunkDays := job.ChunkDays
+	if chunkDays <= 0 {
+		chunkDays = ChunkDays(job.Provider)
+	}
+	windows := Windows(job.From, job.To, chunkDays)
+
+	report := Report{Windows: len(windows) * len(job.Pairs)}
+	var processed int
+
+	for _, pair := range job.Pairs {
+		key := pairKey(job.Provider, pair)
+
+		for _, window := range windows {
+			if err := ctx.Err(); err != nil {
+				return report, err
+			}
+			processed++
+
+			if r.state.Done(key, window) {
+				report.Skipped++
+				continue
+			}
+
+			records, err := r.fetch(ctx, job, pair, window)
+			if err != nil {
+				if ctx.Err() != nil {
+					return report, ctx.Err()
+				}
+
+				report.Failed++
+				r.logger.Error("failed to backfill window",
+					slog.String("pair", key),
+					slog.String("window", window.String()),
+					slog.Any("error", err))
+				continue
+			}
+			report.Records += records
+
+			if err := r.state.MarkDone(key, window); err != nil {
+				return report, err
+			}
+
+			r.logger.Info("backfill progress",
+				slog.String("pair", key),
+				slog.String("window", window.String()),
+				slog.Int("records", records),
+				slog.String("progress", fmt.Sprintf("%d/%d", processed, report.Windows)))
+		}
+	}
+
+	if report.Failed > 0 {
+		return report, fmt.Errorf("%d of %d windows failed, run again to retry them", report.Failed, report.Windows)
+	}
+
+	return report, nil
+}
+
+func (r *Runner) fetch(ctx context.Context, job Job, pair dto.CurrencyPairDTO, window Window) (int, error) {
+	if job.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, job.Timeout)
+		defer cancel()
+	}
+
+	return r.service.BackfillCurrencyRates(ctx, &dto.CurrencyRequestDTO{
+		BaseCurrency:   pair.BaseCurrency,
+		TargetCurrency: pair.TargetCurrency,
+		DateFrom:       window.From,
+		DateTo:         window.To,
+	})
+}
+
+// pairKey identifies a pair in the state: the same pair from another
+// provider is a separate backfill.
+func pairKey(provider string, pair dto.CurrencyPairDTO) string {
+	return provider + ":" + pair.BaseCurrency + "/" + pair.TargetCurrency
+}
+
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,157 @@
+package backfill
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"my-currency-service/currency/internal/dto"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func date(s string) time.Time {
+	t, err := time.Parse(dateLayout, s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// fakeService запоминает окна и падает на окнах из failOn
+type fakeService struct {
+	calls  []string
+	failOn map[string]bool
+	cancel context.CancelFunc
+}
+
+func (s *fakeService) BackfillCurrencyRates(_ context.Context, req *dto.CurrencyRequestDTO) (int, error) {
+	window := Window{From: req.DateFrom, To: req.DateTo}.String()
+	s.calls = append(s.calls, req.BaseCurrency+"/"+req.TargetCurrency+" "+window)
+
+	if s.cancel != nil && len(s.calls) == 2 {
+		s.cancel()
+		return 0, context.Canceled
+	}
+	if s.failOn[window] {
+		return 0, errors.New("provider is down")
+	}
+	return 10, nil
+}
+
+func newTestState(t *testing.T) (*State, string) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "state.json")
+	state, err := LoadState(path)
+	require.NoError(t, err)
+	return state, path
+}
+
+var testJob = Job{
+	Provider:  "ecb",
+	Pairs:     []dto.CurrencyPairDTO{{BaseCurrency: "EUR", TargetCurrency: "USD"}, {BaseCurrency: "EUR", TargetCurrency: "GBP"}},
+	From:      date("2024-01-01"),
+	To:        date("2024-03-15"),
+	ChunkDays: 31,
+}
+
+func TestWindows(t *testing.T) {
+	windows := Windows(date("2024-01-01"), date("2024-03-15"), 31)
+
+	require.Len(t, windows, 3)
+	assert.Equal(t, "2024-01-01..2024-01-31", windows[0].String())
+	assert.Equal(t, "2024-02-01..2024-03-02", windows[1].String())
+	assert.Equal(t, "2024-03-03..2024-03-15", windows[2].String())
+
+	assert.Len(t, Windows(date("2024-01-01"), date("2024-01-01"), 31), 1)
+	assert.Empty(t, Windows(date("2024-01-02"), date("2024-01-01"), 31))
+}
+
+func TestChunkDays(t *testing.T) {
+	assert.Equal(t, 366, ChunkDays("ecb"))
+	assert.Equal(t, 31, ChunkDays("json"))
+	assert.Equal(t, fallbackChunkDays, ChunkDays("custom"))
+}
+
+func TestRunner_Run(t *testing.T) {
+	state, _ := newTestState(t)
+	service := &fakeService{}
+
+	report, err := NewRunner(service, state, slog.Default()).Run(context.Background(), testJob)
+	require.NoError(t, err)
+
+	assert.Equal(t, Report{Windows: 6, Records: 60}, report)
+	assert.Equal(t, []string{
+		"EUR/USD 2024-01-01..2024-01-31",
+		"EUR/USD 2024-02-01..2024-03-02",
+		"EUR/USD 2024-03-03..2024-03-15",
+		"EUR/GBP 2024-01-01..2024-01-31",
+		"EUR/GBP 2024-02-01..2024-03-02",
+		"EUR/GBP 2024-03-03..2024-03-15",
+	}, service.calls)
+}
+
+func TestRunner_ResumesFailedWindows(t *testing.T) {
+	state, path := newTestState(t)
+	service := &fakeService{failOn: map[string]bool{"2024-02-01..2024-03-02": true}}
+
+	report, err := NewRunner(service, state, slog.Default()).Run(context.Background(), testJob)
+	require.Error(t, err)
+	assert.Equal(t, 2, report.Failed)
+	assert.Len(t, service.calls, 6)
+
+	// Новый запуск читает состояние из файла и повторяет только упавшие окна
+	state, err = LoadState(path)
+	require.NoError(t, err)
+	service = &fakeService{}
+
+	report, err = NewRunner(service, state, slog.Default()).Run(context.Background(), testJob)
+	require.NoError(t, err)
+	assert.Equal(t, Report{Windows: 6, Skipped: 4, Records: 20}, report)
+	assert.Equal(t, []string{
+		"EUR/USD 2024-02-01..2024-03-02",
+		"EUR/GBP 2024-02-01..2024-03-02",
+	}, service.calls)
+}
+
+func TestRunner_StopsOnCancel(t *testing.T) {
+	state, path := newTestState(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	service := &fakeService{cancel: cancel}
+
+	_, err := NewRunner(service, state, slog.Default()).Run(ctx, testJob)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Len(t, service.calls, 2)
+
+	// Первое окно успело сохраниться
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"done": {"ecb:EUR/USD": ["2024-01-01..2024-01-31"]}}`, string(data))
+}
+
+func TestRunner_InvalidJob(t *testing.T) {
+	state, _ := newTestState(t)
+	runner := NewRunner(&fakeService{}, state, slog.Default())
+
+	_, err := runner.Run(context.Background(), Job{From: date("2024-01-01"), To: date("2024-01-31")})
+	assert.Error(t, err)
+
+	job := testJob
+	job.From, job.To = job.To, job.From
+	_, err = runner.Run(context.Background(), job)
+	assert.Error(t, err)
+}
+
+func TestLoadState_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	require.NoError(t, os.WriteFile(path, []byte("{"), 0o600))
+
+	_, err := LoadState(path)
+	assert.Error(t, err)
+}
@@ -0,0 +1,108 @@
+package backfill
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+)
+
+// State is the set of finished windows per pair, persisted to a JSON file
+// after every window.
+type State struct {
+	path string
+
+	mu   sync.Mutex
+	done map[string]map[string]bool
+}
+
+type stateFile struct {
+	Done map[string][]string `json:"done"`
+}
+
+// LoadState reads the state file; a missing file is an empty state.
+func LoadState(path string) (*State, error) {
+	state := &State{path: path, done: make(map[string]map[string]bool)}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backfill state: %w", err)
+	}
+
+	var file stateFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse backfill state %s: %w", path, err)
+	}
+
+	for key, windows := range file.Done {
+		state.done[key] = make(map[string]bool, len(windows))
+		for _, window := range windows {
+			state.done[key][window] = true
+		}
+	}
+
+	return state, nil
+}
+
+func (s *State) Done(key string, window Window) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.done[key][window.String()]
+}
+
+// MarkDone records the window and writes the file.
+func (s *State) MarkDone(key string, window Window) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.done[key] == nil {
+		s.done[key] = make(map[string]bool)
+	}
+	s.done[key][window.String()] = true
+
+	return s.save()
+}
+
+// save replaces the file through a temporary one, so an interrupted write
+// does not lose the previous state.
+func (s *State) save() error {
+	file := stateFile{Done: make(map[string][]string, len(s.done))}
+	for key, windows := range s.done {
+		for window := range windows {
+			file.Done[key] = append(file.Done[key], window)
+		}
+		slices.Sort(file.Done[key])
+	}
+
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode backfill state: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write backfill state: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write backfill state: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write backfill state: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to write backfill state: %w", err)
+	}
+
+	return nil
+}
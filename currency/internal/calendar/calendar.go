@@ -0,0 +1,145 @@
+// Package calendar tells on which days a rate provider publishes fixings.
+package calendar
+
+import (
+	"time"
+)
+
+// Calendar reports whether a provider publishes an observation dated day.
+type Calendar interface {
+	IsPublishingDay(day time.Time) bool
+}
+
+// Weekdays publishes on the listed days of the week.
+type Weekdays []time.Weekday
+
+func (w Weekdays) IsPublishingDay(day time.Time) bool {
+	weekday := day.Weekday()
+	for _, d := range w {
+		if d == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	// MondayToFriday is the week of the ECB reference rates.
+	MondayToFriday = Weekdays{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+
+	// TuesdayToSaturday is the week of CBR: a rate set on a working day is
+	// dated the next day, the Friday rate is dated Saturday.
+	TuesdayToSaturday = Weekdays{time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday}
+
+	// EveryDay fits aggregators that publish on weekends too.
+	EveryDay = Weekdays{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday, time.Sunday}
+)
+
+// Market is the business days of a market: the days of its week that are
+// not holidays. Dates from holiday files override the rules both ways.
+type Market struct {
+	week  Weekdays
+	rules []HolidayRule
+	// Праздники и рабочие дни из файлов пользователя
+	holidays map[time.Time]bool
+	working  map[time.Time]bool
+}
+
+// NewMarket returns a market open on the days of week except rule holidays.
+func NewMarket(week Weekdays, rules ...HolidayRule) *Market {
+	return &Market{
+		week:     week,
+		rules:    rules,
+		holidays: make(map[time.Time]bool),
+		working:  make(map[time.Time]bool),
+	}
+}
+
+func (m *Market) IsPublishingDay(day time.Time) bool {
+	day = truncateDay(day)
+	if m.working[day] {
+		return true
+	}
+	if m.holidays[day] || !m.week.IsPublishingDay(day) {
+		return false
+	}
+	return !m.IsHoliday(day)
+}
+
+// IsHoliday reports whether a rule or a holiday file closes the market on
+// day. Weekends are not holidays.
+func (m *Market) IsHoliday(day time.Time) bool {
+	day = truncateDay(day)
+	if m.holidays[day] {
+		return true
+	}
+	for _, rule := range m.rules {
+		if rule(day) {
+			return true
+		}
+	}
+	return false
+}
+
+// AddHolidays closes the market on the given dates.
+func (m *Market) AddHolidays(days ...time.Time) {
+	for _, day := range days {
+		day = truncateDay(day)
+		m.holidays[day] = true
+		delete(m.working, day)
+	}
+}
+
+// AddWorkingDays opens the market on the given dates, e.g. a Saturday
+// that a government decree made a working day.
+func (m *Market) AddWorkingDays(days ...time.Time) {
+	for _, day := range days {
+		day = truncateDay(day)
+		m.working[day] = true
+		delete(m.holidays, day)
+	}
+}
+
+// Shifted dates the fixing of every publishing day of Calendar Days days
+// later: CBR sets a rate on a Russian working day and dates it the next day.
+type Shifted struct {
+	Calendar Calendar
+	Days     int
+}
+
+func (s Shifted) IsPublishingDay(day time.Time) bool {
+	return s.Calendar.IsPublishingDay(day.AddDate(0, 0, -s.Days))
+}
+
+// PublishingDays returns the publishing days in [from, to].
+func PublishingDays(cal Calendar, from, to time.Time) []time.Time {
+	from, to = truncateDay(from), truncateDay(to)
+
+	var days []time.Time
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		if cal.IsPublishingDay(day) {
+			days = append(days, day)
+		}
+	}
+	return days
+}
+
+// Дольше этого срока без фиксинга не закрыт ни один из рынков
+const maxFixingLookbackDays = 31
+
+// FixingDate returns the fixing that applies on day: the last publishing
+// day on or before it. ok is false if there is none within a month.
+func FixingDate(cal Calendar, day time.Time) (time.Time, bool) {
+	day = truncateDay(day)
+	for i := 0; i <= maxFixingLookbackDays; i++ {
+		if cal.IsPublishingDay(day) {
+			return day, true
+		}
+		day = day.AddDate(0, 0, -1)
+	}
+	return time.Time{}, false
+}
+
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
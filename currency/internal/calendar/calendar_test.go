@@ -0,0 +1,139 @@
+package calendar
+
+import (
+	"my-currency-service/currency/internal/config"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func date(s string) time.Time {
+	t, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestForProvider(t *testing.T) {
+	saturday := date("2025-03-01")
+	monday := date("2025-03-03")
+
+	assert.False(t, ForProvider("ecb").IsPublishingDay(saturday))
+	assert.True(t, ForProvider("ECB").IsPublishingDay(monday))
+
+	assert.True(t, ForProvider("cbr").IsPublishingDay(saturday))
+	assert.False(t, ForProvider("cbr").IsPublishingDay(monday))
+
+	assert.True(t, ForProvider("json").IsPublishingDay(saturday))
+	assert.False(t, ForProvider("unknown").IsPublishingDay(saturday))
+}
+
+func TestPublishingDays(t *testing.T) {
+	days := PublishingDays(MondayToFriday, date("2025-02-27"), date("2025-03-04").Add(15*time.Hour))
+
+	assert.Equal(t, []time.Time{date("2025-02-27"), date("2025-02-28"), date("2025-03-03"), date("2025-03-04")}, days)
+	assert.Empty(t, PublishingDays(MondayToFriday, date("2025-03-01"), date("2025-03-02")))
+}
+
+func TestEaster(t *testing.T) {
+	assert.Equal(t, date("2024-03-31"), Easter(2024))
+	assert.Equal(t, date("2025-04-20"), Easter(2025))
+	assert.Equal(t, date("2026-04-05"), Easter(2026))
+}
+
+func TestTARGET2(t *testing.T) {
+	for _, d := range []string{"2025-01-01", "2025-04-18", "2025-04-21", "2025-05-01", "2025-12-25", "2025-12-26"} {
+		assert.True(t, TARGET2(date(d)), d)
+	}
+	for _, d := range []string{"2025-04-17", "2025-04-22", "2025-05-09", "2025-12-24", "2025-12-31"} {
+		assert.False(t, TARGET2(date(d)), d)
+	}
+
+	ecb := ForProvider("ecb")
+	assert.False(t, ecb.IsPublishingDay(date("2025-04-18")))
+	assert.True(t, ecb.IsPublishingDay(date("2025-04-22")))
+}
+
+func TestRussia(t *testing.T) {
+	for _, d := range []string{"2025-01-02", "2025-01-08", "2025-02-23", "2025-06-12", "2025-11-04"} {
+		assert.True(t, Russia(date(d)), d)
+	}
+	// 1 мая 2021 — суббота, выходной переносится на понедельник
+	assert.True(t, Russia(date("2021-05-03")))
+	// Новогодние выходные переносятся постановлением, а не правилом
+	assert.False(t, Russia(date("2023-01-09")))
+	assert.False(t, Russia(date("2025-01-09")))
+
+	// Курс, установленный в пятницу перед праздником, датирован праздником
+	cbr := ForProvider("cbr")
+	assert.True(t, cbr.IsPublishingDay(date("2025-06-12")))
+	assert.False(t, cbr.IsPublishingDay(date("2025-06-13")))
+}
+
+func TestFed(t *testing.T) {
+	for _, d := range []string{
+		"2025-01-01", "2025-01-20", "2025-02-17", "2025-05-26", "2025-06-19", "2025-07-04",
+		"2025-09-01", "2025-10-13", "2025-11-11", "2025-11-27", "2025-12-25",
+		"2022-12-26", // Рождество в воскресенье
+	} {
+		assert.True(t, Fed(date(d)), d)
+	}
+	for _, d := range []string{"2021-06-18", "2025-01-13", "2025-05-19", "2025-11-20", "2021-12-24"} {
+		assert.False(t, Fed(date(d)), d)
+	}
+}
+
+func TestNew_HolidayFiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "russia.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("holidays: [2025-05-02, \"2025-05-08\"]\nworking_days: [2025-11-01]\n"), 0o600))
+
+	cals, err := New(config.CalendarConfig{
+		HolidayFiles: map[string][]string{"russia": {path}},
+		Providers:    map[string]string{"json": "fed"},
+	})
+	require.NoError(t, err)
+
+	cbr := cals.ForProvider("cbr")
+	assert.False(t, cbr.IsPublishingDay(date("2025-05-03")))
+	assert.False(t, cbr.IsPublishingDay(date("2025-05-09")))
+	assert.True(t, cbr.IsPublishingDay(date("2025-11-02")))
+
+	assert.False(t, cals.ForProvider("JSON").IsPublishingDay(date("2025-07-04")))
+
+	// Встроенные календари файлами не меняются
+	assert.True(t, ForProvider("cbr").IsPublishingDay(date("2025-05-03")))
+}
+
+func TestNew_Invalid(t *testing.T) {
+	_, err := New(config.CalendarConfig{HolidayFiles: map[string][]string{"nyse": {"x.yaml"}}})
+	assert.ErrorContains(t, err, "unknown market")
+
+	_, err = New(config.CalendarConfig{Providers: map[string]string{"ecb": "lunar"}})
+	assert.ErrorContains(t, err, "unknown calendar")
+
+	path := filepath.Join(t.TempDir(), "bad.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("holidays: [02.05.2025]\n"), 0o600))
+	_, err = New(config.CalendarConfig{HolidayFiles: map[string][]string{"target2": {path}}})
+	assert.ErrorContains(t, err, "invalid date")
+}
+
+func TestFixingDate(t *testing.T) {
+	ecb := ForProvider("ecb")
+
+	// Пасхальные выходные: в понедельник действует фиксинг четверга
+	fixing, ok := FixingDate(ecb, date("2025-04-21").Add(12*time.Hour))
+	require.True(t, ok)
+	assert.Equal(t, date("2025-04-17"), fixing)
+
+	fixing, ok = FixingDate(ecb, date("2025-04-22"))
+	require.True(t, ok)
+	assert.Equal(t, date("2025-04-22"), fixing)
+
+	_, ok = FixingDate(Weekdays{}, date("2025-04-22"))
+	assert.False(t, ok)
+}
@@ -0,0 +1,150 @@
+package calendar
+
+import (
+	"fmt"
+	"my-currency-service/currency/internal/clients/currency"
+	"my-currency-service/currency/internal/config"
+	"os"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Имена календарей в конфиге
+const (
+	NameTARGET2  = "target2"
+	NameRussia   = "russia"
+	NameCBR      = "cbr"
+	NameFed      = "fed"
+	NameWeekdays = "weekdays"
+	NameEveryDay = "every_day"
+)
+
+// Calendars resolves the publishing calendar of a provider.
+type Calendars struct {
+	markets    map[string]*Market
+	byName     map[string]Calendar
+	byProvider map[string]Calendar
+}
+
+var builtin = newCalendars()
+
+// ForProvider returns the built-in calendar of a provider, without
+// holiday files. Unknown providers are assumed to publish on weekdays.
+func ForProvider(provider string) Calendar {
+	return builtin.ForProvider(provider)
+}
+
+// New builds the built-in calendars, loads the holiday files of cfg and
+// applies its provider overrides.
+func New(cfg config.CalendarConfig) (*Calendars, error) {
+	c := newCalendars()
+
+	for market, paths := range cfg.HolidayFiles {
+		m, ok := c.markets[strings.ToLower(market)]
+		if !ok {
+			return nil, fmt.Errorf("holiday files: unknown market %q", market)
+		}
+		for _, path := range paths {
+			if err := LoadHolidayFile(m, path); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for provider, name := range cfg.Providers {
+		cal, ok := c.byName[strings.ToLower(name)]
+		if !ok {
+			return nil, fmt.Errorf("provider %q: unknown calendar %q", provider, name)
+		}
+		c.byProvider[strings.ToLower(provider)] = cal
+	}
+
+	return c, nil
+}
+
+func newCalendars() *Calendars {
+	target2 := NewMarket(MondayToFriday, TARGET2)
+	russia := NewMarket(MondayToFriday, Russia)
+	fed := NewMarket(MondayToFriday, Fed)
+	cbr := Shifted{Calendar: russia, Days: 1}
+
+	return &Calendars{
+		markets: map[string]*Market{
+			NameTARGET2: target2,
+			NameRussia:  russia,
+			NameFed:     fed,
+		},
+		byName: map[string]Calendar{
+			NameTARGET2:  target2,
+			NameRussia:   russia,
+			NameCBR:      cbr,
+			NameFed:      fed,
+			NameWeekdays: MondayToFriday,
+			NameEveryDay: EveryDay,
+		},
+		byProvider: map[string]Calendar{
+			currency.ProviderECB:  target2,
+			currency.ProviderCBR:  cbr,
+			currency.ProviderJSON: EveryDay,
+		},
+	}
+}
+
+// ForProvider returns the publishing calendar of a provider. Unknown
+// providers are assumed to publish on weekdays.
+func (c *Calendars) ForProvider(provider string) Calendar {
+	if cal, ok := c.byProvider[strings.ToLower(provider)]; ok {
+		return cal
+	}
+	return MondayToFriday
+}
+
+// HolidayFile is a user list of dates of one market, e.g. holidays
+// announced after the release or days off moved by a decree:
+//
+//	holidays: ["2025-05-02", "2025-05-08"]
+//	working_days: ["2025-11-01"]
+type HolidayFile struct {
+	Holidays    []string `yaml:"holidays"`
+	WorkingDays []string `yaml:"working_days"`
+}
+
+// LoadHolidayFile adds the dates of the file at path to m.
+func LoadHolidayFile(m *Market, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read holiday file: %w", err)
+	}
+
+	var file HolidayFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("failed to parse holiday file %s: %w", path, err)
+	}
+
+	holidays, err := parseDates(file.Holidays)
+	if err != nil {
+		return fmt.Errorf("holiday file %s: holidays: %w", path, err)
+	}
+	working, err := parseDates(file.WorkingDays)
+	if err != nil {
+		return fmt.Errorf("holiday file %s: working_days: %w", path, err)
+	}
+
+	m.AddHolidays(holidays...)
+	m.AddWorkingDays(working...)
+	return nil
+}
+
+func parseDates(values []string) ([]time.Time, error) {
+	dates := make([]time.Time, 0, len(values))
+	for _, value := range values {
+		date, err := time.Parse(time.DateOnly, value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date %q, expected YYYY-MM-DD", value)
+		}
+		dates = append(dates, date)
+	}
+	return dates, nil
+}
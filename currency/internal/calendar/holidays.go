@@ -0,0 +1,118 @@
+package calendar
+
+import "time"
+
+// HolidayRule reports whether day, truncated to midnight UTC, is a holiday.
+type HolidayRule func(day time.Time) bool
+
+// TARGET2 is the closing days of the TARGET2 payment system, on which the
+// ECB publishes no reference rates.
+func TARGET2(day time.Time) bool {
+	if isDate(day, time.January, 1) || isDate(day, time.May, 1) ||
+		isDate(day, time.December, 25) || isDate(day, time.December, 26) {
+		return true
+	}
+
+	easter := Easter(day.Year())
+	return day.Equal(easter.AddDate(0, 0, -2)) || day.Equal(easter.AddDate(0, 0, 1))
+}
+
+// Нерабочие праздники по ст. 112 ТК РФ, кроме новогодних каникул
+var russianHolidays = []struct {
+	month time.Month
+	day   int
+}{
+	{time.February, 23},
+	{time.March, 8},
+	{time.May, 1},
+	{time.May, 9},
+	{time.June, 12},
+	{time.November, 4},
+}
+
+// Russia is the non-working holidays of the Russian Labour Code. A holiday
+// on a weekend moves to the next Monday. Days off moved by government
+// decrees, including the January weekends, are not predictable and come
+// from holiday files.
+func Russia(day time.Time) bool {
+	if day.Month() == time.January && day.Day() <= 8 {
+		return true
+	}
+
+	for _, h := range russianHolidays {
+		if isDate(day, h.month, h.day) {
+			return true
+		}
+	}
+
+	if day.Weekday() != time.Monday {
+		return false
+	}
+	for _, h := range russianHolidays {
+		if isDate(day.AddDate(0, 0, -1), h.month, h.day) || isDate(day.AddDate(0, 0, -2), h.month, h.day) {
+			return true
+		}
+	}
+	return false
+}
+
+// Fed is the holidays of the Federal Reserve System. A holiday on Sunday
+// is observed on Monday; on Saturday the banks stay open on Friday.
+func Fed(day time.Time) bool {
+	year, month := day.Year(), day.Month()
+
+	switch {
+	case observed(day, time.January, 1),
+		month == time.January && isNthWeekday(day, time.Monday, 3),  // Martin Luther King Jr.
+		month == time.February && isNthWeekday(day, time.Monday, 3), // Washington's Birthday
+		month == time.May && isLastWeekday(day, time.Monday),        // Memorial Day
+		year >= 2022 && observed(day, time.June, 19),                // Juneteenth
+		observed(day, time.July, 4),
+		month == time.September && isNthWeekday(day, time.Monday, 1), // Labor Day
+		month == time.October && isNthWeekday(day, time.Monday, 2),   // Columbus Day
+		observed(day, time.November, 11),
+		month == time.November && isNthWeekday(day, time.Thursday, 4), // Thanksgiving
+		observed(day, time.December, 25):
+		return true
+	}
+	return false
+}
+
+// Easter returns Western Easter Sunday of year (anonymous Gregorian algorithm).
+func Easter(year int) time.Time {
+	a := year % 19
+	b, c := year/100, year%100
+	d, e := b/4, b%4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i, k := c/4, c%4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+}
+
+func isDate(day time.Time, month time.Month, dayOfMonth int) bool {
+	return day.Month() == month && day.Day() == dayOfMonth
+}
+
+// observed reports whether day is the holiday or, for a Sunday holiday,
+// the Monday after it.
+func observed(day time.Time, month time.Month, dayOfMonth int) bool {
+	if isDate(day, month, dayOfMonth) {
+		return true
+	}
+	return day.Weekday() == time.Monday && isDate(day.AddDate(0, 0, -1), month, dayOfMonth)
+}
+
+// isNthWeekday reports whether day is the n-th weekday of its month.
+func isNthWeekday(day time.Time, weekday time.Weekday, n int) bool {
+	return day.Weekday() == weekday && (day.Day()-1)/7 == n-1
+}
+
+func isLastWeekday(day time.Time, weekday time.Weekday) bool {
+	return day.Weekday() == weekday && day.AddDate(0, 0, 7).Month() != day.Month()
+}
@@ -0,0 +1,170 @@
+package currency
+
+import (
+	"fmt"
+	"log/slog"
+	"my-currency-service/currency/internal/config"
+	"sync"
+	"time"
+)
+
+const (
+	defaultFailureThreshold = 5
+	defaultOpenTimeout      = time.Minute
+)
+
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitHalfOpen
+	circuitOpen
+)
+
+func (s circuitState) String() string {
+	switch s {
+	case circuitHalfOpen:
+		return "half-open"
+	case circuitOpen:
+		return "open"
+	}
+	return "closed"
+}
+
+// breaker is the circuit breaker of one provider. It opens after
+// threshold failed requests in a row and fails requests at once while
+// open. After openTimeout a single probe request is let through: success
+// closes the circuit, failure opens it for another openTimeout.
+type breaker struct {
+	provider    string
+	threshold   int
+	openTimeout time.Duration
+	now         func() time.Time
+	logger      *slog.Logger
+
+	mu       sync.Mutex
+	state    circuitState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+// newBreaker returns nil if the breaker is disabled; a nil breaker lets
+// every request through.
+func newBreaker(provider string, cfg config.CircuitBreakerConfig, logger *slog.Logger) *breaker {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	threshold := cfg.FailureThreshold
+	if threshold <= 0 {
+		threshold = defaultFailureThreshold
+	}
+	openTimeout := cfg.OpenTimeout
+	if openTimeout <= 0 {
+		openTimeout = defaultOpenTimeout
+	}
+
+	setCircuitState(provider, circuitClosed)
+
+	return &breaker{
+		provider:    provider,
+		threshold:   threshold,
+		openTimeout: openTimeout,
+		now:         time.Now,
+		logger:      logger,
+	}
+}
+
+// allow reports whether a request may be sent. Every allowed request must
+// be followed by success, failure or release.
+func (b *breaker) allow() error {
+	if b == nil {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case circuitOpen:
+		if b.now().Sub(b.openedAt) < b.openTimeout {
+			return fmt.Errorf("%s: %w", b.provider, ErrCircuitOpen)
+		}
+		b.setState(circuitHalfOpen)
+		b.probing = true
+	case circuitHalfOpen:
+		// Пока пробный запрос не вернулся, остальные не пропускаются
+		if b.probing {
+			return fmt.Errorf("%s: %w", b.provider, ErrCircuitOpen)
+		}
+		b.probing = true
+	}
+
+	return nil
+}
+
+// success records a response of the upstream, including a permanent error:
+// the upstream is up.
+func (b *breaker) success() {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures = 0
+	b.probing = false
+	if b.state != circuitClosed {
+		b.setState(circuitClosed)
+	}
+}
+
+// failure records a network error, a timeout or a retryable status.
+func (b *breaker) failure() {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	b.probing = false
+	if b.state == circuitHalfOpen || b.failures >= b.threshold {
+		b.openedAt = b.now()
+		b.setState(circuitOpen)
+	}
+}
+
+// release frees the probe slot of a request whose outcome says nothing
+// about the upstream, e.g. cancelled by the caller.
+func (b *breaker) release() {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+}
+
+func (b *breaker) setState(state circuitState) {
+	if b.state == state {
+		return
+	}
+	b.state = state
+	setCircuitState(b.provider, state)
+
+	switch state {
+	case circuitOpen:
+		b.logger.Warn("provider circuit breaker opened",
+			slog.String("provider", b.provider),
+			slog.Int("failures", b.failures),
+			slog.Duration("open_timeout", b.openTimeout))
+	case circuitClosed:
+		b.logger.Info("provider circuit breaker closed", slog.String("provider", b.provider))
+	}
+}
@@ -0,0 +1,114 @@
+package currency
+
+import (
+	"log/slog"
+	"my-currency-service/currency/internal/config"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestMetrics(t *testing.T) *Metrics {
+	m := NewMetrics(
+		prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "circuit_state"}, []string{"provider"}),
+		prometheus.NewCounterVec(prometheus.CounterOpts{Name: "retries"}, []string{"provider", "reason"}),
+		prometheus.NewCounterVec(prometheus.CounterOpts{Name: "abandoned"}, []string{"provider", "reason"}),
+	)
+	SetMetrics(m)
+	t.Cleanup(func() { SetMetrics(nil) })
+
+	return m
+}
+
+func TestBreaker_OpensAndShortCircuits(t *testing.T) {
+	metrics := newTestMetrics(t)
+	server, calls := newFlakyServer(t, nil, 503, 503, 503, 503, 503, 503)
+	client, transport, _ := newRetryClient(config.APIConfig{
+		Retry:          config.RetryConfig{MaxAttempts: 2},
+		CircuitBreaker: config.CircuitBreakerConfig{Enabled: true, FailureThreshold: 3, OpenTimeout: time.Minute},
+	})
+
+	// Первый запрос: две неудачные попытки, второй: одна, и цепь размыкается
+	code, err := get(client, server.URL)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	_, err = get(client, server.URL)
+	require.ErrorIs(t, err, ErrCircuitOpen)
+
+	assert.Equal(t, int32(3), calls.Load())
+	assert.Equal(t, circuitOpen, transport.breaker.state)
+	assert.Equal(t, float64(circuitOpen), testutil.ToFloat64(metrics.circuitState.WithLabelValues("test")))
+	assert.Equal(t, float64(2), testutil.ToFloat64(metrics.retries.WithLabelValues("test", "503")))
+
+	_, err = get(client, server.URL)
+	require.ErrorIs(t, err, ErrCircuitOpen)
+	assert.True(t, Retryable(err))
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+func TestBreaker_ProbeClosesCircuit(t *testing.T) {
+	metrics := newTestMetrics(t)
+	server, calls := newFlakyServer(t, nil, 500, 500)
+	client, transport, _ := newRetryClient(config.APIConfig{
+		CircuitBreaker: config.CircuitBreakerConfig{Enabled: true, FailureThreshold: 2, OpenTimeout: time.Minute},
+	})
+	now := time.Now()
+	transport.breaker.now = func() time.Time { return now }
+
+	for range 2 {
+		_, err := get(client, server.URL)
+		require.NoError(t, err)
+	}
+	_, err := get(client, server.URL)
+	require.ErrorIs(t, err, ErrCircuitOpen)
+
+	now = now.Add(time.Minute)
+	code, err := get(client, server.URL)
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, int32(3), calls.Load())
+	assert.Equal(t, circuitClosed, transport.breaker.state)
+	assert.Equal(t, float64(circuitClosed), testutil.ToFloat64(metrics.circuitState.WithLabelValues("test")))
+}
+
+func TestBreaker_FailedProbeReopens(t *testing.T) {
+	b := newBreaker("test", config.CircuitBreakerConfig{Enabled: true, FailureThreshold: 1, OpenTimeout: time.Minute}, slog.Default())
+	now := time.Now()
+	b.now = func() time.Time { return now }
+
+	require.NoError(t, b.allow())
+	b.failure()
+	require.ErrorIs(t, b.allow(), ErrCircuitOpen)
+
+	now = now.Add(time.Minute)
+	require.NoError(t, b.allow())
+	assert.Equal(t, circuitHalfOpen, b.state)
+	// Второй запрос ждёт результата пробного
+	require.ErrorIs(t, b.allow(), ErrCircuitOpen)
+
+	b.failure()
+	assert.Equal(t, circuitOpen, b.state)
+	require.ErrorIs(t, b.allow(), ErrCircuitOpen)
+}
+
+func TestBreaker_PermanentErrorsDoNotOpen(t *testing.T) {
+	server, calls := newFlakyServer(t, nil, 404, 404, 404)
+	client, transport, _ := newRetryClient(config.APIConfig{
+		CircuitBreaker: config.CircuitBreakerConfig{Enabled: true, FailureThreshold: 2},
+	})
+
+	for range 3 {
+		code, err := get(client, server.URL)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, code)
+	}
+
+	assert.Equal(t, int32(3), calls.Load())
+	assert.Equal(t, circuitClosed, transport.breaker.state)
+}
@@ -0,0 +1,290 @@
+package currency
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log/slog"
+	"my-currency-service/currency/internal/config"
+	"my-currency-service/currency/internal/dto"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"golang.org/x/text/encoding/charmap"
+)
+
+const (
+	cbrDefaultBaseURL = "https://www.cbr.ru/scripts"
+	cbrCurrency       = "RUB"
+
+	// Точность обратного курса (RUB как базовая валюта): 1/x в общем случае
+	// не представим конечной десятичной дробью
+	cbrInversePrecision = 12
+
+	// Формат дат в ответах ЦБ и в параметрах запроса соответственно
+	cbrDateLayout    = "02.01.2006"
+	cbrRequestLayout = "02/01/2006"
+)
+
+// CBR fetches official rates of the Central Bank of Russia from the
+// XML_daily.asp and XML_dynamic.asp feeds. CBR quotes every currency
+// against RUB, so one side of the requested pair must be RUB. When RUB is
+// the base currency the published rate is inverted.
+type CBR struct {
+	baseURL    string
+	httpClient *http.Client
+	logger     *slog.Logger
+}
+
+func NewCBR(cfg config.APIConfig, logger *slog.Logger) (*CBR, error) {
+	baseURL := strings.TrimRight(cfg.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = cbrDefaultBaseURL
+	}
+
+	return &CBR{
+		baseURL:    baseURL,
+		httpClient: newHTTPClient(ProviderCBR, cfg, logger),
+		logger:     logger,
+	}, nil
+}
+
+func (c *CBR) Name() string {
+	return ProviderCBR
+}
+
+// FetchRates returns CBR rates for the requested pair. A single-day request
+// is served from the daily feed, a period is served from the dynamic feed.
+// If the foreign side of the pair is empty and the period is a single day,
+// rates for every currency published that day are returned.
+func (c *CBR) FetchRates(ctx context.Context, reqData *dto.CurrencyRequestDTO) ([]dto.RateRecordDTO, error) {
+	if reqData.DateFrom.IsZero() || reqData.DateTo.IsZero() {
+		return nil, fmt.Errorf("found zero value in request: DateFrom %s, DateTo %s",
+			reqData.DateFrom, reqData.DateTo)
+	}
+
+	var foreign string
+	rubIsBase := strings.EqualFold(reqData.BaseCurrency, cbrCurrency)
+	switch {
+	case rubIsBase:
+		foreign = strings.ToUpper(reqData.TargetCurrency)
+	case strings.EqualFold(reqData.TargetCurrency, cbrCurrency):
+		foreign = strings.ToUpper(reqData.BaseCurrency)
+	default:
+		return nil, fmt.Errorf("CBR quotes currencies against %s only, got %s/%s",
+			cbrCurrency, reqData.BaseCurrency, reqData.TargetCurrency)
+	}
+
+	dateFrom := truncateDay(reqData.DateFrom)
+	dateTo := truncateDay(reqData.DateTo)
+	if dateFrom.After(dateTo) {
+		return nil, fmt.Errorf("DateFrom %s is after DateTo %s", dateFrom, dateTo)
+	}
+
+	daily, dailyHash, err := c.fetchDaily(ctx, dateTo)
+	if err != nil {
+		return nil, err
+	}
+
+	dailyDate, err := time.Parse(cbrDateLayout, daily.Date)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse date %q: %w", daily.Date, err)
+	}
+
+	if foreign == "" {
+		if !dateFrom.Equal(dateTo) {
+			return nil, fmt.Errorf("currency is required to fetch CBR rates for a period")
+		}
+
+		records := make([]dto.RateRecordDTO, 0, len(daily.Valute))
+		for _, valute := range daily.Valute {
+			record, err := cbrRecord(dailyDate, valute.CharCode, valute.Nominal, valute.Value, valute.VunitRate, rubIsBase)
+			if err != nil {
+				return nil, err
+			}
+			record.SeriesKey, record.PayloadHash = valute.ID, dailyHash
+			records = append(records, record)
+		}
+		return records, nil
+	}
+
+	var valute *RawValute
+	for i := range daily.Valute {
+		if strings.EqualFold(daily.Valute[i].CharCode, foreign) {
+			valute = &daily.Valute[i]
+			break
+		}
+	}
+	if valute == nil {
+		return nil, fmt.Errorf("currency %s is not quoted by CBR on %s", foreign, daily.Date)
+	}
+
+	if dateFrom.Equal(dateTo) {
+		record, err := cbrRecord(dailyDate, foreign, valute.Nominal, valute.Value, valute.VunitRate, rubIsBase)
+		if err != nil {
+			return nil, err
+		}
+		record.SeriesKey, record.PayloadHash = valute.ID, dailyHash
+		return []dto.RateRecordDTO{record}, nil
+	}
+
+	dynamic, dynamicHash, err := c.fetchDynamic(ctx, valute.ID, dateFrom, dateTo)
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]dto.RateRecordDTO, 0, len(dynamic.Records))
+	for _, raw := range dynamic.Records {
+		date, err := time.Parse(cbrDateLayout, raw.Date)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse date %q: %w", raw.Date, err)
+		}
+
+		record, err := cbrRecord(date, foreign, raw.Nominal, raw.Value, raw.VunitRate, rubIsBase)
+		if err != nil {
+			return nil, err
+		}
+		record.SeriesKey, record.PayloadHash = valute.ID, dynamicHash
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+func (c *CBR) fetchDaily(ctx context.Context, date time.Time) (*RawCurrency, string, error) {
+	query := url.Values{}
+	query.Set("date_req", date.Format(cbrRequestLayout))
+
+	var data RawCurrency
+	hash, err := c.get(ctx, c.baseURL+"/XML_daily.asp?"+query.Encode(), &data)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &data, hash, nil
+}
+
+func (c *CBR) fetchDynamic(ctx context.Context, id string, dateFrom, dateTo time.Time) (*RawDynamic, string, error) {
+	query := url.Values{}
+	query.Set("date_req1", dateFrom.Format(cbrRequestLayout))
+	query.Set("date_req2", dateTo.Format(cbrRequestLayout))
+	query.Set("VAL_NM_RQ", id)
+
+	var data RawDynamic
+	hash, err := c.get(ctx, c.baseURL+"/XML_dynamic.asp?"+query.Encode(), &data)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &data, hash, nil
+}
+
+// get decodes the response into v and returns the hash of the payload.
+func (c *CBR) get(ctx context.Context, messageUrl string, v any) (string, error) {
+	c.logger.DebugContext(ctx, "sending request", slog.String("url", messageUrl))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, messageUrl, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Add("Accept", "application/xml")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		return "", &StatusError{StatusCode: resp.StatusCode, Status: resp.Status}
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read body: %w", err)
+	}
+
+	if err := decodeCBR(bytes.NewReader(body), v); err != nil {
+		return "", fmt.Errorf("failed to decode XML: %w", err)
+	}
+
+	return payloadHash(body), nil
+}
+
+// decodeCBR decodes a CBR response. The feeds are served in windows-1251.
+func decodeCBR(body io.Reader, v any) error {
+	decoder := xml.NewDecoder(body)
+	decoder.CharsetReader = func(charset string, input io.Reader) (io.Reader, error) {
+		switch strings.ToLower(charset) {
+		case "windows-1251", "cp1251":
+			return charmap.Windows1251.NewDecoder().Reader(input), nil
+		case "utf-8", "":
+			return input, nil
+		}
+		return nil, fmt.Errorf("unsupported charset %q", charset)
+	}
+
+	return decoder.Decode(v)
+}
+
+// cbrRecord converts a published quote into a record. VunitRate is the rate
+// for one unit of the currency; older payloads lack it, so Value/Nominal is used.
+func cbrRecord(date time.Time, code string, nominal int, value, vunitRate string, rubIsBase bool) (dto.RateRecordDTO, error) {
+	var unitRate decimal.Decimal
+	if vunitRate != "" {
+		rate, err := parseCBRDecimal(vunitRate)
+		if err != nil {
+			return dto.RateRecordDTO{}, err
+		}
+		unitRate = rate
+	} else {
+		if nominal <= 0 {
+			return dto.RateRecordDTO{}, fmt.Errorf("invalid nominal %d for %s", nominal, code)
+		}
+		rate, err := parseCBRDecimal(value)
+		if err != nil {
+			return dto.RateRecordDTO{}, err
+		}
+		unitRate = rate.Div(decimal.NewFromInt(int64(nominal)))
+	}
+
+	if !unitRate.IsPositive() {
+		return dto.RateRecordDTO{}, fmt.Errorf("invalid rate %s for %s", unitRate, code)
+	}
+
+	if rubIsBase {
+		return dto.RateRecordDTO{
+			Date:           date,
+			BaseCurrency:   cbrCurrency,
+			TargetCurrency: code,
+			Value:          decimal.NewFromInt(1).DivRound(unitRate, cbrInversePrecision),
+		}, nil
+	}
+
+	return dto.RateRecordDTO{
+		Date:           date,
+		BaseCurrency:   code,
+		TargetCurrency: cbrCurrency,
+		Value:          unitRate,
+	}, nil
+}
+
+// parseCBRDecimal parses numbers with a comma decimal separator, e.g. "92,5058".
+func parseCBRDecimal(s string) (decimal.Decimal, error) {
+	val, err := decimal.NewFromString(strings.Replace(strings.TrimSpace(s), ",", ".", 1))
+	if err != nil {
+		return decimal.Decimal{}, fmt.Errorf("failed to parse value %q: %w", s, err)
+	}
+	return val, nil
+}
+
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,201 @@
+package currency
+
+import (
+	"context"
+	"log/slog"
+	"my-currency-service/currency/internal/config"
+	"my-currency-service/currency/internal/dto"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/encoding/charmap"
+)
+
+var testCBRDaily = `<?xml version="1.0" encoding="windows-1251"?>
+<ValCurs Date="02.05.2024" name="Foreign Currency Market">
+    <Valute ID="R01235">
+        <NumCode>840</NumCode>
+        <CharCode>USD</CharCode>
+        <Nominal>1</Nominal>
+        <Name>Доллар США</Name>
+        <Value>93,4419</Value>
+        <VunitRate>93,4419</VunitRate>
+    </Valute>
+    <Valute ID="R01820">
+        <NumCode>392</NumCode>
+        <CharCode>JPY</CharCode>
+        <Nominal>100</Nominal>
+        <Name>Японских иен</Name>
+        <Value>59,7581</Value>
+    </Valute>
+</ValCurs>`
+
+var testCBRDynamic = `<?xml version="1.0" encoding="windows-1251"?>
+<ValCurs ID="R01235" DateRange1="30.04.2024" DateRange2="02.05.2024" name="Foreign Currency Market Dynamic">
+    <Record Date="30.04.2024" Id="R01235">
+        <Nominal>1</Nominal>
+        <Value>93,3254</Value>
+        <VunitRate>93,3254</VunitRate>
+    </Record>
+    <Record Date="02.05.2024" Id="R01235">
+        <Nominal>1</Nominal>
+        <Value>93,4419</Value>
+        <VunitRate>93,4419</VunitRate>
+    </Record>
+</ValCurs>`
+
+func newTestCBR(t *testing.T) (*CBR, *[]string) {
+	var requested []string
+
+	encode := func(s string) []byte {
+		b, err := charmap.Windows1251.NewEncoder().Bytes([]byte(s))
+		require.NoError(t, err)
+		return b
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.String())
+		w.Header().Set("Content-Type", "application/xml; charset=windows-1251")
+		switch r.URL.Path {
+		case "/XML_daily.asp":
+			_, _ = w.Write(encode(testCBRDaily))
+		case "/XML_dynamic.asp":
+			assert.Equal(t, "R01235", r.URL.Query().Get("VAL_NM_RQ"))
+			assert.Equal(t, "30/04/2024", r.URL.Query().Get("date_req1"))
+			assert.Equal(t, "02/05/2024", r.URL.Query().Get("date_req2"))
+			_, _ = w.Write(encode(testCBRDynamic))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := NewCBR(config.APIConfig{BaseURL: server.URL, TimeoutSeconds: 5}, slog.Default())
+	require.NoError(t, err)
+
+	return client, &requested
+}
+
+func TestCBR_FetchRates_Daily(t *testing.T) {
+	client, requested := newTestCBR(t)
+	day := time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC)
+
+	rates, err := client.FetchRates(context.Background(), &dto.CurrencyRequestDTO{
+		BaseCurrency:   "USD",
+		TargetCurrency: "RUB",
+		DateFrom:       day,
+		DateTo:         day,
+	})
+
+	require.NoError(t, err)
+	require.Len(t, rates, 1)
+	assert.Equal(t, day, rates[0].Date)
+	assert.Equal(t, "USD", rates[0].BaseCurrency)
+	assert.Equal(t, "RUB", rates[0].TargetCurrency)
+	assert.Equal(t, "93.4419", rates[0].Value.String())
+	assert.Equal(t, "R01235", rates[0].SeriesKey)
+	assert.Len(t, rates[0].PayloadHash, 64)
+	assert.Len(t, *requested, 1)
+}
+
+func TestCBR_FetchRates_NominalScaling(t *testing.T) {
+	client, _ := newTestCBR(t)
+	day := time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC)
+
+	rates, err := client.FetchRates(context.Background(), &dto.CurrencyRequestDTO{
+		BaseCurrency:   "JPY",
+		TargetCurrency: "RUB",
+		DateFrom:       day,
+		DateTo:         day,
+	})
+
+	require.NoError(t, err)
+	require.Len(t, rates, 1)
+	assert.Equal(t, "0.597581", rates[0].Value.String())
+}
+
+func TestCBR_FetchRates_RUBBase(t *testing.T) {
+	client, _ := newTestCBR(t)
+	day := time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC)
+
+	rates, err := client.FetchRates(context.Background(), &dto.CurrencyRequestDTO{
+		BaseCurrency:   "rub",
+		TargetCurrency: "usd",
+		DateFrom:       day,
+		DateTo:         day,
+	})
+
+	require.NoError(t, err)
+	require.Len(t, rates, 1)
+	assert.Equal(t, "RUB", rates[0].BaseCurrency)
+	assert.Equal(t, "USD", rates[0].TargetCurrency)
+	assert.Equal(t, "0.010701837184", rates[0].Value.String())
+}
+
+func TestCBR_FetchRates_AllCurrencies(t *testing.T) {
+	client, _ := newTestCBR(t)
+	day := time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC)
+
+	rates, err := client.FetchRates(context.Background(), &dto.CurrencyRequestDTO{
+		BaseCurrency: "RUB",
+		DateFrom:     day,
+		DateTo:       day,
+	})
+
+	require.NoError(t, err)
+	require.Len(t, rates, 2)
+	assert.Equal(t, "USD", rates[0].TargetCurrency)
+	assert.Equal(t, "JPY", rates[1].TargetCurrency)
+}
+
+func TestCBR_FetchRates_Dynamic(t *testing.T) {
+	client, requested := newTestCBR(t)
+
+	rates, err := client.FetchRates(context.Background(), &dto.CurrencyRequestDTO{
+		BaseCurrency:   "USD",
+		TargetCurrency: "RUB",
+		DateFrom:       time.Date(2024, 4, 30, 0, 0, 0, 0, time.UTC),
+		DateTo:         time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC),
+	})
+
+	require.NoError(t, err)
+	require.Len(t, rates, 2)
+	assert.Equal(t, "2024-04-30", rates[0].Date.Format("2006-01-02"))
+	assert.Equal(t, "93.3254", rates[0].Value.String())
+	assert.Equal(t, "2024-05-02", rates[1].Date.Format("2006-01-02"))
+	assert.Len(t, *requested, 2)
+}
+
+func TestCBR_FetchRates_NonRUBPair(t *testing.T) {
+	client, requested := newTestCBR(t)
+	day := time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC)
+
+	_, err := client.FetchRates(context.Background(), &dto.CurrencyRequestDTO{
+		BaseCurrency:   "USD",
+		TargetCurrency: "EUR",
+		DateFrom:       day,
+		DateTo:         day,
+	})
+
+	require.Error(t, err)
+	assert.Empty(t, *requested)
+}
+
+func TestCBR_FetchRates_UnknownCurrency(t *testing.T) {
+	client, _ := newTestCBR(t)
+	day := time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC)
+
+	_, err := client.FetchRates(context.Background(), &dto.CurrencyRequestDTO{
+		BaseCurrency:   "XYZ",
+		TargetCurrency: "RUB",
+		DateFrom:       day,
+		DateTo:         day,
+	})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "XYZ is not quoted")
+}
@@ -0,0 +1,310 @@
+package currency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"my-currency-service/currency/internal/config"
+	"my-currency-service/currency/internal/dto"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	defaultConsensusTolerance = 0.01
+	defaultMinSources         = 2
+)
+
+// NewJobProvider creates the provider of a worker job: the single provider
+// of the job, a fallback chain of its providers or their consensus.
+func NewJobProvider(api config.APIConfig, job config.WorkerJobConfig, logger *slog.Logger) (RateProvider, error) {
+	if job.Provider != "" && len(job.Providers) > 0 {
+		return nil, errors.New("provider and providers are mutually exclusive")
+	}
+
+	names := job.ProviderNames()
+	if len(names) <= 1 && !job.Consensus.Enabled {
+		var name string
+		if len(names) == 1 {
+			name = names[0]
+		}
+		return NewProvider(api.ForProvider(name), logger)
+	}
+
+	providers := make([]RateProvider, 0, len(names))
+	for _, name := range names {
+		provider, err := NewProvider(api.ForProvider(name), logger)
+		if err != nil {
+			return nil, err
+		}
+		providers = append(providers, provider)
+	}
+
+	if job.Consensus.Enabled {
+		return NewConsensus(providers, job.Consensus, logger)
+	}
+	return NewFallback(providers, logger), nil
+}
+
+// PrimaryName returns the name of the provider whose publishing calendar
+// applies: the first provider of a chain or a consensus.
+func PrimaryName(provider RateProvider) string {
+	if composite, ok := provider.(interface{ Primary() RateProvider }); ok {
+		return PrimaryName(composite.Primary())
+	}
+	return provider.Name()
+}
+
+// Fallback asks its providers in priority order and returns the rates of
+// the first one that answers. The records are marked with its name.
+type Fallback struct {
+	providers []RateProvider
+	logger    *slog.Logger
+}
+
+func NewFallback(providers []RateProvider, logger *slog.Logger) *Fallback {
+	return &Fallback{providers: providers, logger: logger}
+}
+
+func (f *Fallback) Name() string {
+	return joinNames(f.providers)
+}
+
+func (f *Fallback) Primary() RateProvider {
+	return f.providers[0]
+}
+
+func (f *Fallback) FetchRates(ctx context.Context, reqData *dto.CurrencyRequestDTO) ([]dto.RateRecordDTO, error) {
+	var errs []error
+	for i, provider := range f.providers {
+		// Провайдер может поправить запрос под себя, следующему нужен исходный
+		req := *reqData
+		records, err := provider.FetchRates(ctx, &req)
+		if err == nil {
+			if i > 0 {
+				f.logger.WarnContext(ctx, "rates fetched from fallback provider",
+					slog.String("provider", provider.Name()),
+					slog.String("base_currency", reqData.BaseCurrency),
+					slog.String("target_currency", reqData.TargetCurrency))
+			}
+			return markProvider(records, provider.Name()), nil
+		}
+
+		errs = append(errs, fmt.Errorf("%s: %w", provider.Name(), err))
+		if ctx.Err() != nil {
+			break
+		}
+		if i < len(f.providers)-1 {
+			f.logger.WarnContext(ctx, "rate provider failed, falling back to the next one",
+				slog.String("provider", provider.Name()),
+				slog.String("next", f.providers[i+1].Name()),
+				slog.Any("error", err))
+		}
+	}
+
+	return nil, errors.Join(errs...)
+}
+
+// Consensus asks all its providers at once and keeps the observations on
+// which at least minSources of them agree: a value further than tolerance
+// from the median of the day is an outlier. The kept value is the one of
+// the first agreeing provider in priority order.
+type Consensus struct {
+	providers  []RateProvider
+	tolerance  decimal.Decimal
+	minSources int
+	logger     *slog.Logger
+}
+
+func NewConsensus(providers []RateProvider, cfg config.ConsensusConfig, logger *slog.Logger) (*Consensus, error) {
+	tolerance := cfg.Tolerance
+	if tolerance <= 0 {
+		tolerance = defaultConsensusTolerance
+	}
+	minSources := cfg.MinSources
+	if minSources <= 0 {
+		minSources = defaultMinSources
+	}
+
+	if len(providers) < 2 {
+		return nil, errors.New("consensus requires at least two providers")
+	}
+	if minSources > len(providers) {
+		return nil, fmt.Errorf("consensus min_sources %d exceeds the number of providers %d", minSources, len(providers))
+	}
+
+	return &Consensus{
+		providers:  providers,
+		tolerance:  decimal.NewFromFloat(tolerance),
+		minSources: minSources,
+		logger:     logger,
+	}, nil
+}
+
+func (c *Consensus) Name() string {
+	return "consensus:" + joinNames(c.providers)
+}
+
+func (c *Consensus) Primary() RateProvider {
+	return c.providers[0]
+}
+
+// observationKey identifies one observation across providers.
+type observationKey struct {
+	date           string
+	baseCurrency   string
+	targetCurrency string
+}
+
+func (c *Consensus) FetchRates(ctx context.Context, reqData *dto.CurrencyRequestDTO) ([]dto.RateRecordDTO, error) {
+	results := make([][]dto.RateRecordDTO, len(c.providers))
+	errs := make([]error, len(c.providers))
+
+	var wg sync.WaitGroup
+	for i, provider := range c.providers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			req := *reqData
+			records, err := provider.FetchRates(ctx, &req)
+			if err != nil {
+				errs[i] = fmt.Errorf("%s: %w", provider.Name(), err)
+				return
+			}
+			results[i] = markProvider(records, provider.Name())
+		}()
+	}
+	wg.Wait()
+
+	var answered int
+	for i, err := range errs {
+		if err != nil {
+			c.logger.WarnContext(ctx, "rate provider failed, consensus continues without it",
+				slog.String("provider", c.providers[i].Name()),
+				slog.Any("error", err))
+			continue
+		}
+		answered++
+	}
+	if answered < c.minSources {
+		return nil, fmt.Errorf("consensus needs %d providers, %d answered: %w",
+			c.minSources, answered, errors.Join(errs...))
+	}
+
+	// Котировки дня складываются в порядке приоритета провайдеров
+	target := strings.ToUpper(reqData.TargetCurrency)
+	quotes := make(map[observationKey][]dto.RateRecordDTO)
+	for _, records := range results {
+		for _, record := range records {
+			key := observationKey{
+				date:           record.Date.UTC().Format(time.DateOnly),
+				baseCurrency:   strings.ToUpper(record.BaseCurrency),
+				targetCurrency: strings.ToUpper(record.TargetCurrency),
+			}
+			// JSON отдаёт всю таблицу: сверяются только курсы запрошенной пары
+			if target != "" && key.targetCurrency != target {
+				continue
+			}
+			quotes[key] = append(quotes[key], record)
+		}
+	}
+
+	keys := make([]observationKey, 0, len(quotes))
+	for key := range quotes {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].date != keys[j].date {
+			return keys[i].date < keys[j].date
+		}
+		return keys[i].targetCurrency < keys[j].targetCurrency
+	})
+
+	records := make([]dto.RateRecordDTO, 0, len(keys))
+	for _, key := range keys {
+		record, ok := c.agree(ctx, key, quotes[key])
+		if ok {
+			records = append(records, record)
+		}
+	}
+
+	return records, nil
+}
+
+// agree picks the value of the observation or reports that too few
+// providers agree on it.
+func (c *Consensus) agree(ctx context.Context, key observationKey, quotes []dto.RateRecordDTO) (dto.RateRecordDTO, bool) {
+	median := medianValue(quotes)
+
+	var accepted []dto.RateRecordDTO
+	for _, q := range quotes {
+		if c.withinTolerance(q.Value, median) {
+			accepted = append(accepted, q)
+			continue
+		}
+		c.logger.WarnContext(ctx, "outlier rate rejected",
+			slog.String("provider", q.Provider),
+			slog.String("date", key.date),
+			slog.String("base_currency", key.baseCurrency),
+			slog.String("target_currency", key.targetCurrency),
+			slog.String("value", q.Value.String()),
+			slog.String("median", median.String()))
+	}
+
+	if len(accepted) < c.minSources {
+		// Курс не сохраняется: сверка найдёт пропуск и загрузит его позже
+		c.logger.WarnContext(ctx, "no consensus on rate, observation skipped",
+			slog.String("date", key.date),
+			slog.String("base_currency", key.baseCurrency),
+			slog.String("target_currency", key.targetCurrency),
+			slog.Int("sources", len(quotes)),
+			slog.Int("agreed", len(accepted)))
+		return dto.RateRecordDTO{}, false
+	}
+
+	return accepted[0], true
+}
+
+func (c *Consensus) withinTolerance(value, median decimal.Decimal) bool {
+	if median.IsZero() {
+		return value.IsZero()
+	}
+	return value.Sub(median).Abs().Div(median.Abs()).LessThanOrEqual(c.tolerance)
+}
+
+func medianValue(quotes []dto.RateRecordDTO) decimal.Decimal {
+	values := make([]decimal.Decimal, len(quotes))
+	for i, q := range quotes {
+		values[i] = q.Value
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i].LessThan(values[j]) })
+
+	mid := len(values) / 2
+	if len(values)%2 == 1 {
+		return values[mid]
+	}
+	return values[mid-1].Add(values[mid]).Div(decimal.NewFromInt(2))
+}
+
+func markProvider(records []dto.RateRecordDTO, name string) []dto.RateRecordDTO {
+	for i := range records {
+		if records[i].Provider == "" {
+			records[i].Provider = name
+		}
+	}
+	return records
+}
+
+func joinNames(providers []RateProvider) string {
+	names := make([]string, len(providers))
+	for i, provider := range providers {
+		names[i] = provider.Name()
+	}
+	return strings.Join(names, ",")
+}
@@ -0,0 +1,185 @@
+package currency
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"my-currency-service/currency/internal/config"
+	"my-currency-service/currency/internal/dto"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fixedProvider отвечает заданными курсами EUR/USD по дням или ошибкой.
+// Валюты others котируются с теми же значениями, как во всей таблице JSON.
+type fixedProvider struct {
+	name   string
+	values map[int]string
+	others []string
+	err    error
+	calls  int
+}
+
+func (p *fixedProvider) Name() string { return p.name }
+
+func (p *fixedProvider) FetchRates(context.Context, *dto.CurrencyRequestDTO) ([]dto.RateRecordDTO, error) {
+	p.calls++
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	records := make([]dto.RateRecordDTO, 0, len(p.values))
+	for day, value := range p.values {
+		for _, target := range append([]string{"usd"}, p.others...) {
+			records = append(records, dto.RateRecordDTO{
+				Date:           time.Date(2024, 5, day, 0, 0, 0, 0, time.UTC),
+				BaseCurrency:   "eur",
+				TargetCurrency: target,
+				Value:          decimal.RequireFromString(value),
+			})
+		}
+	}
+	return records, nil
+}
+
+var eurUSD = &dto.CurrencyRequestDTO{BaseCurrency: "EUR", TargetCurrency: "USD"}
+
+func TestFallback_UsesFirstAnswer(t *testing.T) {
+	ecb := &fixedProvider{name: "ecb", err: &StatusError{StatusCode: 503, Status: "503 Service Unavailable"}}
+	json := &fixedProvider{name: "json", values: map[int]string{2: "1.07"}}
+	cbr := &fixedProvider{name: "cbr", values: map[int]string{2: "1.08"}}
+
+	records, err := NewFallback([]RateProvider{ecb, json, cbr}, slog.Default()).FetchRates(context.Background(), eurUSD)
+
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, "1.07", records[0].Value.String())
+	assert.Equal(t, "json", records[0].Provider)
+	assert.Zero(t, cbr.calls)
+}
+
+func TestFallback_AllFail(t *testing.T) {
+	ecb := &fixedProvider{name: "ecb", err: &StatusError{StatusCode: 503, Status: "503 Service Unavailable"}}
+	json := &fixedProvider{name: "json", err: errors.New("failed to parse JSON")}
+
+	_, err := NewFallback([]RateProvider{ecb, json}, slog.Default()).FetchRates(context.Background(), eurUSD)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "ecb: server returned error: 503")
+	assert.Contains(t, err.Error(), "json: failed to parse JSON")
+	// Хотя бы одна ошибка временная — запуск стоит повторить
+	assert.True(t, Retryable(err))
+}
+
+func TestConsensus_RejectsOutlier(t *testing.T) {
+	consensus, err := NewConsensus([]RateProvider{
+		&fixedProvider{name: "ecb", values: map[int]string{1: "1.1000", 2: "1.2000"}},
+		&fixedProvider{name: "json", values: map[int]string{1: "1.1010", 2: "1.0700"}},
+		&fixedProvider{name: "cbr", values: map[int]string{1: "1.0990", 2: "1.0710"}},
+	}, config.ConsensusConfig{Tolerance: 0.005}, slog.Default())
+	require.NoError(t, err)
+
+	records, err := consensus.FetchRates(context.Background(), eurUSD)
+
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	// Все сошлись — значение основного провайдера
+	assert.Equal(t, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), records[0].Date)
+	assert.Equal(t, "1.1", records[0].Value.String())
+	assert.Equal(t, "ecb", records[0].Provider)
+	// ECB выбивается из медианы — значение следующего по приоритету
+	assert.Equal(t, "1.07", records[1].Value.String())
+	assert.Equal(t, "json", records[1].Provider)
+}
+
+func TestConsensus_SkipsDayWithoutAgreement(t *testing.T) {
+	consensus, err := NewConsensus([]RateProvider{
+		&fixedProvider{name: "ecb", values: map[int]string{1: "1.10", 2: "1.20"}},
+		&fixedProvider{name: "json", values: map[int]string{1: "1.10", 2: "1.07", 3: "1.08"}},
+	}, config.ConsensusConfig{Tolerance: 0.01}, slog.Default())
+	require.NoError(t, err)
+
+	records, err := consensus.FetchRates(context.Background(), eurUSD)
+
+	require.NoError(t, err)
+	// 2 мая источники расходятся, 3 мая источник один
+	require.Len(t, records, 1)
+	assert.Equal(t, 1, records[0].Date.Day())
+}
+
+func TestConsensus_OnlyRequestedTarget(t *testing.T) {
+	var logs bytes.Buffer
+	consensus, err := NewConsensus([]RateProvider{
+		&fixedProvider{name: "ecb", values: map[int]string{1: "1.10"}},
+		&fixedProvider{name: "json", values: map[int]string{1: "1.10"}, others: []string{"gbp", "jpy"}},
+	}, config.ConsensusConfig{}, slog.New(slog.NewTextHandler(&logs, nil)))
+	require.NoError(t, err)
+
+	records, err := consensus.FetchRates(context.Background(), eurUSD)
+
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, "usd", records[0].TargetCurrency)
+	// Валюты, которых нет у других источников, не сверяются и не шумят в логе
+	assert.NotContains(t, logs.String(), "no consensus")
+}
+
+func TestConsensus_TooFewProvidersAnswered(t *testing.T) {
+	consensus, err := NewConsensus([]RateProvider{
+		&fixedProvider{name: "ecb", err: errors.New("connection refused")},
+		&fixedProvider{name: "json", values: map[int]string{1: "1.10"}},
+	}, config.ConsensusConfig{}, slog.Default())
+	require.NoError(t, err)
+
+	_, err = consensus.FetchRates(context.Background(), eurUSD)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "consensus needs 2 providers, 1 answered")
+}
+
+func TestNewJobProvider(t *testing.T) {
+	single, err := NewJobProvider(config.APIConfig{}, config.WorkerJobConfig{Provider: "cbr"}, slog.Default())
+	require.NoError(t, err)
+	assert.Equal(t, ProviderCBR, single.Name())
+
+	chain, err := NewJobProvider(config.APIConfig{}, config.WorkerJobConfig{Providers: []string{"ecb", "json"}}, slog.Default())
+	require.NoError(t, err)
+	assert.IsType(t, &Fallback{}, chain)
+	assert.Equal(t, "ecb,json", chain.Name())
+	assert.Equal(t, ProviderECB, PrimaryName(chain))
+
+	consensus, err := NewJobProvider(config.APIConfig{}, config.WorkerJobConfig{
+		Providers: []string{"json", "ecb"},
+		Consensus: config.ConsensusConfig{Enabled: true},
+	}, slog.Default())
+	require.NoError(t, err)
+	assert.Equal(t, "consensus:json,ecb", consensus.Name())
+	assert.Equal(t, ProviderJSON, PrimaryName(consensus))
+}
+
+func TestNewJobProvider_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		job  config.WorkerJobConfig
+	}{
+		{"provider and providers", config.WorkerJobConfig{Provider: "ecb", Providers: []string{"json"}}},
+		{"unknown provider", config.WorkerJobConfig{Providers: []string{"ecb", "nope"}}},
+		{"consensus of one", config.WorkerJobConfig{Providers: []string{"ecb"}, Consensus: config.ConsensusConfig{Enabled: true}}},
+		{"min sources", config.WorkerJobConfig{
+			Providers: []string{"ecb", "json"},
+			Consensus: config.ConsensusConfig{Enabled: true, MinSources: 3},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewJobProvider(config.APIConfig{}, tt.job, slog.Default())
+			assert.Error(t, err)
+		})
+	}
+}
@@ -11,41 +11,63 @@ import (
 	"my-currency-service/currency/internal/config"
 	"my-currency-service/currency/internal/dto"
 	"net/http"
-	"strconv"
 	"time"
+
+	"github.com/shopspring/decimal"
 )
 
-type Currency struct {
+const ecbDefaultBaseURL = "https://data-api.ecb.europa.eu/service/data/EXR/D.%s.%s.SP00.A?startPeriod=%s&endPeriod=%s"
+
+// ECB fetches rates from the ECB SDMX data API. ECB series keys are
+// D.<currency>.<denominator>, i.e. D.USD.EUR is the price of one EUR in USD,
+// so the target currency goes first: base_url is a template filled with
+// target, base, startPeriod and endPeriod.
+type ECB struct {
 	baseURL    string
 	httpClient *http.Client
 	logger     *slog.Logger
 }
 
-func New(cfg config.APIConfig, logger *slog.Logger) (Currency, error) {
-	return Currency{
-		baseURL: cfg.BaseURL,
-		httpClient: &http.Client{
-			Timeout: time.Duration(cfg.TimeoutSeconds) * time.Second,
-			Transport: &http.Transport{
-				TLSClientConfig: &tls.Config{InsecureSkipVerify: cfg.SkipVerify},
-			},
-		},
-		logger: logger,
+func NewECB(cfg config.APIConfig, logger *slog.Logger) (*ECB, error) {
+	baseURL := cfg.BaseURL
+	if baseURL == "" {
+		baseURL = ecbDefaultBaseURL
+	}
+
+	return &ECB{
+		baseURL:    baseURL,
+		httpClient: newHTTPClient(ProviderECB, cfg, logger),
+		logger:     logger,
 	}, nil
 }
 
-func (c *Currency) buildURL(ReqData *dto.CurrencyRequestDTO) (string, error) {
+// newHTTPClient returns the client shared by the providers: requests are
+// repeated on transient failures and pass the circuit breaker of provider.
+func newHTTPClient(provider string, cfg config.APIConfig, logger *slog.Logger) *http.Client {
+	return &http.Client{
+		// Таймаут на попытку держит retryTransport: общий оборвал бы паузы между повторами
+		Transport: newRetryTransport(provider, cfg, &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: cfg.SkipVerify},
+		}, logger),
+	}
+}
+
+func (c *ECB) Name() string {
+	return ProviderECB
+}
+
+func (c *ECB) buildURL(ReqData *dto.CurrencyRequestDTO) (string, error) {
 	if ReqData.BaseCurrency == "" || ReqData.TargetCurrency == "" ||
 		ReqData.DateFrom.IsZero() || ReqData.DateTo.IsZero() {
 		return "", fmt.Errorf("found zero value in request: BaseCurrency %s, TargetCurrency %s, DateFrom %s, DateTo %s",
 			ReqData.BaseCurrency, ReqData.TargetCurrency, ReqData.DateFrom, ReqData.DateTo)
 	}
 	return fmt.Sprintf(c.baseURL,
-		ReqData.BaseCurrency, ReqData.TargetCurrency,
+		ReqData.TargetCurrency, ReqData.BaseCurrency,
 		ReqData.DateFrom.Format("2006-01-02"), ReqData.DateTo.Format("2006-01-02")), nil
 }
 
-func (c *Currency) FetchCurrentRates(ctx context.Context, ReqData *dto.CurrencyRequestDTO) (map[string]float64, error) {
+func (c *ECB) FetchRates(ctx context.Context, ReqData *dto.CurrencyRequestDTO) ([]dto.RateRecordDTO, error) {
 
 	messageUrl, err := c.buildURL(ReqData)
 	if err != nil {
@@ -65,36 +87,39 @@ func (c *Currency) FetchCurrentRates(ctx context.Context, ReqData *dto.CurrencyR
 	resp, err := c.httpClient.Do(req)
 
 	if err != nil {
-		return nil, fmt.Errorf("Error while execute request: %v\n", err)
+		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
 	defer func(Body io.ReadCloser) {
-		err := Body.Close()
-		if err != nil {
-		}
+		_ = Body.Close()
 	}(resp.Body)
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("Server returned error: %s\n", resp.Status)
+		return nil, &StatusError{StatusCode: resp.StatusCode, Status: resp.Status}
 	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("Error while reading body: %v\n", err)
+		return nil, fmt.Errorf("failed to read body: %w", err)
 	}
 
 	points, err := extractObs(bytes.NewReader(body))
 	if err != nil {
-		return nil, fmt.Errorf("Error while parsing XML: %v\n", err)
+		return nil, fmt.Errorf("failed to parse XML: %w", err)
 	}
 
 	// TODO: add metrics for this method
 
-	rates := make(map[string]float64, len(points))
-	for _, p := range points {
-		rates[p.Date.Format("2006-01-02")] = float64(p.Value)
+	hash := payloadHash(body)
+	// Ключ ряда в SDMX ECB: EXR.D.<валюта>.<знаменатель>.SP00.A
+	seriesKey := fmt.Sprintf("EXR.D.%s.%s.SP00.A", ReqData.TargetCurrency, ReqData.BaseCurrency)
+	for i := range points {
+		points[i].BaseCurrency = ReqData.BaseCurrency
+		points[i].TargetCurrency = ReqData.TargetCurrency
+		points[i].SeriesKey = seriesKey
+		points[i].PayloadHash = hash
 	}
 
-	return rates, nil
+	return points, nil
 
 }
 
@@ -116,14 +141,14 @@ func extractObs(body io.Reader) ([]dto.RateRecordDTO, error) {
 			return nil, fmt.Errorf("failed to parse date %q: %w", obs.TimePeriod, err)
 		}
 
-		val, err := strconv.ParseFloat(obs.ObsValue, 32)
+		val, err := decimal.NewFromString(obs.ObsValue)
 		if err != nil {
 			return nil, fmt.Errorf("failed to parse value %q: %w", obs.ObsValue, err)
 		}
 
 		RateRecords = append(RateRecords, dto.RateRecordDTO{
 			Date:  date,
-			Value: float32(val),
+			Value: val,
 		})
 
 	}
@@ -14,7 +14,7 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-func newTestClient(t *testing.T) Currency {
+func newTestClient(t *testing.T) RateProvider {
 	cfg := config.MustLoad()
 
 	loggerInstance, err := logger.SetupLogger(cfg.Service.Env)
@@ -22,28 +22,30 @@ func newTestClient(t *testing.T) Currency {
 		t.Fatalf("error creating logger: %v", err)
 	}
 
-	client, err := New(cfg.API, loggerInstance)
+	client, err := NewProvider(cfg.API, loggerInstance)
 	require.NoError(t, err)
 	return client
 }
 
-func TestFetchCurrentRates_RealAPI(t *testing.T) {
+func TestFetchRates_RealAPI(t *testing.T) {
 	client := newTestClient(t)
 
 	req := &dto.CurrencyRequestDTO{
-		BaseCurrency:   "USD",
-		TargetCurrency: "EUR",
+		BaseCurrency:   "EUR",
+		TargetCurrency: "USD",
 		DateFrom:       time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
 		DateTo:         time.Date(2024, 5, 31, 0, 0, 0, 0, time.UTC),
 	}
 
-	rates, err := client.FetchCurrentRates(context.Background(), req)
+	rates, err := client.FetchRates(context.Background(), req)
 
 	require.NoError(t, err)
 	assert.NotEmpty(t, rates)
 
-	for date, rate := range rates {
-		assert.NotEmpty(t, date)
-		assert.Greater(t, rate, 0.0)
+	for _, rate := range rates {
+		assert.False(t, rate.Date.IsZero())
+		assert.Equal(t, "EUR", rate.BaseCurrency)
+		assert.Equal(t, "USD", rate.TargetCurrency)
+		assert.True(t, rate.Value.IsPositive())
 	}
 }
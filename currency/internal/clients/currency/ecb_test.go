@@ -25,9 +25,9 @@ func TestExtractObs(t *testing.T) {
 
 	require.Len(t, rates, 2)
 	assert.Equal(t, "2024-05-01", rates[0].Date.Format("2006-01-02"))
-	assert.InDelta(t, 1.0823, rates[0].Value, 0.0001)
+	assert.Equal(t, "1.0823", rates[0].Value.String())
 
 	assert.Equal(t, "2024-05-02", rates[1].Date.Format("2006-01-02"))
-	assert.InDelta(t, 1.0791, rates[1].Value, 0.0001)
+	assert.Equal(t, "1.0791", rates[1].Value.String())
 
 }
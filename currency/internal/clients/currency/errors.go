@@ -0,0 +1,52 @@
+package currency
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+)
+
+// ErrCircuitOpen is returned without reaching the upstream while the circuit
+// breaker of the provider is open.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// StatusError is a response of the upstream with a status other than 200.
+type StatusError struct {
+	StatusCode int
+	Status     string
+}
+
+func (e *StatusError) Error() string {
+	return "server returned error: " + e.Status
+}
+
+// Retryable reports whether err may go away if the request is repeated
+// later: network failures and timeouts, an open circuit breaker and the
+// 408, 429 and 5xx responses. Other responses, a cancelled context and
+// payloads that fail to parse are permanent.
+func Retryable(err error) bool {
+	var statusErr *StatusError
+	switch {
+	case err == nil, errors.Is(err, context.Canceled):
+		return false
+	case errors.Is(err, ErrCircuitOpen), errors.Is(err, context.DeadlineExceeded):
+		return true
+	case errors.As(err, &statusErr):
+		return retryableStatus(statusErr.StatusCode)
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+func retryableStatus(code int) bool {
+	switch code {
+	case http.StatusRequestTimeout, http.StatusTooManyRequests:
+		return true
+	case http.StatusNotImplemented, http.StatusHTTPVersionNotSupported:
+		// Повтор не поможет: сервер не умеет такой запрос
+		return false
+	}
+	return code >= http.StatusInternalServerError
+}
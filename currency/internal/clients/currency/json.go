@@ -0,0 +1,195 @@
+package currency
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"my-currency-service/currency/internal/config"
+	"my-currency-service/currency/internal/dto"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	jsonDefaultBaseURL = "https://%s.currency-api.pages.dev/v1/currencies"
+
+	// Максимальное число дней в одном запросе: фид версионируется по дате,
+	// поэтому на каждый день приходится отдельный HTTP-запрос.
+	jsonMaxDays = 366
+)
+
+// JSON fetches rates from the date-versioned currency-api.pages.dev feed.
+// The base URL contains a single %s placeholder for the version, which is
+// a date in 2006-01-02 format; the feed returns every currency for a base
+// in one document: {"date": "2024-05-01", "usd": {"eur": 0.93, ...}}.
+type JSON struct {
+	baseURL    string
+	httpClient *http.Client
+	logger     *slog.Logger
+}
+
+func NewJSON(cfg config.APIConfig, logger *slog.Logger) (*JSON, error) {
+	baseURL := strings.TrimRight(cfg.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = jsonDefaultBaseURL
+	}
+
+	if strings.Count(baseURL, "%s") != 1 {
+		return nil, fmt.Errorf("base url %q must contain exactly one %%s placeholder for the date", baseURL)
+	}
+
+	return &JSON{
+		baseURL:    baseURL,
+		httpClient: newHTTPClient(ProviderJSON, cfg, logger),
+		logger:     logger,
+	}, nil
+}
+
+func (c *JSON) Name() string {
+	return ProviderJSON
+}
+
+// FetchRates returns rates of every currency against the requested base for
+// each day of the period. TargetCurrency is ignored: the feed always returns
+// the whole table.
+func (c *JSON) FetchRates(ctx context.Context, reqData *dto.CurrencyRequestDTO) ([]dto.RateRecordDTO, error) {
+	if reqData.BaseCurrency == "" || reqData.DateFrom.IsZero() || reqData.DateTo.IsZero() {
+		return nil, fmt.Errorf("found zero value in request: BaseCurrency %s, DateFrom %s, DateTo %s",
+			reqData.BaseCurrency, reqData.DateFrom, reqData.DateTo)
+	}
+
+	dateFrom := truncateDay(reqData.DateFrom)
+	dateTo := truncateDay(reqData.DateTo)
+	if dateFrom.After(dateTo) {
+		return nil, fmt.Errorf("DateFrom %s is after DateTo %s", dateFrom, dateTo)
+	}
+	if days := int(dateTo.Sub(dateFrom).Hours()/24) + 1; days > jsonMaxDays {
+		return nil, fmt.Errorf("period of %d days exceeds the limit of %d days", days, jsonMaxDays)
+	}
+
+	var records []dto.RateRecordDTO
+	seen := make(map[time.Time]bool)
+	for day := dateFrom; !day.After(dateTo); day = day.AddDate(0, 0, 1) {
+		dayRecords, err := c.fetchDay(ctx, day, reqData.BaseCurrency)
+		if err != nil {
+			return nil, err
+		}
+
+		// Для дат без публикации фид может отдать документ за другую дату
+		if len(dayRecords) == 0 || seen[dayRecords[0].Date] {
+			continue
+		}
+		seen[dayRecords[0].Date] = true
+
+		records = append(records, dayRecords...)
+	}
+
+	return records, nil
+}
+
+func (c *JSON) fetchDay(ctx context.Context, day time.Time, baseCurrency string) ([]dto.RateRecordDTO, error) {
+	base := strings.ToLower(baseCurrency)
+	messageUrl := fmt.Sprintf(c.baseURL, day.Format("2006-01-02")) + "/" + base + ".json"
+
+	c.logger.DebugContext(ctx, "sending request", slog.String("url", messageUrl))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, messageUrl, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Add("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer func(Body io.ReadCloser) {
+		_ = Body.Close()
+	}(resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &StatusError{StatusCode: resp.StatusCode, Status: resp.Status}
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read body: %w", err)
+	}
+
+	records, err := extractJSONRates(bytes.NewReader(body), base)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse JSON: %w", err)
+	}
+
+	hash := payloadHash(body)
+	for i := range records {
+		records[i].PayloadHash = hash
+	}
+
+	return records, nil
+}
+
+func extractJSONRates(body io.Reader, base string) ([]dto.RateRecordDTO, error) {
+	var payload map[string]json.RawMessage
+	if err := json.NewDecoder(body).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("failed to decode JSON: %w", err)
+	}
+
+	var rawDate string
+	if err := json.Unmarshal(payload["date"], &rawDate); err != nil {
+		return nil, fmt.Errorf("failed to read date: %w", err)
+	}
+
+	date, err := time.Parse("2006-01-02", rawDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse date %q: %w", rawDate, err)
+	}
+
+	rawRates, ok := payload[base]
+	if !ok {
+		return nil, fmt.Errorf("payload has no rates for %q", base)
+	}
+
+	var rates map[string]json.Number
+	decoder := json.NewDecoder(strings.NewReader(string(rawRates)))
+	decoder.UseNumber()
+	if err := decoder.Decode(&rates); err != nil {
+		return nil, fmt.Errorf("failed to decode rates: %w", err)
+	}
+
+	codes := make([]string, 0, len(rates))
+	for code := range rates {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	records := make([]dto.RateRecordDTO, 0, len(rates))
+	for _, code := range codes {
+		if code == base {
+			continue
+		}
+
+		val, err := decimal.NewFromString(rates[code].String())
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse value %q: %w", rates[code], err)
+		}
+
+		records = append(records, dto.RateRecordDTO{
+			Date:           date,
+			BaseCurrency:   strings.ToUpper(base),
+			TargetCurrency: strings.ToUpper(code),
+			Value:          val,
+			// Путь курса в фиде: таблица базовой валюты и код в ней
+			SeriesKey: base + "/" + code,
+		})
+	}
+
+	return records, nil
+}
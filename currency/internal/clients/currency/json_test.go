@@ -0,0 +1,118 @@
+package currency
+
+import (
+	"context"
+	"log/slog"
+	"my-currency-service/currency/internal/config"
+	"my-currency-service/currency/internal/dto"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestJSON serves recorded payloads from testdata by the
+// /<date>/v1/currencies/<base>.json scheme of the real feed.
+func newTestJSON(t *testing.T) (*JSON, *[]string) {
+	var requested []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.Path)
+
+		parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+		if len(parts) != 4 || parts[1] != "v1" || parts[2] != "currencies" {
+			http.NotFound(w, r)
+			return
+		}
+
+		name := "currency_api_" + parts[0] + "_" + strings.TrimSuffix(parts[3], ".json") + ".json"
+		body, err := os.ReadFile(filepath.Join("testdata", name))
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(body)
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := NewJSON(config.APIConfig{BaseURL: server.URL + "/%s/v1/currencies", TimeoutSeconds: 5}, slog.Default())
+	require.NoError(t, err)
+
+	return client, &requested
+}
+
+func TestJSON_FetchRates_SingleDay(t *testing.T) {
+	client, requested := newTestJSON(t)
+	day := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	rates, err := client.FetchRates(context.Background(), &dto.CurrencyRequestDTO{
+		BaseCurrency:   "USD",
+		TargetCurrency: "EUR",
+		DateFrom:       day,
+		DateTo:         day,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/2024-05-01/v1/currencies/usd.json"}, *requested)
+
+	// Every target for the base, sorted by code, without the base itself
+	require.Len(t, rates, 3)
+	assert.Equal(t, "EUR", rates[0].TargetCurrency)
+	assert.Equal(t, "JPY", rates[1].TargetCurrency)
+	assert.Equal(t, "RUB", rates[2].TargetCurrency)
+	for _, rate := range rates {
+		assert.Equal(t, "USD", rate.BaseCurrency)
+		assert.Equal(t, day, rate.Date)
+	}
+	assert.Equal(t, "0.93658192", rates[0].Value.String())
+	assert.Equal(t, "157.70963174", rates[1].Value.String())
+
+	payload, err := os.ReadFile(filepath.Join("testdata", "currency_api_2024-05-01_usd.json"))
+	require.NoError(t, err)
+	assert.Equal(t, "usd/eur", rates[0].SeriesKey)
+	assert.Equal(t, payloadHash(payload), rates[0].PayloadHash)
+}
+
+func TestJSON_FetchRates_Period(t *testing.T) {
+	client, requested := newTestJSON(t)
+
+	rates, err := client.FetchRates(context.Background(), &dto.CurrencyRequestDTO{
+		BaseCurrency: "usd",
+		DateFrom:     time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+		DateTo:       time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC),
+	})
+
+	require.NoError(t, err)
+	assert.Len(t, *requested, 2)
+	require.Len(t, rates, 6)
+	assert.Equal(t, "2024-05-02", rates[5].Date.Format("2006-01-02"))
+	assert.Equal(t, "92.27430001", rates[5].Value.String())
+}
+
+func TestJSON_FetchRates_NotFound(t *testing.T) {
+	client, _ := newTestJSON(t)
+	day := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	_, err := client.FetchRates(context.Background(), &dto.CurrencyRequestDTO{
+		BaseCurrency: "GBP",
+		DateFrom:     day,
+		DateTo:       day,
+	})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "404")
+}
+
+func TestNewJSON_InvalidBaseURL(t *testing.T) {
+	_, err := NewJSON(config.APIConfig{BaseURL: "https://example.com/v1/currencies"}, slog.Default())
+
+	require.Error(t, err)
+}
@@ -0,0 +1,49 @@
+package currency
+
+import (
+	"sync/atomic"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics holds the collectors of the provider clients.
+type Metrics struct {
+	circuitState *prometheus.GaugeVec
+	retries      *prometheus.CounterVec
+	abandoned    *prometheus.CounterVec
+}
+
+// NewMetrics wraps the gauge of the circuit breaker state labelled by
+// provider (0 closed, 1 half-open, 2 open), the counter of repeated
+// requests labelled by provider and reason and the counter of failed
+// requests given up on, labelled by provider and the reason to give up:
+// "max_attempts" or "retry_after".
+func NewMetrics(circuitState *prometheus.GaugeVec, retries, abandoned *prometheus.CounterVec) *Metrics {
+	return &Metrics{circuitState: circuitState, retries: retries, abandoned: abandoned}
+}
+
+var clientMetrics atomic.Pointer[Metrics]
+
+// SetMetrics makes the provider clients report to m. It is called on
+// startup, before the providers are created.
+func SetMetrics(m *Metrics) {
+	clientMetrics.Store(m)
+}
+
+func setCircuitState(provider string, state circuitState) {
+	if m := clientMetrics.Load(); m != nil {
+		m.circuitState.WithLabelValues(provider).Set(float64(state))
+	}
+}
+
+func incRetries(provider, reason string) {
+	if m := clientMetrics.Load(); m != nil {
+		m.retries.WithLabelValues(provider, reason).Inc()
+	}
+}
+
+func incAbandoned(provider, reason string) {
+	if m := clientMetrics.Load(); m != nil {
+		m.abandoned.WithLabelValues(provider, reason).Inc()
+	}
+}
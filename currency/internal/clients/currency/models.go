@@ -21,18 +21,37 @@ type StructureSpecificObs struct {
 	ObsValue   string `xml:"OBS_VALUE,attr"`
 }
 
+// XML-структуры для ежедневных курсов ЦБ РФ (XML_daily.asp)
 type RawCurrency struct {
-	XMLName xml.Name `xml:"ValCurs"`
-	Date    string   `xml:"Date"`
-	Name    string   `xml:"Name"`
-
-	Valute []struct {
-		ID        string `xml:"ID,attr"`
-		NumCode   int    `xml:"NumCode"`
-		CharCode  string `xml:"CharCode"`
-		Nominal   int    `xml:"Nominal"`
-		Name      string `xml:"Name"`
-		Value     string `xml:"Value"`
-		VunitRate string `xml:"VunitRate"`
-	} `xml:"Valute"`
+	XMLName xml.Name    `xml:"ValCurs"`
+	Date    string      `xml:"Date,attr"`
+	Name    string      `xml:"name,attr"`
+	Valute  []RawValute `xml:"Valute"`
+}
+
+type RawValute struct {
+	ID        string `xml:"ID,attr"`
+	NumCode   int    `xml:"NumCode"`
+	CharCode  string `xml:"CharCode"`
+	Nominal   int    `xml:"Nominal"`
+	Name      string `xml:"Name"`
+	Value     string `xml:"Value"`
+	VunitRate string `xml:"VunitRate"`
+}
+
+// XML-структуры для динамики курса ЦБ РФ (XML_dynamic.asp)
+type RawDynamic struct {
+	XMLName    xml.Name           `xml:"ValCurs"`
+	ID         string             `xml:"ID,attr"`
+	DateRange1 string             `xml:"DateRange1,attr"`
+	DateRange2 string             `xml:"DateRange2,attr"`
+	Records    []RawDynamicRecord `xml:"Record"`
+}
+
+type RawDynamicRecord struct {
+	Date      string `xml:"Date,attr"`
+	ID        string `xml:"Id,attr"`
+	Nominal   int    `xml:"Nominal"`
+	Value     string `xml:"Value"`
+	VunitRate string `xml:"VunitRate"`
 }
@@ -0,0 +1,104 @@
+package currency
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"my-currency-service/currency/internal/config"
+	"my-currency-service/currency/internal/dto"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	ProviderECB  = "ecb"
+	ProviderCBR  = "cbr"
+	ProviderJSON = "json"
+
+	DefaultProvider = ProviderECB
+)
+
+// RateProvider is a source of exchange rates. Every upstream (ECB, CBR, ...)
+// implements it, so the service and the worker never depend on a concrete client.
+type RateProvider interface {
+	// Name returns the registry name of the provider, e.g. "ecb".
+	Name() string
+	// FetchRates returns observations for the requested pair and period.
+	FetchRates(ctx context.Context, reqData *dto.CurrencyRequestDTO) ([]dto.RateRecordDTO, error)
+}
+
+// Factory builds a provider from the api section of the config.
+type Factory func(cfg config.APIConfig, logger *slog.Logger) (RateProvider, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Factory{
+		ProviderECB: func(cfg config.APIConfig, logger *slog.Logger) (RateProvider, error) {
+			return NewECB(cfg, logger)
+		},
+		ProviderCBR: func(cfg config.APIConfig, logger *slog.Logger) (RateProvider, error) {
+			return NewCBR(cfg, logger)
+		},
+		ProviderJSON: func(cfg config.APIConfig, logger *slog.Logger) (RateProvider, error) {
+			return NewJSON(cfg, logger)
+		},
+	}
+)
+
+// Register makes a provider available under the given name.
+// Registering the same name twice replaces the previous factory.
+func Register(name string, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	registry[strings.ToLower(name)] = factory
+}
+
+// Providers returns the names of all registered providers in sorted order.
+func Providers() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// payloadHash returns the hex SHA-256 of a raw upstream response. It is
+// stored with every rate taken from the response.
+func payloadHash(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
+// NewProvider creates the provider selected by cfg.Provider.
+// Empty value falls back to DefaultProvider.
+func NewProvider(cfg config.APIConfig, logger *slog.Logger) (RateProvider, error) {
+	name := strings.ToLower(strings.TrimSpace(cfg.Provider))
+	if name == "" {
+		name = DefaultProvider
+	}
+
+	registryMu.RLock()
+	factory, ok := registry[name]
+	registryMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown rate provider %q, available: %s",
+			name, strings.Join(Providers(), ", "))
+	}
+
+	provider, err := factory(cfg, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s provider: %w", name, err)
+	}
+
+	return provider, nil
+}
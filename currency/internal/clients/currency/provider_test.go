@@ -0,0 +1,59 @@
+package currency
+
+import (
+	"context"
+	"log/slog"
+	"my-currency-service/currency/internal/config"
+	"my-currency-service/currency/internal/dto"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubProvider struct{}
+
+func (stubProvider) Name() string { return "stub" }
+
+func (stubProvider) FetchRates(context.Context, *dto.CurrencyRequestDTO) ([]dto.RateRecordDTO, error) {
+	return nil, nil
+}
+
+func TestNewProvider_DefaultIsECB(t *testing.T) {
+	provider, err := NewProvider(config.APIConfig{}, slog.Default())
+
+	require.NoError(t, err)
+	assert.Equal(t, ProviderECB, provider.Name())
+}
+
+func TestNewProvider_CaseInsensitive(t *testing.T) {
+	provider, err := NewProvider(config.APIConfig{Provider: " ECB "}, slog.Default())
+
+	require.NoError(t, err)
+	assert.Equal(t, ProviderECB, provider.Name())
+}
+
+func TestNewProvider_Unknown(t *testing.T) {
+	provider, err := NewProvider(config.APIConfig{Provider: "nope"}, slog.Default())
+
+	require.Error(t, err)
+	assert.Nil(t, provider)
+	assert.Contains(t, err.Error(), `unknown rate provider "nope"`)
+}
+
+func TestRegister(t *testing.T) {
+	Register("stub", func(config.APIConfig, *slog.Logger) (RateProvider, error) {
+		return stubProvider{}, nil
+	})
+	t.Cleanup(func() {
+		registryMu.Lock()
+		delete(registry, "stub")
+		registryMu.Unlock()
+	})
+
+	provider, err := NewProvider(config.APIConfig{Provider: "stub"}, slog.Default())
+
+	require.NoError(t, err)
+	assert.Equal(t, "stub", provider.Name())
+	assert.Contains(t, Providers(), "stub")
+}
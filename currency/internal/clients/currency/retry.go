@@ -0,0 +1,229 @@
+package currency
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"my-currency-service/currency/internal/config"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultInitialBackoff = 500 * time.Millisecond
+	defaultMaxBackoff     = 30 * time.Second
+)
+
+// Причины отказа от повтора — метки счётчика abandoned
+const (
+	abandonedMaxAttempts = "max_attempts"
+	abandonedRetryAfter  = "retry_after"
+)
+
+// retryTransport repeats GET requests that failed with a network error, a
+// timeout or a retryable status. Between attempts it waits with exponential
+// backoff and jitter, or as long as Retry-After asks. Every attempt passes
+// the circuit breaker of the provider and has its own timeout.
+type retryTransport struct {
+	next           http.RoundTripper
+	provider       string
+	attempts       int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	attemptTimeout time.Duration
+	breaker        *breaker
+	sleep          func(ctx context.Context, d time.Duration) error
+	logger         *slog.Logger
+}
+
+func newRetryTransport(provider string, cfg config.APIConfig, next http.RoundTripper, logger *slog.Logger) *retryTransport {
+	attempts := cfg.Retry.MaxAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+	initialBackoff := cfg.Retry.InitialBackoff
+	if initialBackoff <= 0 {
+		initialBackoff = defaultInitialBackoff
+	}
+	maxBackoff := cfg.Retry.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = defaultMaxBackoff
+	}
+
+	return &retryTransport{
+		next:           next,
+		provider:       provider,
+		attempts:       attempts,
+		initialBackoff: initialBackoff,
+		maxBackoff:     maxBackoff,
+		attemptTimeout: time.Duration(cfg.TimeoutSeconds) * time.Second,
+		breaker:        newBreaker(provider, cfg.CircuitBreaker, logger),
+		sleep:          sleep,
+		logger:         logger,
+	}
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	attempts := t.attempts
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		// Тело запроса не перечитать, а повтор POST может задвоить действие
+		attempts = 1
+	}
+
+	for attempt := 1; ; attempt++ {
+		if err := t.breaker.allow(); err != nil {
+			return nil, err
+		}
+
+		resp, err := t.try(req)
+		if req.Context().Err() != nil {
+			// Запрос отменил вызывающий, о провайдере это ничего не говорит
+			t.breaker.release()
+			return resp, err
+		}
+
+		reason, retryable := retryReason(resp, err)
+		if !retryable {
+			t.breaker.success()
+			return resp, err
+		}
+		t.breaker.failure()
+
+		if attempt >= attempts {
+			if attempts > 1 {
+				incAbandoned(t.provider, abandonedMaxAttempts)
+			}
+			return resp, err
+		}
+
+		wait := t.backoff(attempt)
+		if after, ok := retryAfter(resp, time.Now()); ok {
+			if after > t.maxBackoff {
+				// Столько ждать внутри одного запуска задачи нет смысла
+				incAbandoned(t.provider, abandonedRetryAfter)
+				t.logger.WarnContext(req.Context(), "retry abandoned: Retry-After exceeds max_backoff",
+					slog.String("provider", t.provider),
+					slog.String("url", req.URL.Redacted()),
+					slog.Int("attempt", attempt),
+					slog.String("reason", reason),
+					slog.Duration("retry_after", after),
+					slog.Duration("max_backoff", t.maxBackoff))
+				return resp, err
+			}
+			wait = after
+		}
+		if resp != nil {
+			drain(resp.Body)
+		}
+
+		incRetries(t.provider, reason)
+		t.logger.WarnContext(req.Context(), "provider request failed, retrying",
+			slog.String("provider", t.provider),
+			slog.String("url", req.URL.Redacted()),
+			slog.Int("attempt", attempt),
+			slog.String("reason", reason),
+			slog.Duration("wait", wait))
+
+		if err := t.sleep(req.Context(), wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// try sends one attempt. Its timeout covers reading the body too, so the
+// context is cancelled when the body is closed.
+func (t *retryTransport) try(req *http.Request) (*http.Response, error) {
+	if t.attemptTimeout <= 0 {
+		return t.next.RoundTrip(req)
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), t.attemptTimeout)
+	resp, err := t.next.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+
+	return resp, nil
+}
+
+// backoff returns the pause before the repeat of attempt: initialBackoff
+// doubled per attempt up to maxBackoff, of which a random half is taken off
+// so that replicas do not hit the upstream at the same moment.
+func (t *retryTransport) backoff(attempt int) time.Duration {
+	d := t.initialBackoff
+	for i := 1; i < attempt && d < t.maxBackoff; i++ {
+		d *= 2
+	}
+	d = min(d, t.maxBackoff)
+
+	return d/2 + rand.N(d/2+1)
+}
+
+// retryReason classifies the outcome of an attempt; the reason is the
+// label of the retries counter.
+func retryReason(resp *http.Response, err error) (string, bool) {
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return "timeout", true
+		}
+		return "network", Retryable(err)
+	}
+	if retryableStatus(resp.StatusCode) {
+		return strconv.Itoa(resp.StatusCode), true
+	}
+	return "", false
+}
+
+// retryAfter parses the Retry-After header given in seconds or as an HTTP date.
+func retryAfter(resp *http.Response, now time.Time) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(at.Sub(now), 0), true
+	}
+
+	return 0, false
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// drain reads a little of an unused body so that the connection can be reused.
+func drain(body io.ReadCloser) {
+	_, _ = io.Copy(io.Discard, io.LimitReader(body, 4<<10))
+	_ = body.Close()
+}
+
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
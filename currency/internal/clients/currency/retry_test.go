@@ -0,0 +1,210 @@
+package currency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"my-currency-service/currency/internal/config"
+	"my-currency-service/currency/internal/dto"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFlakyServer answers with statuses in order and with 200 once they run out.
+func newFlakyServer(t *testing.T, header http.Header, statuses ...int) (*httptest.Server, *atomic.Int32) {
+	var calls atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(calls.Add(1))
+		if n <= len(statuses) {
+			for key, values := range header {
+				w.Header()[key] = values
+			}
+			w.WriteHeader(statuses[n-1])
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &calls
+}
+
+// newRetryClient returns a client whose pauses between attempts are recorded instead of slept.
+func newRetryClient(cfg config.APIConfig) (*http.Client, *retryTransport, *[]time.Duration) {
+	var waits []time.Duration
+
+	transport := newRetryTransport("test", cfg, http.DefaultTransport, slog.Default())
+	transport.sleep = func(_ context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}
+
+	return &http.Client{Transport: transport}, transport, &waits
+}
+
+func get(client *http.Client, url string) (int, error) {
+	resp, err := client.Get(url)
+	if err != nil {
+		return 0, err
+	}
+	drain(resp.Body)
+	return resp.StatusCode, nil
+}
+
+func TestRetryTransport_RetriesServerErrors(t *testing.T) {
+	server, calls := newFlakyServer(t, nil, http.StatusServiceUnavailable, http.StatusBadGateway)
+	client, _, waits := newRetryClient(config.APIConfig{Retry: config.RetryConfig{
+		MaxAttempts:    3,
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     time.Second,
+	}})
+
+	code, err := get(client, server.URL)
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, int32(3), calls.Load())
+	require.Len(t, *waits, 2)
+	// Полный шаг с вычетом случайной половины
+	assert.InDelta(t, 75*time.Millisecond, (*waits)[0], float64(25*time.Millisecond))
+	assert.InDelta(t, 150*time.Millisecond, (*waits)[1], float64(50*time.Millisecond))
+}
+
+func TestRetryTransport_GivesUpAfterMaxAttempts(t *testing.T) {
+	metrics := newTestMetrics(t)
+	server, calls := newFlakyServer(t, nil, 500, 500, 500, 500)
+	client, _, _ := newRetryClient(config.APIConfig{Retry: config.RetryConfig{MaxAttempts: 3}})
+
+	code, err := get(client, server.URL)
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusInternalServerError, code)
+	assert.Equal(t, int32(3), calls.Load())
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.abandoned.WithLabelValues("test", abandonedMaxAttempts)))
+}
+
+func TestRetryTransport_PermanentErrorNotRetried(t *testing.T) {
+	server, calls := newFlakyServer(t, nil, http.StatusNotFound)
+	client, _, waits := newRetryClient(config.APIConfig{Retry: config.RetryConfig{MaxAttempts: 3}})
+
+	code, err := get(client, server.URL)
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, code)
+	assert.Equal(t, int32(1), calls.Load())
+	assert.Empty(t, *waits)
+}
+
+func TestRetryTransport_HonoursRetryAfter(t *testing.T) {
+	server, calls := newFlakyServer(t, http.Header{"Retry-After": {"7"}}, http.StatusTooManyRequests)
+	client, _, waits := newRetryClient(config.APIConfig{Retry: config.RetryConfig{MaxAttempts: 2, MaxBackoff: 10 * time.Second}})
+
+	code, err := get(client, server.URL)
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, int32(2), calls.Load())
+	assert.Equal(t, []time.Duration{7 * time.Second}, *waits)
+}
+
+func TestRetryTransport_RetryAfterBeyondMaxBackoff(t *testing.T) {
+	metrics := newTestMetrics(t)
+	server, calls := newFlakyServer(t, http.Header{"Retry-After": {"3600"}}, http.StatusServiceUnavailable)
+	client, _, waits := newRetryClient(config.APIConfig{Retry: config.RetryConfig{MaxAttempts: 3, MaxBackoff: 30 * time.Second}})
+
+	code, err := get(client, server.URL)
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, int32(1), calls.Load())
+	assert.Empty(t, *waits)
+	// Отказ отличим от исчерпанных попыток
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.abandoned.WithLabelValues("test", abandonedRetryAfter)))
+	assert.Zero(t, testutil.ToFloat64(metrics.abandoned.WithLabelValues("test", abandonedMaxAttempts)))
+}
+
+func TestRetryTransport_RetriesAttemptTimeout(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			<-r.Context().Done()
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	t.Cleanup(server.Close)
+
+	client, transport, _ := newRetryClient(config.APIConfig{Retry: config.RetryConfig{MaxAttempts: 2}})
+	transport.attemptTimeout = 50 * time.Millisecond
+
+	code, err := get(client, server.URL)
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	for value, want := range map[string]time.Duration{
+		"120":                           2 * time.Minute,
+		"Wed, 01 May 2024 12:00:30 GMT": 30 * time.Second,
+		"Wed, 01 May 2024 11:00:00 GMT": 0,
+	} {
+		got, ok := retryAfter(&http.Response{Header: http.Header{"Retry-After": {value}}}, now)
+		assert.True(t, ok, value)
+		assert.Equal(t, want, got, value)
+	}
+
+	_, ok := retryAfter(&http.Response{Header: http.Header{"Retry-After": {"soon"}}}, now)
+	assert.False(t, ok)
+}
+
+func TestRetryable(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{&StatusError{StatusCode: 503, Status: "503 Service Unavailable"}, true},
+		{fmt.Errorf("fetch: %w", &StatusError{StatusCode: 429}), true},
+		{&StatusError{StatusCode: 404, Status: "404 Not Found"}, false},
+		{&StatusError{StatusCode: 501}, false},
+		{fmt.Errorf("ecb: %w", ErrCircuitOpen), true},
+		{context.DeadlineExceeded, true},
+		{context.Canceled, false},
+		{errors.New("failed to parse XML"), false},
+		{nil, false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, Retryable(tt.err), "%v", tt.err)
+	}
+}
+
+func TestJSON_FetchRates_ServerErrorIsRetryable(t *testing.T) {
+	server, _ := newFlakyServer(t, nil, 500, 500)
+	client, err := NewJSON(config.APIConfig{BaseURL: server.URL + "/%s", TimeoutSeconds: 5}, slog.Default())
+	require.NoError(t, err)
+
+	day := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	_, err = client.FetchRates(context.Background(), &dto.CurrencyRequestDTO{
+		BaseCurrency: "EUR",
+		DateFrom:     day,
+		DateTo:       day,
+	})
+
+	var statusErr *StatusError
+	require.ErrorAs(t, err, &statusErr)
+	assert.Equal(t, http.StatusInternalServerError, statusErr.StatusCode)
+	assert.True(t, Retryable(err))
+}
@@ -4,19 +4,65 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
 )
 
 type ServiceConfig struct {
-	ServerPort int    `yaml:"server_port"`
-	Env        string `yaml:"env"`
+	ServerPort int `yaml:"server_port"`
+	// Порт HTTP/JSON-шлюза; 0 — шлюз выключен
+	HTTPPort int       `yaml:"http_port"`
+	Env      string    `yaml:"env"`
+	TLS      TLSConfig `yaml:"tls"`
+}
+
+// TLSConfig configures TLS of the gRPC listener. The files are re-read
+// when they change, so certificates can be rotated without a restart.
+type TLSConfig struct {
+	Enabled  bool   `yaml:"enabled"`
+	CertFile string `yaml:"cert_file"`
+	KeyFile  string `yaml:"key_file"`
+	// CA-бандл для проверки клиентских сертификатов (mTLS); пустой — сертификаты не запрашиваются
+	ClientCAFile string `yaml:"client_ca_file"`
+	// Без сертификата соединение отклоняется; иначе сертификат проверяется, если клиент его передал
+	RequireClientCert bool `yaml:"require_client_cert"`
+	// Период проверки файлов на изменение; по умолчанию 30s
+	ReloadInterval time.Duration `yaml:"reload_interval"`
 }
 
 type APIConfig struct {
+	Provider       string `yaml:"provider"`
 	BaseURL        string `yaml:"base_url"`
 	TimeoutSeconds int    `yaml:"timeout_seconds"`
 	SkipVerify     bool   `yaml:"skip_verify"`
+	// Повторы запросов к провайдеру при сбоях сети, 5xx и 429
+	Retry RetryConfig `yaml:"retry"`
+	// Пока провайдер лежит, запросы к нему не отправляются
+	CircuitBreaker CircuitBreakerConfig `yaml:"circuit_breaker"`
+}
+
+// RetryConfig repeats a provider request that failed with a network error,
+// a timeout, 408, 429 or a 5xx response. Other errors are returned at once.
+type RetryConfig struct {
+	// Попыток на запрос, включая первую; 0 и 1 — без повторов
+	MaxAttempts int `yaml:"max_attempts"`
+	// Пауза перед первым повтором, дальше удваивается; по умолчанию 500ms
+	InitialBackoff time.Duration `yaml:"initial_backoff"`
+	// Предел паузы; Retry-After больше него не ждётся. По умолчанию 30s
+	MaxBackoff time.Duration `yaml:"max_backoff"`
+}
+
+// CircuitBreakerConfig opens the circuit of a provider after a series of
+// failed requests. While it is open requests fail without reaching the
+// upstream; after OpenTimeout one probe request decides whether to close it.
+type CircuitBreakerConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Неудачных запросов подряд до размыкания; по умолчанию 5
+	FailureThreshold int `yaml:"failure_threshold"`
+	// Через сколько после размыкания пропустить пробный запрос; по умолчанию 1m
+	OpenTimeout time.Duration `yaml:"open_timeout"`
 }
 
 type DatabaseConfig struct {
@@ -29,18 +75,184 @@ type DatabaseConfig struct {
 }
 
 type WorkerConfig struct {
-	Schedule     string `yaml:"schedule"`
-	CurrencyPair struct {
-		BaseCurrency   string `yaml:"base_currency"`
-		TargetCurrency string `yaml:"target_currency"`
-	} `yaml:"currency_pair"`
+	Jobs      []WorkerJobConfig `yaml:"jobs"`
+	Reconcile ReconcileConfig   `yaml:"reconcile"`
+	// Порт /metrics процесса cron; 0 — метрики не публикуются
+	MetricsPort int              `yaml:"metrics_port"`
+	Lock        LockConfig       `yaml:"lock"`
+	History     RunHistoryConfig `yaml:"history"`
+}
+
+// RunHistoryConfig configures the job_runs table: every fetch of a pair by
+// a job is recorded there.
+type RunHistoryConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Срок хранения записей; по умолчанию 90 дней
+	Retention time.Duration `yaml:"retention"`
+	// Расписание удаления старых записей; по умолчанию "@daily"
+	PruneSchedule string `yaml:"prune_schedule"`
+}
+
+// LockConfig lets several cron replicas share the jobs: each job runs on
+// the replica holding its Postgres advisory lock.
+type LockConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Имя реплики в логах, метриках и pg_stat_activity; по умолчанию hostname
+	ReplicaID string `yaml:"replica_id"`
+}
+
+// ReconcileConfig configures the search and repair of missing fixings of
+// the worker jobs.
+type ReconcileConfig struct {
+	// Пустое расписание выключает сверку
+	Schedule string `yaml:"schedule"`
+	// Сколько дней до вчерашнего проверять; по умолчанию 30
+	LookbackDays int `yaml:"lookback_days"`
+}
+
+// WorkerJobConfig fetches rates of one base currency against several
+// targets on its own schedule.
+type WorkerJobConfig struct {
+	// Имя задачи в логах; по умолчанию "<provider>:<base>"
+	Name             string   `yaml:"name"`
+	BaseCurrency     string   `yaml:"base_currency"`
+	TargetCurrencies []string `yaml:"target_currencies"`
+	// Пустой — api.provider
+	Provider string `yaml:"provider"`
+	// Провайдеры по приоритету: если первый не ответил, курс берётся у
+	// следующего. Задаётся вместо provider
+	Providers []string `yaml:"providers"`
+	// Вместо отката курс запрашивается у всех providers и сверяется
+	Consensus ConsensusConfig `yaml:"consensus"`
+	// Cron-выражение или дескриптор вида "@daily"
+	Schedule string `yaml:"schedule"`
+	// Время на загрузку одной пары; по умолчанию 30s
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+// ConsensusConfig fetches the rates of a job from all its providers and
+// stores a rate only if enough of them agree on it. The stored value is the
+// one of the first provider in the list among those that agree.
+type ConsensusConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Допустимое отклонение от медианы, доля: 0.005 — 0.5%. По умолчанию 0.01
+	Tolerance float64 `yaml:"tolerance"`
+	// Сколько провайдеров должны сойтись; по умолчанию 2
+	MinSources int `yaml:"min_sources"`
+}
+
+// ProviderNames returns the providers of the job in priority order; nil
+// means api.provider.
+func (c WorkerJobConfig) ProviderNames() []string {
+	if len(c.Providers) > 0 {
+		return c.Providers
+	}
+	if c.Provider != "" {
+		return []string{c.Provider}
+	}
+	return nil
+}
+
+// ProviderKey identifies the providers of the job and the way they are
+// combined; jobs with the same key share the rate service. "" is api.provider.
+func (c WorkerJobConfig) ProviderKey() string {
+	key := strings.ToLower(strings.Join(c.ProviderNames(), ","))
+	if c.Consensus.Enabled {
+		key = "consensus:" + key
+	}
+	return key
+}
+
+type RatesConfig struct {
+	// Валюта, через которую считаются кросс-курсы; по умолчанию EUR
+	PivotCurrency string `yaml:"pivot_currency"`
+	// Курс устаревший, если не сохранён фиксинг, который по календарю
+	// провайдера должен был выйти больше max_age назад
+	MaxAge time.Duration `yaml:"max_age"`
+	// Пороги для отдельных пар, ключ вида "EUR/USD"
+	PairMaxAge map[string]time.Duration `yaml:"pair_max_age"`
+}
+
+// CalendarConfig adjusts the publishing calendars of the providers.
+type CalendarConfig struct {
+	// Календарь провайдера вместо встроенного, ключ — провайдер:
+	// target2, russia, cbr, fed, weekdays, every_day
+	Providers map[string]string `yaml:"providers"`
+	// YAML-файлы с праздниками и рабочими днями, ключ — рынок: target2, russia, fed
+	HolidayFiles map[string][]string `yaml:"holiday_files"`
+}
+
+type AuthConfig struct {
+	// Без аутентификации сервер доступен любому, кто дотянется до порта
+	Enabled bool           `yaml:"enabled"`
+	APIKeys []APIKeyConfig `yaml:"api_keys"`
+	// YAML-файл со списком ключей того же формата, что и api_keys
+	APIKeysFile string    `yaml:"api_keys_file"`
+	JWT         JWTConfig `yaml:"jwt"`
+	// Клиенты, прошедшие mTLS, по CommonName сертификата
+	ClientCerts []ClientCertConfig `yaml:"client_certs"`
+}
+
+type APIKeyConfig struct {
+	Name string `yaml:"name"`
+	// Хеш ключа вида "sha256:<hex>", сам ключ в конфиге не хранится
+	Hash   string   `yaml:"hash"`
+	Scopes []string `yaml:"scopes"`
+}
+
+type ClientCertConfig struct {
+	// CommonName сертификата клиента
+	Subject string   `yaml:"subject"`
+	Scopes  []string `yaml:"scopes"`
+}
+
+type JWTConfig struct {
+	// Секрет для HS256; пустой — HS256 принимается только по ключам из JWKS
+	HS256Secret string `yaml:"hs256_secret"`
+	// Локальный JWKS-файл с ключами RS256 (и oct-ключами HS256)
+	JWKSFile string `yaml:"jwks_file"`
+	Issuer   string `yaml:"issuer"`
+	Audience string `yaml:"audience"`
+}
+
+type RateLimitConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Лимит методов, для которых не задан собственный
+	Default LimitConfig `yaml:"default"`
+	// Ключ — имя метода, например "BatchGetRates"
+	Methods map[string]LimitConfig `yaml:"methods"`
+}
+
+// LimitConfig is a token bucket and a daily quota of one client on one method.
+type LimitConfig struct {
+	// 0 — без ограничения частоты
+	RatePerSecond float64 `yaml:"rate_per_second"`
+	Burst         int     `yaml:"burst"`
+	// Запросов в сутки (UTC); 0 — без квоты
+	DailyQuota int `yaml:"daily_quota"`
 }
 
 type AppConfig struct {
-	Service  ServiceConfig  `yaml:"service"`
-	API      APIConfig      `yaml:"api"`
-	Database DatabaseConfig `yaml:"database"`
-	Worker   WorkerConfig   `yaml:"worker"`
+	Service   ServiceConfig   `yaml:"service"`
+	API       APIConfig       `yaml:"api"`
+	Database  DatabaseConfig  `yaml:"database"`
+	Worker    WorkerConfig    `yaml:"worker"`
+	Rates     RatesConfig     `yaml:"rates"`
+	Calendar  CalendarConfig  `yaml:"calendar"`
+	Auth      AuthConfig      `yaml:"auth"`
+	RateLimit RateLimitConfig `yaml:"rate_limit"`
+}
+
+// ForProvider returns the api section for another provider. BaseURL
+// belongs to the configured provider, others fall back to their default.
+func (c APIConfig) ForProvider(provider string) APIConfig {
+	if provider == "" || strings.EqualFold(provider, c.Provider) {
+		return c
+	}
+
+	c.Provider = provider
+	c.BaseURL = ""
+	return c
 }
 
 func (dc DatabaseConfig) ToDSN() string {
@@ -1,13 +1,17 @@
 package dto
 
 import (
+	"fmt"
+	"my-currency-service/currency/internal/iso4217"
 	"my-currency-service/pkg/currency"
 	"time"
 
+	"github.com/shopspring/decimal"
+	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
-// TODO: вынести в cfg.Worker.CurrencyPair.BaseCurrency и передавать в
+// TODO: вынести в конфиг и передавать в
 // CurrencyRequestDTOFromProtobuf как параметр — потребует рефактор handler и его тестов
 const (
 	DefaultBaseCurrency = "USD"
@@ -20,14 +24,125 @@ type CurrencyRequestDTO struct {
 	DateTo         time.Time
 }
 
+// RateQueryDTO asks for the rate of a pair applicable on Date:
+// the latest observation on or before it.
+type RateQueryDTO struct {
+	BaseCurrency   string
+	TargetCurrency string
+	Date           time.Time
+}
+
+// CurrencyPairDTO identifies a pair: rates of BaseCurrency in TargetCurrency.
+type CurrencyPairDTO struct {
+	BaseCurrency   string
+	TargetCurrency string
+}
+
+// GapQueryDTO asks for the publishing days of a pair in [DateFrom, DateTo]
+// that have no stored observation. Provider selects the publishing
+// calendar; empty means the provider of the service.
+type GapQueryDTO struct {
+	Provider       string
+	BaseCurrency   string
+	TargetCurrency string
+	DateFrom       time.Time
+	DateTo         time.Time
+}
+
+// GapReportDTO lists the missing observations of a pair.
+type GapReportDTO struct {
+	Provider       string
+	BaseCurrency   string
+	TargetCurrency string
+	DateFrom       time.Time
+	DateTo         time.Time
+	ExpectedDays   int
+	Missing        []time.Time
+}
+
+// Статусы запуска задания
+const (
+	JobRunSuccess = "success"
+	JobRunFailure = "failure"
+)
+
+// JobRunDTO is the fetch of one pair in one run of a worker job.
+type JobRunDTO struct {
+	ID             int64
+	Job            string
+	Trigger        string
+	Provider       string
+	BaseCurrency   string
+	TargetCurrency string
+	StartedAt      time.Time
+	FinishedAt     time.Time
+	Status         string
+	Error          string
+	RowsWritten    int
+}
+
+// JobRunQueryDTO filters the run history; empty fields match every run.
+type JobRunQueryDTO struct {
+	Job            string
+	BaseCurrency   string
+	TargetCurrency string
+	Status         string
+	Limit          int
+}
+
+type ConvertRequestDTO struct {
+	Amount decimal.Decimal
+	From   string
+	To     string
+	AsOf   time.Time
+}
+
+// RoundingHalfAwayFromZero is the rounding of converted amounts:
+// 0.125 -> 0.13, -0.125 -> -0.13.
+const RoundingHalfAwayFromZero = "HALF_AWAY_FROM_ZERO"
+
+// RoundingNone means the converted amount is not rounded: ISO 4217 defines
+// no minor units for the target currency, e.g. XAU.
+const RoundingNone = "NONE"
+
+type ConversionDTO struct {
+	Amount          decimal.Decimal
+	UnroundedAmount decimal.Decimal
+	From            string
+	To              string
+	Rate            decimal.Decimal
+	RateDate        time.Time
+	FixingDate      time.Time
+	MinorUnits      int32
+	RoundingMode    string
+}
+
 type CurrencyResponseDTO struct {
 	Currency string
 	Rates    []RateRecordDTO
 }
 
+// RateRecordDTO is a single observation: one unit of BaseCurrency
+// is worth Value units of TargetCurrency on Date.
 type RateRecordDTO struct {
-	Date  time.Time
-	Value float32
+	Date           time.Time
+	BaseCurrency   string
+	TargetCurrency string
+	Value          decimal.Decimal
+	// Происхождение курса, заполняется при загрузке: провайдер, чьё значение
+	// сохранено, ключ ряда у источника, время загрузки и SHA-256 ответа в hex
+	Provider    string
+	SeriesKey   string
+	FetchedAt   time.Time
+	PayloadHash string
+}
+
+// FetchResultDTO is the outcome of a fetch of one pair by a worker job.
+type FetchResultDTO struct {
+	Rows int
+	// Провайдеры сохранённых курсов через запятую; при откате по цепочке
+	// это тот, кто ответил
+	Provider string
 }
 
 func CurrencyRequestDTOFromProtobuf(req *currency.GetRateRequest) *CurrencyRequestDTO {
@@ -43,16 +158,167 @@ func CurrencyRequestDTOFromProtobuf(req *currency.GetRateRequest) *CurrencyReque
 	}
 }
 
+// LatestRateRequestDTOFromProtobuf builds a request without a period:
+// only the pair is used to look up the newest observation.
+func LatestRateRequestDTOFromProtobuf(req *currency.GetLatestRateRequest) *CurrencyRequestDTO {
+	baseCurrency := req.BaseCurrency
+	if baseCurrency == "" {
+		baseCurrency = DefaultBaseCurrency
+	}
+	return &CurrencyRequestDTO{
+		BaseCurrency:   baseCurrency,
+		TargetCurrency: req.Currency,
+	}
+}
+
+func RateQueryDTOFromProtobuf(query *currency.RateQuery) RateQueryDTO {
+	baseCurrency := query.GetBaseCurrency()
+	if baseCurrency == "" {
+		baseCurrency = DefaultBaseCurrency
+	}
+	return RateQueryDTO{
+		BaseCurrency:   baseCurrency,
+		TargetCurrency: query.GetCurrency(),
+		Date:           query.GetDate().AsTime(),
+	}
+}
+
+func CurrencyPairDTOFromProtobuf(pair *currency.CurrencyPair) CurrencyPairDTO {
+	baseCurrency := pair.GetBaseCurrency()
+	if baseCurrency == "" {
+		baseCurrency = DefaultBaseCurrency
+	}
+	return CurrencyPairDTO{
+		BaseCurrency:   baseCurrency,
+		TargetCurrency: pair.GetCurrency(),
+	}
+}
+
+// GapQueryDTOsFromProtobuf builds one query per requested pair. Unset
+// dates stay zero and are defaulted by the service.
+func GapQueryDTOsFromProtobuf(req *currency.GetGapReportRequest) []GapQueryDTO {
+	var dateFrom, dateTo time.Time
+	if req.GetDateFrom() != nil {
+		dateFrom = req.GetDateFrom().AsTime()
+	}
+	if req.GetDateTo() != nil {
+		dateTo = req.GetDateTo().AsTime()
+	}
+
+	queries := make([]GapQueryDTO, 0, len(req.GetPairs()))
+	for _, pair := range req.GetPairs() {
+		pairDTO := CurrencyPairDTOFromProtobuf(pair)
+		queries = append(queries, GapQueryDTO{
+			Provider:       req.GetProvider(),
+			BaseCurrency:   pairDTO.BaseCurrency,
+			TargetCurrency: pairDTO.TargetCurrency,
+			DateFrom:       dateFrom,
+			DateTo:         dateTo,
+		})
+	}
+	return queries
+}
+
+func (dto *GapReportDTO) ToProtobuf() *currency.GapReport {
+	missing := make([]*timestamppb.Timestamp, 0, len(dto.Missing))
+	for _, date := range dto.Missing {
+		missing = append(missing, timestamppb.New(date))
+	}
+
+	return &currency.GapReport{
+		Provider:     dto.Provider,
+		BaseCurrency: dto.BaseCurrency,
+		Currency:     dto.TargetCurrency,
+		DateFrom:     timestamppb.New(dto.DateFrom),
+		DateTo:       timestamppb.New(dto.DateTo),
+		ExpectedDays: int32(dto.ExpectedDays),
+		MissingDates: missing,
+	}
+}
+
+func JobRunQueryDTOFromProtobuf(req *currency.GetJobRunsRequest) *JobRunQueryDTO {
+	return &JobRunQueryDTO{
+		Job:            req.GetJob(),
+		BaseCurrency:   req.GetBaseCurrency(),
+		TargetCurrency: req.GetCurrency(),
+		Status:         req.GetStatus(),
+		Limit:          int(req.GetLimit()),
+	}
+}
+
+func (dto *JobRunDTO) ToProtobuf() *currency.JobRun {
+	return &currency.JobRun{
+		Id:           dto.ID,
+		Job:          dto.Job,
+		Trigger:      dto.Trigger,
+		Provider:     dto.Provider,
+		BaseCurrency: dto.BaseCurrency,
+		Currency:     dto.TargetCurrency,
+		StartedAt:    timestamppb.New(dto.StartedAt),
+		FinishedAt:   timestamppb.New(dto.FinishedAt),
+		Duration:     durationpb.New(dto.FinishedAt.Sub(dto.StartedAt)),
+		Status:       dto.Status,
+		Error:        dto.Error,
+		RowsWritten:  int32(dto.RowsWritten),
+	}
+}
+
+// ConvertRequestDTOFromProtobuf parses the request amount; an empty
+// or malformed amount is returned as an error.
+func ConvertRequestDTOFromProtobuf(req *currency.ConvertRequest) (*ConvertRequestDTO, error) {
+	amount, err := decimal.NewFromString(req.Amount)
+	if err != nil {
+		return nil, fmt.Errorf("invalid amount %q: %w", req.Amount, err)
+	}
+
+	var asOf time.Time
+	if req.AsOf != nil {
+		asOf = req.AsOf.AsTime()
+	}
+
+	return &ConvertRequestDTO{
+		Amount: amount,
+		From:   req.From,
+		To:     req.To,
+		AsOf:   asOf,
+	}, nil
+}
+
+func (dto *ConversionDTO) ToProtobuf() *currency.ConvertResponse {
+	return &currency.ConvertResponse{
+		Amount:          dto.amountString(),
+		From:            dto.From,
+		To:              dto.To,
+		Rate:            dto.Rate.String(),
+		RateDate:        timestamppb.New(dto.RateDate),
+		FixingDate:      timestamppb.New(dto.FixingDate),
+		MinorUnits:      dto.MinorUnits,
+		RoundingMode:    dto.RoundingMode,
+		UnroundedAmount: dto.UnroundedAmount.String(),
+	}
+}
+
+func (dto *ConversionDTO) amountString() string {
+	if dto.MinorUnits == iso4217.NoMinorUnits {
+		return dto.Amount.String()
+	}
+	return dto.Amount.StringFixed(dto.MinorUnits)
+}
+
 func (dto *CurrencyResponseDTO) ToProtobuf() *currency.GetRateResponse {
 	rateRecords := make([]*currency.RateRecord, 0, len(dto.Rates))
 
 	for _, record := range dto.Rates {
-		rateRecords = append(
-			rateRecords, &currency.RateRecord{
-				Date: timestamppb.New(record.Date),
-				Rate: record.Value,
-			},
-		)
+		rateRecord := &currency.RateRecord{
+			Date:      timestamppb.New(record.Date),
+			Rate:      float32(record.Value.InexactFloat64()),
+			ExactRate: record.Value.String(),
+			Provider:  record.Provider,
+		}
+		if !record.FetchedAt.IsZero() {
+			rateRecord.FetchedAt = timestamppb.New(record.FetchedAt)
+		}
+		rateRecords = append(rateRecords, rateRecord)
 	}
 
 	return &currency.GetRateResponse{
@@ -0,0 +1,166 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"my-currency-service/currency/internal/dto"
+	"sync"
+)
+
+var (
+	// ErrSlowConsumer closes a subscription whose buffer overflowed:
+	// dropping single events silently would leave the consumer with gaps.
+	ErrSlowConsumer = errors.New("subscriber is too slow")
+	ErrBusClosed    = errors.New("event bus is closed")
+)
+
+// Notifier forwards published observations to other processes.
+type Notifier interface {
+	Notify(ctx context.Context, records []dto.RateRecordDTO) error
+}
+
+// Bus fans stored observations out to the subscribers of the process.
+// Publishing never blocks on a subscriber.
+type Bus struct {
+	mu          sync.Mutex
+	subscribers map[*Subscription]struct{}
+	closed      bool
+
+	notifier Notifier
+	logger   *slog.Logger
+}
+
+// NewBus creates a bus. notifier may be nil when observations
+// are consumed in the publishing process only.
+func NewBus(notifier Notifier, logger *slog.Logger) *Bus {
+	return &Bus{
+		subscribers: make(map[*Subscription]struct{}),
+		notifier:    notifier,
+		logger:      logger,
+	}
+}
+
+// Publish delivers records to local subscribers and forwards them
+// through the notifier.
+func (b *Bus) Publish(ctx context.Context, records []dto.RateRecordDTO) {
+	b.Deliver(records)
+
+	if b.notifier == nil || len(records) == 0 {
+		return
+	}
+	if err := b.notifier.Notify(ctx, records); err != nil {
+		b.logger.ErrorContext(ctx, "failed to notify rate subscribers",
+			slog.Int("count", len(records)),
+			slog.Any("error", err))
+	}
+}
+
+// Deliver delivers records to local subscribers only. A subscriber
+// whose buffer is full is closed with ErrSlowConsumer.
+func (b *Bus) Deliver(records []dto.RateRecordDTO) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for sub := range b.subscribers {
+		for _, record := range records {
+			if !sub.matches(record) {
+				continue
+			}
+
+			select {
+			case sub.events <- record:
+				continue
+			default:
+			}
+
+			b.logger.Warn("dropping slow rate subscriber", slog.Int("buffer", cap(sub.events)))
+			b.removeLocked(sub, ErrSlowConsumer)
+			break
+		}
+	}
+}
+
+// Subscribe returns a subscription to observations of pairs with room
+// for buffer undelivered events.
+func (b *Bus) Subscribe(pairs []dto.CurrencyPairDTO, buffer int) *Subscription {
+	sub := &Subscription{
+		bus:    b,
+		pairs:  make(map[dto.CurrencyPairDTO]struct{}, len(pairs)),
+		events: make(chan dto.RateRecordDTO, buffer),
+	}
+	for _, pair := range pairs {
+		sub.pairs[pair] = struct{}{}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		sub.err = ErrBusClosed
+		close(sub.events)
+		return sub
+	}
+
+	b.subscribers[sub] = struct{}{}
+	return sub
+}
+
+// Close closes every subscription with ErrBusClosed so that streams
+// end before the server stops.
+func (b *Bus) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for sub := range b.subscribers {
+		b.removeLocked(sub, ErrBusClosed)
+	}
+}
+
+func (b *Bus) removeLocked(sub *Subscription, err error) {
+	if _, ok := b.subscribers[sub]; !ok {
+		return
+	}
+	delete(b.subscribers, sub)
+	sub.err = err
+	close(sub.events)
+}
+
+// Subscription receives observations of the subscribed pairs.
+type Subscription struct {
+	bus    *Bus
+	pairs  map[dto.CurrencyPairDTO]struct{}
+	events chan dto.RateRecordDTO
+	err    error
+}
+
+// Events is closed when the subscription ends; Err tells why.
+func (s *Subscription) Events() <-chan dto.RateRecordDTO {
+	return s.events
+}
+
+// Err returns the reason the subscription ended, nil if it was
+// closed by the subscriber. Valid once Events is closed.
+func (s *Subscription) Err() error {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+
+	return s.err
+}
+
+// Close ends the subscription. It is safe to call more than once.
+func (s *Subscription) Close() {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+
+	s.bus.removeLocked(s, nil)
+}
+
+func (s *Subscription) matches(record dto.RateRecordDTO) bool {
+	_, ok := s.pairs[dto.CurrencyPairDTO{
+		BaseCurrency:   record.BaseCurrency,
+		TargetCurrency: record.TargetCurrency,
+	}]
+	return ok
+}
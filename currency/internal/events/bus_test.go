@@ -0,0 +1,131 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"my-currency-service/currency/internal/dto"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var eurUSD = dto.CurrencyPairDTO{BaseCurrency: "EUR", TargetCurrency: "USD"}
+
+type recordingNotifier struct {
+	records []dto.RateRecordDTO
+	err     error
+}
+
+func (n *recordingNotifier) Notify(_ context.Context, records []dto.RateRecordDTO) error {
+	n.records = append(n.records, records...)
+	return n.err
+}
+
+func record(base, target, value string) dto.RateRecordDTO {
+	return dto.RateRecordDTO{
+		Date:           time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC),
+		BaseCurrency:   base,
+		TargetCurrency: target,
+		Value:          decimal.RequireFromString(value),
+	}
+}
+
+func TestBus_DeliversSubscribedPairs(t *testing.T) {
+	notifier := &recordingNotifier{}
+	bus := NewBus(notifier, slog.Default())
+
+	sub := bus.Subscribe([]dto.CurrencyPairDTO{eurUSD}, 4)
+	defer sub.Close()
+
+	bus.Publish(context.Background(), []dto.RateRecordDTO{
+		record("EUR", "USD", "1.03"),
+		record("EUR", "GBP", "0.83"),
+	})
+
+	require.Len(t, sub.Events(), 1)
+	assert.Equal(t, "1.03", (<-sub.Events()).Value.String())
+
+	// Другим процессам уходят все записи
+	assert.Len(t, notifier.records, 2)
+}
+
+func TestBus_DeliverDoesNotNotify(t *testing.T) {
+	notifier := &recordingNotifier{}
+	bus := NewBus(notifier, slog.Default())
+
+	bus.Deliver([]dto.RateRecordDTO{record("EUR", "USD", "1.03")})
+
+	assert.Empty(t, notifier.records)
+}
+
+func TestBus_NotifierErrorDoesNotBlockDelivery(t *testing.T) {
+	bus := NewBus(&recordingNotifier{err: errors.New("connection refused")}, slog.Default())
+
+	sub := bus.Subscribe([]dto.CurrencyPairDTO{eurUSD}, 1)
+	defer sub.Close()
+
+	bus.Publish(context.Background(), []dto.RateRecordDTO{record("EUR", "USD", "1.03")})
+
+	assert.Len(t, sub.Events(), 1)
+}
+
+func TestBus_DropsSlowConsumer(t *testing.T) {
+	bus := NewBus(nil, slog.Default())
+
+	slow := bus.Subscribe([]dto.CurrencyPairDTO{eurUSD}, 1)
+	fast := bus.Subscribe([]dto.CurrencyPairDTO{eurUSD}, 4)
+	defer fast.Close()
+
+	bus.Deliver([]dto.RateRecordDTO{record("EUR", "USD", "1.03"), record("EUR", "USD", "1.04")})
+
+	// Буферизованное событие дочитывается, затем канал закрыт
+	assert.Equal(t, "1.03", (<-slow.Events()).Value.String())
+	_, ok := <-slow.Events()
+	assert.False(t, ok)
+	assert.ErrorIs(t, slow.Err(), ErrSlowConsumer)
+
+	// Медленный подписчик не влияет на остальных
+	assert.Len(t, fast.Events(), 2)
+	assert.NoError(t, fast.Err())
+
+	// Повторное закрытие безопасно
+	slow.Close()
+	assert.ErrorIs(t, slow.Err(), ErrSlowConsumer)
+}
+
+func TestBus_Close(t *testing.T) {
+	bus := NewBus(nil, slog.Default())
+
+	sub := bus.Subscribe([]dto.CurrencyPairDTO{eurUSD}, 1)
+	bus.Close()
+
+	_, ok := <-sub.Events()
+	assert.False(t, ok)
+	assert.ErrorIs(t, sub.Err(), ErrBusClosed)
+
+	late := bus.Subscribe([]dto.CurrencyPairDTO{eurUSD}, 1)
+	_, ok = <-late.Events()
+	assert.False(t, ok)
+	assert.ErrorIs(t, late.Err(), ErrBusClosed)
+
+	// Публикация после закрытия не паникует
+	bus.Deliver([]dto.RateRecordDTO{record("EUR", "USD", "1.03")})
+}
+
+func TestSubscription_Close(t *testing.T) {
+	bus := NewBus(nil, slog.Default())
+
+	sub := bus.Subscribe([]dto.CurrencyPairDTO{eurUSD}, 1)
+	sub.Close()
+	sub.Close()
+
+	_, ok := <-sub.Events()
+	assert.False(t, ok)
+	assert.NoError(t, sub.Err())
+
+	bus.Deliver([]dto.RateRecordDTO{record("EUR", "USD", "1.03")})
+}
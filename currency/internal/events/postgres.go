@@ -0,0 +1,126 @@
+package events
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"my-currency-service/currency/internal/dto"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/shopspring/decimal"
+)
+
+// Канал LISTEN/NOTIFY, через который cron передаёт сохранённые курсы серверу
+const postgresChannel = "exchange_rates"
+
+const (
+	listenerMinReconnect = 10 * time.Second
+	listenerMaxReconnect = time.Minute
+
+	// Без пинга обрыв соединения может остаться незамеченным
+	listenerPingInterval = 90 * time.Second
+)
+
+// rateEvent is the NOTIFY payload of one observation.
+type rateEvent struct {
+	Date           time.Time       `json:"date"`
+	BaseCurrency   string          `json:"base_currency"`
+	TargetCurrency string          `json:"target_currency"`
+	Rate           decimal.Decimal `json:"rate"`
+	// Нет в событиях процессов, запущенных до появления полей
+	Provider  string    `json:"provider,omitempty"`
+	FetchedAt time.Time `json:"fetched_at,omitzero"`
+}
+
+// PostgresNotifier forwards observations to other processes with
+// pg_notify, one notification per observation.
+type PostgresNotifier struct {
+	db *sql.DB
+}
+
+func NewPostgresNotifier(db *sql.DB) *PostgresNotifier {
+	return &PostgresNotifier{db: db}
+}
+
+func (n *PostgresNotifier) Notify(ctx context.Context, records []dto.RateRecordDTO) error {
+	payloads := make([]string, len(records))
+	for i, record := range records {
+		payload, err := json.Marshal(rateEvent{
+			Date:           record.Date,
+			BaseCurrency:   record.BaseCurrency,
+			TargetCurrency: record.TargetCurrency,
+			Rate:           record.Value,
+			Provider:       record.Provider,
+			FetchedAt:      record.FetchedAt,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to encode rate event: %w", err)
+		}
+		payloads[i] = string(payload)
+	}
+
+	query := `SELECT pg_notify($1, payload) FROM unnest($2::text[]) AS payload`
+
+	if _, err := n.db.ExecContext(ctx, query, postgresChannel, pq.Array(payloads)); err != nil {
+		return fmt.Errorf("failed to notify: %w", err)
+	}
+
+	return nil
+}
+
+// ListenPostgres delivers observations notified by other processes to bus
+// until ctx is done. The listener reconnects on its own; notifications
+// sent while it is disconnected are lost.
+func ListenPostgres(ctx context.Context, dsn string, bus *Bus, logger *slog.Logger) error {
+	listener := pq.NewListener(dsn, listenerMinReconnect, listenerMaxReconnect,
+		func(event pq.ListenerEventType, err error) {
+			if err != nil {
+				logger.Error("rate listener connection event", slog.Int("event", int(event)), slog.Any("error", err))
+			}
+		})
+	defer func(listener *pq.Listener) {
+		_ = listener.Close()
+	}(listener)
+
+	if err := listener.Listen(postgresChannel); err != nil {
+		return fmt.Errorf("failed to listen %s: %w", postgresChannel, err)
+	}
+
+	ticker := time.NewTicker(listenerPingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case notification := <-listener.Notify:
+			// nil приходит после переподключения
+			if notification == nil {
+				logger.Warn("rate listener reconnected, notifications may have been lost")
+				continue
+			}
+
+			var event rateEvent
+			if err := json.Unmarshal([]byte(notification.Extra), &event); err != nil {
+				logger.Error("failed to decode rate event", slog.String("payload", notification.Extra), slog.Any("error", err))
+				continue
+			}
+
+			bus.Deliver([]dto.RateRecordDTO{{
+				Date:           event.Date,
+				BaseCurrency:   event.BaseCurrency,
+				TargetCurrency: event.TargetCurrency,
+				Value:          event.Rate,
+				Provider:       event.Provider,
+				FetchedAt:      event.FetchedAt,
+			}})
+		case <-ticker.C:
+			if err := listener.Ping(); err != nil {
+				logger.Error("rate listener ping failed", slog.Any("error", err))
+			}
+		}
+	}
+}
@@ -0,0 +1,64 @@
+//go:build integration
+
+package events_test
+
+import (
+	"context"
+	"log/slog"
+	"my-currency-service/currency/internal/config"
+	"my-currency-service/currency/internal/db"
+	"my-currency-service/currency/internal/dto"
+	"my-currency-service/currency/internal/events"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostgresNotifier_DeliveredToListener(t *testing.T) {
+	cfg := config.MustLoad()
+
+	conn, err := db.NewDatabaseConnection(cfg.Database)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	// XTS зарезервирована ISO 4217 для тестов
+	pair := dto.CurrencyPairDTO{BaseCurrency: "XTS", TargetCurrency: "XXX"}
+
+	bus := events.NewBus(nil, slog.Default())
+	sub := bus.Subscribe([]dto.CurrencyPairDTO{pair}, 1)
+	defer sub.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = events.ListenPostgres(ctx, cfg.Database.ToDSN(), bus, slog.Default()) }()
+
+	notifier := events.NewPostgresNotifier(conn)
+	date := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	// LISTEN выполняется асинхронно, поэтому уведомление повторяется до получения
+	var received dto.RateRecordDTO
+	require.Eventually(t, func() bool {
+		err := notifier.Notify(ctx, []dto.RateRecordDTO{{
+			Date:           date,
+			BaseCurrency:   pair.BaseCurrency,
+			TargetCurrency: pair.TargetCurrency,
+			Value:          decimal.RequireFromString("157.709631745"),
+		}})
+		if !assert.NoError(t, err) {
+			return false
+		}
+
+		select {
+		case received = <-sub.Events():
+			return true
+		case <-time.After(100 * time.Millisecond):
+			return false
+		}
+	}, 5*time.Second, 10*time.Millisecond)
+
+	assert.Equal(t, date, received.Date.UTC())
+	assert.Equal(t, "157.709631745", received.Value.String())
+}
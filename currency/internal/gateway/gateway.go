@@ -0,0 +1,178 @@
+// Package gateway serves the CurrencyService over HTTP/JSON. Requests are
+// forwarded to the gRPC server through a client connection, so they pass
+// the same interceptors (auth, rate limits, metrics, logs) as gRPC calls.
+package gateway
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"my-currency-service/pkg/currency"
+	"net"
+	"net/http"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// Пути HTTP API
+const (
+	RPCPathPrefix = "/v1/rpc/"
+	OpenAPIPath   = "/openapi.json"
+)
+
+// Ограничение тела запроса: BatchGetRates на 10000 запросов укладывается с запасом
+const maxBodyBytes = 4 << 20
+
+// ForwardedForHeader carries the address of the HTTP client to the gRPC
+// server, which sees the gateway as the peer.
+const ForwardedForHeader = "x-forwarded-for"
+
+// HTTP-заголовки, передаваемые в метаданные gRPC
+var forwardedHeaders = []string{"authorization", "x-api-key"}
+
+var (
+	marshaler   = protojson.MarshalOptions{EmitUnpopulated: true}
+	unmarshaler = protojson.UnmarshalOptions{DiscardUnknown: false}
+)
+
+// Gateway is an http.Handler translating HTTP/JSON requests to gRPC calls.
+type Gateway struct {
+	conn    grpc.ClientConnInterface
+	service protoreflect.ServiceDescriptor
+	mux     *http.ServeMux
+	openAPI []byte
+	logger  *slog.Logger
+}
+
+// New creates a gateway calling the CurrencyService through conn. Every
+// unary method is served at POST /v1/rpc/{Method} with the request
+// message as the JSON body; common reads also have GET routes.
+func New(conn grpc.ClientConnInterface, logger *slog.Logger) (*Gateway, error) {
+	service := currency.File_proto_currency_currency_service_proto.Services().ByName("CurrencyService")
+
+	openAPI, err := openAPIDocument(service)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &Gateway{
+		conn:    conn,
+		service: service,
+		mux:     http.NewServeMux(),
+		openAPI: openAPI,
+		logger:  logger,
+	}
+
+	for _, route := range getRoutes {
+		g.mux.HandleFunc("GET "+route.path, g.handleGet(route))
+	}
+	g.mux.HandleFunc("POST "+RPCPathPrefix+"{method}", g.handleRPC)
+	g.mux.HandleFunc("GET "+OpenAPIPath, g.handleOpenAPI)
+
+	return g, nil
+}
+
+func (g *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	g.mux.ServeHTTP(w, r)
+}
+
+func (g *Gateway) handleRPC(w http.ResponseWriter, r *http.Request) {
+	method := g.service.Methods().ByName(protoreflect.Name(r.PathValue("method")))
+	if method == nil || method.IsStreamingClient() || method.IsStreamingServer() {
+		g.writeError(w, r, status.Errorf(codes.NotFound, "unknown method %q", r.PathValue("method")))
+		return
+	}
+
+	req, err := newMessage(method.Input())
+	if err != nil {
+		g.writeError(w, r, err)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	if err != nil {
+		g.writeError(w, r, status.Errorf(codes.InvalidArgument, "failed to read body: %v", err))
+		return
+	}
+	if len(body) > 0 {
+		if err := unmarshaler.Unmarshal(body, req); err != nil {
+			g.writeError(w, r, status.Errorf(codes.InvalidArgument, "invalid JSON body: %v", err))
+			return
+		}
+	}
+
+	g.invoke(w, r, method, req)
+}
+
+// invoke calls method with req and writes the response or the error.
+func (g *Gateway) invoke(w http.ResponseWriter, r *http.Request, method protoreflect.MethodDescriptor, req proto.Message) {
+	resp, err := newMessage(method.Output())
+	if err != nil {
+		g.writeError(w, r, err)
+		return
+	}
+
+	fullMethod := "/" + string(g.service.FullName()) + "/" + string(method.Name())
+
+	var header metadata.MD
+	err = g.conn.Invoke(outgoingContext(r), fullMethod, req, resp, grpc.Header(&header))
+
+	// retry-after выставляет ограничитель запросов
+	if retryAfter := header.Get("retry-after"); len(retryAfter) > 0 {
+		w.Header().Set("Retry-After", retryAfter[0])
+	}
+
+	if err != nil {
+		g.writeError(w, r, err)
+		return
+	}
+
+	data, err := marshaler.Marshal(resp)
+	if err != nil {
+		g.writeError(w, r, status.Errorf(codes.Internal, "failed to encode response: %v", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(data)
+}
+
+func (g *Gateway) handleOpenAPI(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(g.openAPI)
+}
+
+func newMessage(desc protoreflect.MessageDescriptor) (proto.Message, error) {
+	mt, err := protoregistry.GlobalTypes.FindMessageByName(desc.FullName())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "message type %s is not registered", desc.FullName())
+	}
+	return mt.New().Interface(), nil
+}
+
+// outgoingContext carries credentials and the client address of r
+// to the gRPC call.
+func outgoingContext(r *http.Request) context.Context {
+	md := metadata.MD{}
+	for _, name := range forwardedHeaders {
+		if value := r.Header.Get(name); value != "" {
+			md.Set(name, value)
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	md.Set(ForwardedForHeader, strings.TrimSpace(host))
+
+	return metadata.NewOutgoingContext(r.Context(), md)
+}
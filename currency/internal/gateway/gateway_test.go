@@ -0,0 +1,227 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"my-currency-service/pkg/currency"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// fakeCurrencyServer запоминает последний запрос и метаданные
+type fakeCurrencyServer struct {
+	currency.UnimplementedCurrencyServiceServer
+
+	md         metadata.MD
+	getRateReq *currency.GetRateRequest
+	err        error
+}
+
+func (s *fakeCurrencyServer) GetRate(ctx context.Context, req *currency.GetRateRequest) (*currency.GetRateResponse, error) {
+	s.md, _ = metadata.FromIncomingContext(ctx)
+	s.getRateReq = req
+	if s.err != nil {
+		_ = grpc.SetHeader(ctx, metadata.Pairs("retry-after", "3"))
+		return nil, s.err
+	}
+	return &currency.GetRateResponse{
+		Currency: req.GetCurrency(),
+		Rates: []*currency.RateRecord{{
+			Date:      req.GetDataFrom(),
+			Rate:      1.03,
+			ExactRate: "1.0345",
+		}},
+	}, nil
+}
+
+func (s *fakeCurrencyServer) BatchGetRates(_ context.Context, req *currency.BatchGetRatesRequest) (*currency.BatchGetRatesResponse, error) {
+	results := make([]*currency.BatchRateResult, len(req.GetQueries()))
+	for i, query := range req.GetQueries() {
+		results[i] = &currency.BatchRateResult{
+			Query:  query,
+			Result: &currency.BatchRateResult_Rate{Rate: &currency.RateRecord{ExactRate: "1.1"}},
+		}
+	}
+	return &currency.BatchGetRatesResponse{Results: results}, nil
+}
+
+func newTestGateway(t *testing.T) (*httptest.Server, *fakeCurrencyServer) {
+	t.Helper()
+
+	fake := &fakeCurrencyServer{}
+	server := grpc.NewServer()
+	currency.RegisterCurrencyServiceServer(server, fake)
+	t.Cleanup(server.Stop)
+
+	conn, err := InProcess(server)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	gw, err := New(conn, slog.Default())
+	require.NoError(t, err)
+
+	httpServer := httptest.NewServer(gw)
+	t.Cleanup(httpServer.Close)
+
+	return httpServer, fake
+}
+
+func decode(t *testing.T, resp *http.Response) map[string]any {
+	t.Helper()
+
+	defer func() { _ = resp.Body.Close() }()
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+
+	var body map[string]any
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	return body
+}
+
+func TestGetRates(t *testing.T) {
+	server, fake := newTestGateway(t)
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/v1/rates?base=EUR&currency=USD&from=2025-01-01&to=2025-01-31T00:00:00Z", nil)
+	require.NoError(t, err)
+	req.Header.Set("X-Api-Key", "secret")
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	body := decode(t, resp)
+
+	assert.Equal(t, "EUR", fake.getRateReq.GetBaseCurrency())
+	assert.Equal(t, "USD", fake.getRateReq.GetCurrency())
+	assert.Equal(t, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), fake.getRateReq.GetDataFrom().AsTime())
+	assert.Equal(t, time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC), fake.getRateReq.GetDateTo().AsTime())
+
+	// Учётные данные и адрес клиента передаются в метаданных
+	assert.Equal(t, []string{"secret"}, fake.md.Get("x-api-key"))
+	assert.Equal(t, []string{"127.0.0.1"}, fake.md.Get(ForwardedForHeader))
+
+	rates := body["rates"].([]any)
+	require.Len(t, rates, 1)
+	assert.Equal(t, "1.0345", rates[0].(map[string]any)["exactRate"])
+	assert.Equal(t, "2025-01-01T00:00:00Z", rates[0].(map[string]any)["date"])
+}
+
+func TestGetRates_InvalidDate(t *testing.T) {
+	server, fake := newTestGateway(t)
+
+	resp, err := http.Get(server.URL + "/v1/rates?currency=USD&from=01.01.2025&to=2025-01-31")
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	body := decode(t, resp)
+	assert.Equal(t, float64(codes.InvalidArgument), body["code"])
+
+	details := body["details"].([]any)
+	require.Len(t, details, 1)
+	assert.Equal(t, "type.googleapis.com/google.rpc.BadRequest", details[0].(map[string]any)["@type"])
+	assert.Nil(t, fake.getRateReq)
+}
+
+func TestGetRates_ErrorStatus(t *testing.T) {
+	tests := []struct {
+		err  error
+		code int
+	}{
+		{status.Error(codes.NotFound, "rate not found"), http.StatusNotFound},
+		{status.Error(codes.Unauthenticated, "valid API key, bearer token or client certificate is required"), http.StatusUnauthorized},
+		{status.Error(codes.ResourceExhausted, "rate limit exceeded"), http.StatusTooManyRequests},
+		{status.Error(codes.Unavailable, "rate storage is unavailable"), http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(status.Code(tt.err).String(), func(t *testing.T) {
+			server, fake := newTestGateway(t)
+			fake.err = tt.err
+
+			resp, err := http.Get(server.URL + "/v1/rates?currency=USD&from=2025-01-01&to=2025-01-31")
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.code, resp.StatusCode)
+			assert.Equal(t, "3", resp.Header.Get("Retry-After"))
+			body := decode(t, resp)
+			assert.Equal(t, float64(status.Code(tt.err)), body["code"])
+			assert.Equal(t, status.Convert(tt.err).Message(), body["message"])
+		})
+	}
+}
+
+func TestRPC(t *testing.T) {
+	server, _ := newTestGateway(t)
+
+	resp, err := http.Post(server.URL+"/v1/rpc/BatchGetRates", "application/json",
+		strings.NewReader(`{"queries": [{"currency": "USD", "date": "2025-01-15T00:00:00Z"}]}`))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	body := decode(t, resp)
+	results := body["results"].([]any)
+	require.Len(t, results, 1)
+	assert.Equal(t, "1.1", results[0].(map[string]any)["rate"].(map[string]any)["exactRate"])
+}
+
+func TestRPC_Errors(t *testing.T) {
+	server, _ := newTestGateway(t)
+
+	tests := []struct {
+		name string
+		path string
+		body string
+		code int
+	}{
+		{"unknown method", "/v1/rpc/DeleteRates", `{}`, http.StatusNotFound},
+		{"streaming method", "/v1/rpc/SubscribeRates", `{}`, http.StatusNotFound},
+		{"malformed body", "/v1/rpc/BatchGetRates", `{"queries": 1}`, http.StatusBadRequest},
+		{"not implemented", "/v1/rpc/Convert", `{"amount": "1"}`, http.StatusNotImplemented},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := http.Post(server.URL+tt.path, "application/json", strings.NewReader(tt.body))
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.code, resp.StatusCode)
+			assert.Contains(t, decode(t, resp), "message")
+		})
+	}
+}
+
+func TestOpenAPI(t *testing.T) {
+	server, _ := newTestGateway(t)
+
+	resp, err := http.Get(server.URL + OpenAPIPath)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	body := decode(t, resp)
+
+	assert.Equal(t, "3.0.3", body["openapi"])
+
+	paths := body["paths"].(map[string]any)
+	assert.Contains(t, paths, "/v1/rates")
+	assert.Contains(t, paths, "/v1/rpc/GetRate")
+	assert.Contains(t, paths, "/v1/rpc/BatchGetRates")
+	assert.NotContains(t, paths, "/v1/rpc/SubscribeRates")
+
+	schemas := body["components"].(map[string]any)["schemas"].(map[string]any)
+	record := schemas["currency.RateRecord"].(map[string]any)["properties"].(map[string]any)
+	assert.Equal(t, "string", record["exactRate"].(map[string]any)["type"])
+	assert.Equal(t, "date-time", record["date"].(map[string]any)["format"])
+	assert.Equal(t, "array", record["legs"].(map[string]any)["type"])
+
+	// Имена полей совпадают с protojson
+	request := schemas["currency.GetRateRequest"].(map[string]any)["properties"].(map[string]any)
+	assert.Contains(t, request, "dataFrom")
+}
@@ -0,0 +1,36 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// Размер буфера соединения в памяти
+const inProcessBufferSize = 1 << 20
+
+// InProcess serves server on an in-memory listener and returns a client
+// connection to it: gateway calls skip the network but still pass the
+// interceptor chain. The listener is closed when the server stops.
+func InProcess(server *grpc.Server) (*grpc.ClientConn, error) {
+	listener := bufconn.Listen(inProcessBufferSize)
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	conn, err := grpc.NewClient("passthrough:///in-process",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create in-process connection: %w", err)
+	}
+
+	return conn, nil
+}
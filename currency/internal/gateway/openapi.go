@@ -0,0 +1,202 @@
+package gateway
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Схема тела ошибки, см. writeError
+const statusSchema = "google.rpc.Status"
+
+// openAPIDocument generates an OpenAPI 3.0 document of the gateway routes
+// from the service descriptor, so new RPCs appear in it without changes.
+func openAPIDocument(service protoreflect.ServiceDescriptor) ([]byte, error) {
+	schemas := map[string]any{
+		statusSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"code":    map[string]any{"type": "integer", "format": "int32", "description": "google.rpc.Code"},
+				"message": map[string]any{"type": "string"},
+				"details": map[string]any{
+					"type": "array",
+					"items": map[string]any{
+						"type":                 "object",
+						"properties":           map[string]any{"@type": map[string]any{"type": "string"}},
+						"additionalProperties": true,
+					},
+				},
+			},
+		},
+	}
+
+	paths := map[string]any{}
+
+	for _, route := range getRoutes {
+		method := service.Methods().ByName(route.method)
+		if method == nil {
+			return nil, fmt.Errorf("route %s: unknown method %s", route.path, route.method)
+		}
+		addSchema(schemas, method.Output())
+
+		params := make([]any, 0, len(route.params))
+		for _, param := range route.params {
+			schema := map[string]any{"type": "string"}
+			if param.format != "" {
+				schema["format"] = param.format
+			}
+			params = append(params, map[string]any{
+				"name":        param.name,
+				"in":          "query",
+				"required":    param.required,
+				"description": param.description,
+				"schema":      schema,
+			})
+		}
+
+		paths[route.path] = map[string]any{
+			"get": map[string]any{
+				"operationId": "get" + string(route.method),
+				"summary":     route.summary,
+				"parameters":  params,
+				"responses":   responses(method),
+			},
+		}
+	}
+
+	methods := service.Methods()
+	for i := 0; i < methods.Len(); i++ {
+		method := methods.Get(i)
+		if method.IsStreamingClient() || method.IsStreamingServer() {
+			continue
+		}
+		addSchema(schemas, method.Input())
+		addSchema(schemas, method.Output())
+
+		paths[RPCPathPrefix+string(method.Name())] = map[string]any{
+			"post": map[string]any{
+				"operationId": string(method.Name()),
+				"requestBody": map[string]any{
+					"required": true,
+					"content": map[string]any{
+						"application/json": map[string]any{"schema": ref(method.Input())},
+					},
+				},
+				"responses": responses(method),
+			},
+		}
+	}
+
+	return json.MarshalIndent(map[string]any{
+		"openapi": "3.0.3",
+		"info": map[string]any{
+			"title":   string(service.Name()),
+			"version": "v1",
+		},
+		"paths": paths,
+		"components": map[string]any{
+			"schemas": schemas,
+			"securitySchemes": map[string]any{
+				"apiKey":     map[string]any{"type": "apiKey", "in": "header", "name": "X-Api-Key"},
+				"bearerAuth": map[string]any{"type": "http", "scheme": "bearer", "bearerFormat": "JWT"},
+			},
+		},
+		"security": []any{
+			map[string]any{"apiKey": []any{}},
+			map[string]any{"bearerAuth": []any{}},
+		},
+	}, "", "  ")
+}
+
+func responses(method protoreflect.MethodDescriptor) map[string]any {
+	return map[string]any{
+		"200": map[string]any{
+			"description": "OK",
+			"content": map[string]any{
+				"application/json": map[string]any{"schema": ref(method.Output())},
+			},
+		},
+		"default": map[string]any{
+			"description": "Error derived from the gRPC status",
+			"content": map[string]any{
+				"application/json": map[string]any{"schema": map[string]any{"$ref": "#/components/schemas/" + statusSchema}},
+			},
+		},
+	}
+}
+
+func ref(message protoreflect.MessageDescriptor) map[string]any {
+	return map[string]any{"$ref": "#/components/schemas/" + string(message.FullName())}
+}
+
+// addSchema adds the schema of message and of every message it refers to.
+func addSchema(schemas map[string]any, message protoreflect.MessageDescriptor) {
+	name := string(message.FullName())
+	if _, ok := schemas[name]; ok {
+		return
+	}
+
+	properties := map[string]any{}
+	schemas[name] = map[string]any{"type": "object", "properties": properties}
+
+	fields := message.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		field := fields.Get(i)
+
+		var schema map[string]any
+		switch {
+		case field.IsMap():
+			schema = map[string]any{
+				"type":                 "object",
+				"additionalProperties": fieldSchema(schemas, field.MapValue()),
+			}
+		case field.IsList():
+			schema = map[string]any{"type": "array", "items": fieldSchema(schemas, field)}
+		default:
+			schema = fieldSchema(schemas, field)
+		}
+
+		properties[field.JSONName()] = schema
+	}
+}
+
+// fieldSchema describes a single value of field as protojson encodes it.
+func fieldSchema(schemas map[string]any, field protoreflect.FieldDescriptor) map[string]any {
+	switch field.Kind() {
+	case protoreflect.BoolKind:
+		return map[string]any{"type": "boolean"}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return map[string]any{"type": "integer", "format": "int32"}
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return map[string]any{"type": "integer", "format": "int64", "minimum": 0}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		// protojson кодирует 64-битные числа строками
+		return map[string]any{"type": "string", "format": "int64"}
+	case protoreflect.FloatKind:
+		return map[string]any{"type": "number", "format": "float"}
+	case protoreflect.DoubleKind:
+		return map[string]any{"type": "number", "format": "double"}
+	case protoreflect.BytesKind:
+		return map[string]any{"type": "string", "format": "byte"}
+	case protoreflect.EnumKind:
+		values := field.Enum().Values()
+		names := make([]any, values.Len())
+		for i := range names {
+			names[i] = string(values.Get(i).Name())
+		}
+		return map[string]any{"type": "string", "enum": names}
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		switch field.Message().FullName() {
+		case "google.protobuf.Timestamp":
+			return map[string]any{"type": "string", "format": "date-time"}
+		case "google.protobuf.Duration":
+			return map[string]any{"type": "string", "description": "Seconds with an s suffix, e.g. \"3600s\""}
+		}
+		addSchema(schemas, field.Message())
+		return ref(field.Message())
+	default:
+		return map[string]any{"type": "string"}
+	}
+}
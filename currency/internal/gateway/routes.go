@@ -0,0 +1,145 @@
+package gateway
+
+import (
+	"fmt"
+	"my-currency-service/pkg/currency"
+	"net/http"
+	"net/url"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type queryParam struct {
+	name        string
+	description string
+	required    bool
+	// Формат значения в OpenAPI, например "date"
+	format string
+}
+
+// getRoute maps a GET request with query parameters onto a unary method.
+type getRoute struct {
+	path    string
+	method  protoreflect.Name
+	summary string
+	params  []queryParam
+	build   func(query url.Values) (proto.Message, error)
+}
+
+var getRoutes = []getRoute{
+	{
+		path:    "/v1/rates",
+		method:  "GetRate",
+		summary: "Rates of a pair for every day of an interval",
+		params: []queryParam{
+			{name: "base", description: "Base currency, defaults to USD"},
+			{name: "currency", description: "Target currency", required: true},
+			{name: "from", description: "First day, YYYY-MM-DD or RFC 3339", required: true, format: "date"},
+			{name: "to", description: "Last day, YYYY-MM-DD or RFC 3339", required: true, format: "date"},
+		},
+		build: func(query url.Values) (proto.Message, error) {
+			var v violations
+			req := &currency.GetRateRequest{
+				BaseCurrency: query.Get("base"),
+				Currency:     query.Get("currency"),
+				DataFrom:     v.timestamp(query, "from"),
+				DateTo:       v.timestamp(query, "to"),
+			}
+			return req, v.err()
+		},
+	},
+	{
+		path:    "/v1/rates/latest",
+		method:  "GetLatestRate",
+		summary: "Newest rate of a pair with its freshness",
+		params: []queryParam{
+			{name: "base", description: "Base currency, defaults to USD"},
+			{name: "currency", description: "Target currency", required: true},
+		},
+		build: func(query url.Values) (proto.Message, error) {
+			return &currency.GetLatestRateRequest{
+				BaseCurrency: query.Get("base"),
+				Currency:     query.Get("currency"),
+			}, nil
+		},
+	},
+	{
+		path:    "/v1/convert",
+		method:  "Convert",
+		summary: "Converts an amount with the latest rate on or before as_of",
+		params: []queryParam{
+			{name: "amount", description: "Decimal amount, e.g. 100.50", required: true},
+			{name: "from", description: "Source currency", required: true},
+			{name: "to", description: "Target currency", required: true},
+			{name: "as_of", description: "Rate date, YYYY-MM-DD or RFC 3339; defaults to now", format: "date"},
+		},
+		build: func(query url.Values) (proto.Message, error) {
+			var v violations
+			req := &currency.ConvertRequest{
+				Amount: query.Get("amount"),
+				From:   query.Get("from"),
+				To:     query.Get("to"),
+			}
+			if query.Has("as_of") {
+				req.AsOf = v.timestamp(query, "as_of")
+			}
+			return req, v.err()
+		},
+	},
+}
+
+func (g *Gateway) handleGet(route getRoute) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		req, err := route.build(r.URL.Query())
+		if err != nil {
+			g.writeError(w, r, err)
+			return
+		}
+
+		g.invoke(w, r, g.service.Methods().ByName(route.method), req)
+	}
+}
+
+// violations collects invalid query parameters. Missing or invalid values
+// of fields validated by the handler are passed on to it.
+type violations []*errdetails.BadRequest_FieldViolation
+
+// timestamp parses a date or an RFC 3339 time; an empty value is left
+// for the handler to report.
+func (v *violations) timestamp(query url.Values, name string) *timestamppb.Timestamp {
+	value := query.Get(name)
+	if value == "" {
+		return nil
+	}
+
+	for _, layout := range []string{time.DateOnly, time.RFC3339} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return timestamppb.New(t)
+		}
+	}
+
+	*v = append(*v, &errdetails.BadRequest_FieldViolation{
+		Field:       name,
+		Description: fmt.Sprintf("invalid date %q, expected YYYY-MM-DD or RFC 3339", value),
+	})
+	return nil
+}
+
+func (v violations) err() error {
+	if len(v) == 0 {
+		return nil
+	}
+
+	st := status.New(codes.InvalidArgument, fmt.Sprintf("invalid %s: %s", v[0].Field, v[0].Description))
+	detailed, err := st.WithDetails(&errdetails.BadRequest{FieldViolations: v})
+	if err != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
@@ -2,58 +2,220 @@ package handler
 
 import (
 	"context"
-	"fmt"
+	"errors"
 	"my-currency-service/currency/internal/dto"
+	"my-currency-service/currency/internal/events"
+	"my-currency-service/currency/internal/repository"
 	"my-currency-service/pkg/currency"
+	"strings"
 	"time"
 
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 func (s CurrencyServer) GetRate(ctx context.Context, request *currency.GetRateRequest) (*currency.GetRateResponse, error) {
+	if err := validateGetRateRequest(request); err != nil {
+		return nil, err
+	}
 
-	// ValidationErr := rateRequestValidation(request)
-	// if err != nil {
-	// 	return nil, ValidationErr
-	// }
-
-	start := time.Now()
 	reqDTO := dto.CurrencyRequestDTOFromProtobuf(request)
 
-	// TODO: метрики в мидлвары
-	s.requestCount.WithLabelValues("GetRate").Inc()
 	rates, err := s.service.GetCurrencyRatesInInterval(ctx, reqDTO)
 	if err != nil {
-		return nil, fmt.Errorf("service.GetCurrencyRatesInInterval: %w", err)
+		return nil, s.statusError(ctx, "GetRate", err)
 	}
 
 	rateRecords := make([]*currency.RateRecord, len(rates))
 	for i, rate := range rates {
-		rateRecords[i] = &currency.RateRecord{
-			Date: timestamppb.New(rate.Date),
-			Rate: rate.Rate,
-		}
+		rateRecords[i] = rateRecordToProtobuf(rate)
 	}
 
-	s.requestDuration.WithLabelValues("GetExchangeRate").Observe(time.Since(start).Seconds())
 	return &currency.GetRateResponse{
 		Currency: reqDTO.TargetCurrency,
 		Rates:    rateRecords,
 	}, nil
 }
 
-// func rateRequestValidation(req *currecy.GetRateRequest) error {
+func (s CurrencyServer) GetLatestRate(ctx context.Context, request *currency.GetLatestRateRequest) (*currency.GetLatestRateResponse, error) {
+	if err := validateGetLatestRateRequest(request); err != nil {
+		return nil, err
+	}
+
+	reqDTO := dto.LatestRateRequestDTOFromProtobuf(request)
+
+	latest, err := s.service.GetLatestRate(ctx, reqDTO)
+	if err != nil {
+		return nil, s.statusError(ctx, "GetLatestRate", err)
+	}
+
+	return &currency.GetLatestRateResponse{
+		Currency:     strings.ToUpper(reqDTO.TargetCurrency),
+		BaseCurrency: strings.ToUpper(reqDTO.BaseCurrency),
+		Rate:         rateRecordToProtobuf(latest.CurrencyRate),
+		FetchedAt:    optionalTimestamp(latest.FetchedAt),
+		Age:          durationpb.New(latest.Age),
+		MaxAge:       durationpb.New(latest.MaxAge),
+		Stale:        latest.Stale,
+		FixingDate:   optionalTimestamp(latest.FixingDate),
+	}, nil
+}
+
+func (s CurrencyServer) Convert(ctx context.Context, request *currency.ConvertRequest) (*currency.ConvertResponse, error) {
+	if err := validateConvertRequest(request); err != nil {
+		return nil, err
+	}
+
+	reqDTO, err := dto.ConvertRequestDTOFromProtobuf(request)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	conversion, err := s.service.Convert(ctx, reqDTO)
+	if err != nil {
+		return nil, s.statusError(ctx, "Convert", err)
+	}
+
+	return conversion.ToProtobuf(), nil
+}
+
+// Ограничение размера пакета, чтобы один вызов не занимал сервис надолго
+const maxBatchSize = 10000
 
-// 	if req.GetBaseCurrency() == "" {
-// 		return nil, status.Error(codes.InvalidArgument, "currency is required")
-// 	}
+func (s CurrencyServer) BatchGetRates(ctx context.Context, request *currency.BatchGetRatesRequest) (*currency.BatchGetRatesResponse, error) {
+	queries := request.GetQueries()
+	if len(queries) == 0 || len(queries) > maxBatchSize {
+		var v violations
+		v.add("queries", "number of queries must be between 1 and %d, got %d", maxBatchSize, len(queries))
+		return nil, v.err()
+	}
 
-// 	if req.GetCurrency() == "" {
-// 		return nil, status.Error(codes.InvalidArgument,"exchange currency is required")
-// 	}
+	results := make([]*currency.BatchRateResult, len(queries))
+	queryDTOs := make([]dto.RateQueryDTO, 0, len(queries))
+	positions := make([]int, 0, len(queries))
+	for i, query := range queries {
+		results[i] = &currency.BatchRateResult{Query: query}
 
-// 	if req.GetDataFrom()
+		if v := validateRateQuery(query); len(v) > 0 {
+			results[i].Result = batchRateError(codes.InvalidArgument, v.message())
+			continue
+		}
 
-// 	if req.
+		queryDTOs = append(queryDTOs, dto.RateQueryDTOFromProtobuf(query))
+		positions = append(positions, i)
+	}
 
-// }
+	if len(queryDTOs) > 0 {
+		rates, err := s.service.BatchGetRates(ctx, queryDTOs)
+		if err != nil {
+			return nil, s.statusError(ctx, "BatchGetRates", err)
+		}
+
+		for j, rate := range rates {
+			i := positions[j]
+			results[i].FixingDate = optionalTimestamp(rate.FixingDate)
+			if rate.Err != nil {
+				st := errorStatus(rate.Err)
+				results[i].Result = batchRateError(st.Code(), st.Message())
+				continue
+			}
+			results[i].Result = &currency.BatchRateResult_Rate{Rate: rateRecordToProtobuf(*rate.Rate)}
+		}
+	}
+
+	return &currency.BatchGetRatesResponse{Results: results}, nil
+}
+
+func (s CurrencyServer) SubscribeRates(request *currency.SubscribeRatesRequest, stream grpc.ServerStreamingServer[currency.SubscribeRatesResponse]) error {
+	if err := validateSubscribeRatesRequest(request); err != nil {
+		return err
+	}
+
+	pairs := make([]dto.CurrencyPairDTO, len(request.GetPairs()))
+	for i, pair := range request.GetPairs() {
+		pairs[i] = dto.CurrencyPairDTOFromProtobuf(pair)
+	}
+
+	ctx := stream.Context()
+	subscription, err := s.service.SubscribeRates(ctx, pairs)
+	if err != nil {
+		return s.statusError(ctx, "SubscribeRates", err)
+	}
+	defer subscription.Close()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case record, ok := <-subscription.Events():
+			if !ok {
+				return subscriptionError(subscription.Err())
+			}
+
+			err := stream.Send(&currency.SubscribeRatesResponse{
+				BaseCurrency: record.BaseCurrency,
+				Currency:     record.TargetCurrency,
+				Rate: rateRecordToProtobuf(repository.CurrencyRate{
+					Date:      record.Date,
+					Rate:      record.Value,
+					FetchedAt: record.FetchedAt,
+					Provider:  record.Provider,
+				}),
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func subscriptionError(err error) error {
+	switch {
+	case errors.Is(err, events.ErrSlowConsumer):
+		return status.Error(codes.ResourceExhausted, "subscriber fell behind, resubscribe and catch up with GetRate")
+	case errors.Is(err, events.ErrBusClosed):
+		return status.Error(codes.Unavailable, "server is shutting down")
+	default:
+		return status.Error(codes.Aborted, "subscription closed")
+	}
+}
+
+func batchRateError(code codes.Code, message string) *currency.BatchRateResult_Error {
+	return &currency.BatchRateResult_Error{Error: &currency.BatchRateError{
+		Code:    int32(code),
+		Message: message,
+	}}
+}
+
+func rateRecordToProtobuf(rate repository.CurrencyRate) *currency.RateRecord {
+	record := &currency.RateRecord{
+		Date:      timestamppb.New(rate.Date),
+		Rate:      float32(rate.Rate.InexactFloat64()),
+		ExactRate: rate.Rate.String(),
+		Derived:   rate.Derived,
+		Provider:  rate.Provider,
+		FetchedAt: optionalTimestamp(rate.FetchedAt),
+	}
+	for _, leg := range rate.Legs {
+		record.Legs = append(record.Legs, &currency.RateLeg{
+			BaseCurrency: leg.BaseCurrency,
+			Currency:     leg.TargetCurrency,
+			Rate:         leg.Rate.String(),
+			Inverted:     leg.Inverted,
+			Provider:     leg.Provider,
+			FetchedAt:    optionalTimestamp(leg.FetchedAt),
+		})
+	}
+	return record
+}
+
+// optionalTimestamp leaves an unknown time unset instead of sending year 1.
+func optionalTimestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
@@ -3,43 +3,35 @@ package handler
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
 	"math/rand/v2"
+	"my-currency-service/currency/internal/events"
 	"my-currency-service/currency/internal/handler/mocks"
 	"my-currency-service/currency/internal/repository"
+	"my-currency-service/currency/internal/service"
 	"my-currency-service/pkg/currency"
 	"testing"
 	"time"
 
 	"my-currency-service/currency/internal/dto"
 
-	"github.com/prometheus/client_golang/prometheus"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
-// newTestServer создаёт CurrencyServer с моком и заглушками метрик.
+// newTestServer создаёт CurrencyServer с моком.
 // Возвращает сервер и мок, чтобы в тесте настроить ожидания.
 func newTestServer(t *testing.T) (*CurrencyServer, *mocks.CurrencyService) {
 	service := mocks.NewCurrencyService(t)
 
-	requestCount := prometheus.NewCounterVec(
-		prometheus.CounterOpts{Name: "test_request_count", Help: "test"},
-		[]string{"method"},
-	)
-	requestDuration := prometheus.NewHistogramVec(
-		prometheus.HistogramOpts{Name: "test_request_duration", Help: "test", Buckets: prometheus.DefBuckets},
-		[]string{"method"},
-	)
-
-	appUptime := prometheus.NewGauge(
-		prometheus.GaugeOpts{Name: "currency_service_uptime_seconds",
-			Help: "Time since service start in seconds"},
-	)
-
-	server := NewCurrencyServer(service, slog.Default(), requestCount, requestDuration, &appUptime)
+	server := NewCurrencyServer(service, slog.Default())
 	return server, service
 }
 
@@ -50,8 +42,8 @@ func TestGetRate_Success(t *testing.T) {
 
 	service.On("GetCurrencyRatesInInterval", mock.Anything, mock.Anything).
 		Return([]repository.CurrencyRate{
-			{Date: now, Rate: 1.10},
-			{Date: now.AddDate(0, 0, 1), Rate: 1.12},
+			{Date: now, Rate: decimal.RequireFromString("1.10")},
+			{Date: now.AddDate(0, 0, 1), Rate: decimal.RequireFromString("1.12")},
 		}, nil)
 
 	req := &currency.GetRateRequest{
@@ -67,6 +59,11 @@ func TestGetRate_Success(t *testing.T) {
 	assert.Len(t, resp.Rates, 2)
 	assert.Equal(t, float32(1.10), resp.Rates[0].Rate)
 	assert.Equal(t, float32(1.12), resp.Rates[1].Rate)
+	assert.Equal(t, "1.1", resp.Rates[0].ExactRate)
+	assert.Equal(t, "1.12", resp.Rates[1].ExactRate)
+	// Происхождение неизвестно — поля не заполняются
+	assert.Empty(t, resp.Rates[0].Provider)
+	assert.Nil(t, resp.Rates[0].FetchedAt)
 }
 
 func TestGetRate_ServiceError(t *testing.T) {
@@ -85,7 +82,9 @@ func TestGetRate_ServiceError(t *testing.T) {
 
 	require.Error(t, err)
 	assert.Nil(t, resp)
-	assert.Contains(t, err.Error(), "db connection failed")
+	// Причина пишется в лог, клиенту уходит только код
+	assert.Equal(t, codes.Internal, status.Code(err))
+	assert.NotContains(t, err.Error(), "db connection failed")
 }
 
 func TestGetRate_EmptyRates(t *testing.T) {
@@ -114,7 +113,7 @@ func TestGetRate_SingleRate(t *testing.T) {
 
 	service.On("GetCurrencyRatesInInterval", mock.Anything, mock.Anything).
 		Return([]repository.CurrencyRate{
-			{Date: now, Rate: 0.85},
+			{Date: now, Rate: decimal.RequireFromString("0.85")},
 		}, nil)
 
 	req := &currency.GetRateRequest{
@@ -141,7 +140,7 @@ func TestGetRate_DefaultBaseCurrency(t *testing.T) {
 	service.On("GetCurrencyRatesInInterval", mock.Anything, mock.MatchedBy(func(req *dto.CurrencyRequestDTO) bool {
 		return req.BaseCurrency == dto.DefaultBaseCurrency
 	})).Return([]repository.CurrencyRate{
-		{Date: now, Rate: 1.15},
+		{Date: now, Rate: decimal.RequireFromString("1.15")},
 	}, nil)
 
 	// No BaseCurrency in request — should fall back to DefaultBaseCurrency
@@ -165,7 +164,7 @@ func TestGetRate_CustomBaseCurrency(t *testing.T) {
 	service.On("GetCurrencyRatesInInterval", mock.Anything, mock.MatchedBy(func(req *dto.CurrencyRequestDTO) bool {
 		return req.BaseCurrency == "EUR" && req.TargetCurrency == "GBP"
 	})).Return([]repository.CurrencyRate{
-		{Date: now, Rate: 0.86},
+		{Date: now, Rate: decimal.RequireFromString("0.86")},
 	}, nil)
 
 	req := &currency.GetRateRequest{
@@ -193,8 +192,8 @@ func TestGetRate_DatesPassedCorrectly(t *testing.T) {
 		return req.DateFrom.Equal(dateFrom) && req.DateTo.Equal(dateTo)
 	})).
 		Return([]repository.CurrencyRate{
-			{Date: dateFrom, Rate: 1.10},
-			{Date: dateTo, Rate: 1.37},
+			{Date: dateFrom, Rate: decimal.RequireFromString("1.10")},
+			{Date: dateTo, Rate: decimal.RequireFromString("1.37")},
 		}, nil)
 
 	req := &currency.GetRateRequest{
@@ -221,7 +220,7 @@ func TestGetRate_ManyRates(t *testing.T) {
 	for i := 0; i < numberOfTests; i++ {
 		rates[i] = repository.CurrencyRate{
 			Date: baseDate.AddDate(0, 0, i),
-			Rate: float32(0.5 + rand.Float64()*1.5),
+			Rate: decimal.NewFromFloat(0.5 + rand.Float64()*1.5).Round(4),
 		}
 	}
 
@@ -240,6 +239,337 @@ func TestGetRate_ManyRates(t *testing.T) {
 	assert.Len(t, resp.Rates, 30)
 	for i := 0; i < numberOfTests; i++ {
 		assert.Equal(t, rates[i].Date, resp.Rates[i].Date.AsTime())
+		assert.Equal(t, rates[i].Rate.String(), resp.Rates[i].ExactRate)
 	}
 
 }
+
+func TestGetRate_ExactRate(t *testing.T) {
+	server, service := newTestServer(t)
+
+	now := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	// 16234.5678901 does not fit into float32 without losing digits
+	service.On("GetCurrencyRatesInInterval", mock.Anything, mock.Anything).
+		Return([]repository.CurrencyRate{
+			{Date: now, Rate: decimal.RequireFromString("16234.5678901")},
+		}, nil)
+
+	req := &currency.GetRateRequest{
+		Currency:     "IDR",
+		BaseCurrency: "EUR",
+		DataFrom:     timestamppb.New(now),
+		DateTo:       timestamppb.New(now),
+	}
+
+	resp, err := server.GetRate(context.Background(), req)
+
+	require.NoError(t, err)
+	require.Len(t, resp.Rates, 1)
+	assert.Equal(t, "16234.5678901", resp.Rates[0].ExactRate)
+	assert.InDelta(t, 16234.5678901, resp.Rates[0].Rate, 0.01)
+}
+
+func TestConvert_Success(t *testing.T) {
+	server, svc := newTestServer(t)
+
+	rateDate := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	svc.On("Convert", mock.Anything, mock.MatchedBy(func(req *dto.ConvertRequestDTO) bool {
+		return req.Amount.Equal(decimal.RequireFromString("100.5")) &&
+			req.From == "EUR" && req.To == "JPY" && req.AsOf.Equal(rateDate)
+	})).Return(&dto.ConversionDTO{
+		Amount:          decimal.RequireFromString("16330"),
+		UnroundedAmount: decimal.RequireFromString("16329.7425"),
+		From:            "EUR",
+		To:              "JPY",
+		Rate:            decimal.RequireFromString("162.485"),
+		RateDate:        rateDate,
+		FixingDate:      rateDate,
+		MinorUnits:      0,
+		RoundingMode:    dto.RoundingHalfAwayFromZero,
+	}, nil)
+
+	resp, err := server.Convert(context.Background(), &currency.ConvertRequest{
+		Amount: "100.5",
+		From:   "EUR",
+		To:     "JPY",
+		AsOf:   timestamppb.New(rateDate),
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "16330", resp.Amount)
+	assert.Equal(t, "16329.7425", resp.UnroundedAmount)
+	assert.Equal(t, "162.485", resp.Rate)
+	assert.Equal(t, rateDate, resp.RateDate.AsTime())
+	assert.Equal(t, rateDate, resp.FixingDate.AsTime())
+	assert.Equal(t, int32(0), resp.MinorUnits)
+	assert.Equal(t, dto.RoundingHalfAwayFromZero, resp.RoundingMode)
+}
+
+func TestConvert_InvalidAmount(t *testing.T) {
+	server, _ := newTestServer(t)
+
+	resp, err := server.Convert(context.Background(), &currency.ConvertRequest{
+		Amount: "ten",
+		From:   "EUR",
+		To:     "USD",
+	})
+
+	require.Error(t, err)
+	assert.Nil(t, resp)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestConvert_MissingCurrency(t *testing.T) {
+	server, _ := newTestServer(t)
+
+	_, err := server.Convert(context.Background(), &currency.ConvertRequest{Amount: "10", From: "EUR"})
+
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestConvert_RateNotFound(t *testing.T) {
+	server, svc := newTestServer(t)
+
+	svc.On("Convert", mock.Anything, mock.Anything).
+		Return(nil, fmt.Errorf("%w: EUR/USD", service.ErrRateNotFound))
+
+	_, err := server.Convert(context.Background(), &currency.ConvertRequest{Amount: "10", From: "EUR", To: "USD"})
+
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestGetRate_DerivedRate(t *testing.T) {
+	server, service := newTestServer(t)
+
+	now := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	service.On("GetCurrencyRatesInInterval", mock.Anything, mock.Anything).
+		Return([]repository.CurrencyRate{{
+			Date:      now,
+			Rate:      decimal.RequireFromString("200"),
+			Derived:   true,
+			FetchedAt: now.Add(15 * time.Hour),
+			Provider:  "ecb,json",
+			Legs: []repository.RateLeg{
+				{BaseCurrency: "EUR", TargetCurrency: "GBP", Rate: decimal.RequireFromString("0.8"), Inverted: true,
+					Provider: "ecb", FetchedAt: now.Add(16 * time.Hour)},
+				{BaseCurrency: "EUR", TargetCurrency: "JPY", Rate: decimal.RequireFromString("160"),
+					Provider: "json", FetchedAt: now.Add(15 * time.Hour)},
+			},
+		}}, nil)
+
+	req := &currency.GetRateRequest{
+		Currency:     "JPY",
+		BaseCurrency: "GBP",
+		DataFrom:     timestamppb.New(now),
+		DateTo:       timestamppb.New(now),
+	}
+
+	resp, err := server.GetRate(context.Background(), req)
+
+	require.NoError(t, err)
+	require.Len(t, resp.Rates, 1)
+	assert.True(t, resp.Rates[0].Derived)
+	require.Len(t, resp.Rates[0].Legs, 2)
+	assert.Equal(t, "GBP", resp.Rates[0].Legs[0].Currency)
+	assert.Equal(t, "0.8", resp.Rates[0].Legs[0].Rate)
+	assert.True(t, resp.Rates[0].Legs[0].Inverted)
+	assert.Equal(t, "JPY", resp.Rates[0].Legs[1].Currency)
+	assert.False(t, resp.Rates[0].Legs[1].Inverted)
+
+	assert.Equal(t, "ecb,json", resp.Rates[0].Provider)
+	assert.Equal(t, now.Add(15*time.Hour), resp.Rates[0].FetchedAt.AsTime())
+	assert.Equal(t, "ecb", resp.Rates[0].Legs[0].Provider)
+	assert.Equal(t, now.Add(16*time.Hour), resp.Rates[0].Legs[0].FetchedAt.AsTime())
+}
+
+func TestGetLatestRate_Success(t *testing.T) {
+	server, svc := newTestServer(t)
+
+	date := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+	fetchedAt := date.Add(16 * time.Hour)
+
+	svc.On("GetLatestRate", mock.Anything, mock.MatchedBy(func(req *dto.CurrencyRequestDTO) bool {
+		return req.BaseCurrency == dto.DefaultBaseCurrency && req.TargetCurrency == "eur"
+	})).Return(&service.LatestRate{
+		CurrencyRate: repository.CurrencyRate{Date: date, Rate: decimal.RequireFromString("0.97"), FetchedAt: fetchedAt},
+		Age:          30 * time.Hour,
+		MaxAge:       24 * time.Hour,
+		FixingDate:   date.AddDate(0, 0, 1),
+		Stale:        true,
+	}, nil)
+
+	resp, err := server.GetLatestRate(context.Background(), &currency.GetLatestRateRequest{Currency: "eur"})
+
+	require.NoError(t, err)
+	assert.Equal(t, "EUR", resp.Currency)
+	assert.Equal(t, "USD", resp.BaseCurrency)
+	assert.Equal(t, date, resp.Rate.Date.AsTime())
+	assert.Equal(t, "0.97", resp.Rate.ExactRate)
+	assert.Equal(t, fetchedAt, resp.FetchedAt.AsTime())
+	assert.Equal(t, 30*time.Hour, resp.Age.AsDuration())
+	assert.Equal(t, 24*time.Hour, resp.MaxAge.AsDuration())
+	assert.Equal(t, date.AddDate(0, 0, 1), resp.FixingDate.AsTime())
+	assert.True(t, resp.Stale)
+}
+
+func TestGetLatestRate_UnknownFetchTime(t *testing.T) {
+	server, svc := newTestServer(t)
+
+	svc.On("GetLatestRate", mock.Anything, mock.Anything).Return(&service.LatestRate{
+		CurrencyRate: repository.CurrencyRate{Date: time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC), Rate: decimal.RequireFromString("0.97")},
+	}, nil)
+
+	resp, err := server.GetLatestRate(context.Background(), &currency.GetLatestRateRequest{Currency: "eur"})
+
+	require.NoError(t, err)
+	// Нулевое время не превращается в 0001-01-01
+	assert.Nil(t, resp.FetchedAt)
+}
+
+func TestGetLatestRate_NotFound(t *testing.T) {
+	server, svc := newTestServer(t)
+
+	svc.On("GetLatestRate", mock.Anything, mock.Anything).
+		Return(nil, service.ErrRateNotFound)
+
+	_, err := server.GetLatestRate(context.Background(), &currency.GetLatestRateRequest{Currency: "EUR"})
+
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestBatchGetRates_OrderAndErrors(t *testing.T) {
+	server, svc := newTestServer(t)
+
+	date := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	svc.On("BatchGetRates", mock.Anything, mock.MatchedBy(func(queries []dto.RateQueryDTO) bool {
+		// Запрос без даты в сервис не передаётся
+		return len(queries) == 2 &&
+			queries[0].BaseCurrency == "EUR" && queries[0].TargetCurrency == "USD" &&
+			queries[1].BaseCurrency == dto.DefaultBaseCurrency && queries[1].TargetCurrency == "CHF"
+	})).Return([]service.BatchRateResult{
+		{Rate: &repository.CurrencyRate{Date: date, Rate: decimal.RequireFromString("1.03")}, FixingDate: date},
+		{Err: fmt.Errorf("%w: USD/CHF", service.ErrRateNotFound), FixingDate: date},
+	}, nil)
+
+	resp, err := server.BatchGetRates(context.Background(), &currency.BatchGetRatesRequest{
+		Queries: []*currency.RateQuery{
+			{BaseCurrency: "EUR", Currency: "USD", Date: timestamppb.New(date)},
+			{Currency: "GBP"},
+			{Currency: "CHF", Date: timestamppb.New(date)},
+		},
+	})
+
+	require.NoError(t, err)
+	require.Len(t, resp.Results, 3)
+
+	assert.Equal(t, "USD", resp.Results[0].Query.Currency)
+	assert.Equal(t, "1.03", resp.Results[0].GetRate().ExactRate)
+	assert.Equal(t, date, resp.Results[0].FixingDate.AsTime())
+	assert.Nil(t, resp.Results[0].GetError())
+	assert.Nil(t, resp.Results[1].FixingDate)
+
+	assert.Equal(t, int32(codes.InvalidArgument), resp.Results[1].GetError().Code)
+	assert.Equal(t, int32(codes.NotFound), resp.Results[2].GetError().Code)
+}
+
+func TestBatchGetRates_Empty(t *testing.T) {
+	server, _ := newTestServer(t)
+
+	_, err := server.BatchGetRates(context.Background(), &currency.BatchGetRatesRequest{})
+
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+// testRateStream записывает отправленные сообщения; остальные методы
+// grpc.ServerStream в тестах не вызываются
+type testRateStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	sent chan *currency.SubscribeRatesResponse
+}
+
+func (s *testRateStream) Context() context.Context { return s.ctx }
+
+func (s *testRateStream) Send(resp *currency.SubscribeRatesResponse) error {
+	s.sent <- resp
+	return nil
+}
+
+func TestSubscribeRates_StreamsUntilCancelled(t *testing.T) {
+	server, svc := newTestServer(t)
+
+	bus := events.NewBus(nil, slog.Default())
+	pair := dto.CurrencyPairDTO{BaseCurrency: "EUR", TargetCurrency: "USD"}
+	svc.On("SubscribeRates", mock.Anything, []dto.CurrencyPairDTO{pair}).
+		Return(bus.Subscribe([]dto.CurrencyPairDTO{pair}, 4), nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := &testRateStream{ctx: ctx, sent: make(chan *currency.SubscribeRatesResponse, 4)}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- server.SubscribeRates(&currency.SubscribeRatesRequest{
+			Pairs: []*currency.CurrencyPair{{BaseCurrency: "EUR", Currency: "USD"}},
+		}, stream)
+	}()
+
+	date := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+	bus.Deliver([]dto.RateRecordDTO{{Date: date, BaseCurrency: "EUR", TargetCurrency: "USD", Value: decimal.RequireFromString("1.03")}})
+
+	resp := <-stream.sent
+	assert.Equal(t, "EUR", resp.BaseCurrency)
+	assert.Equal(t, "USD", resp.Currency)
+	assert.Equal(t, "1.03", resp.Rate.ExactRate)
+	assert.Equal(t, date, resp.Rate.Date.AsTime())
+
+	cancel()
+	assert.Equal(t, codes.Canceled, status.Code(<-errCh))
+}
+
+func TestSubscribeRates_SlowConsumer(t *testing.T) {
+	server, svc := newTestServer(t)
+
+	bus := events.NewBus(nil, slog.Default())
+	pair := dto.CurrencyPairDTO{BaseCurrency: dto.DefaultBaseCurrency, TargetCurrency: "EUR"}
+	subscription := bus.Subscribe([]dto.CurrencyPairDTO{pair}, 1)
+	svc.On("SubscribeRates", mock.Anything, []dto.CurrencyPairDTO{pair}).Return(subscription, nil)
+
+	// Переполняем буфер до начала чтения
+	bus.Deliver([]dto.RateRecordDTO{
+		{BaseCurrency: pair.BaseCurrency, TargetCurrency: "EUR", Value: decimal.RequireFromString("0.97")},
+		{BaseCurrency: pair.BaseCurrency, TargetCurrency: "EUR", Value: decimal.RequireFromString("0.98")},
+	})
+
+	stream := &testRateStream{ctx: context.Background(), sent: make(chan *currency.SubscribeRatesResponse, 4)}
+	err := server.SubscribeRates(&currency.SubscribeRatesRequest{
+		Pairs: []*currency.CurrencyPair{{Currency: "EUR"}},
+	}, stream)
+
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.Len(t, stream.sent, 1)
+}
+
+func TestSubscribeRates_InvalidRequest(t *testing.T) {
+	server, svc := newTestServer(t)
+
+	stream := &testRateStream{ctx: context.Background()}
+
+	err := server.SubscribeRates(&currency.SubscribeRatesRequest{}, stream)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	err = server.SubscribeRates(&currency.SubscribeRatesRequest{
+		Pairs: []*currency.CurrencyPair{{BaseCurrency: "EUR"}},
+	}, stream)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	err = server.SubscribeRates(&currency.SubscribeRatesRequest{
+		Pairs: []*currency.CurrencyPair{{Currency: "EUR"}, {Currency: "ABC"}},
+	}, stream)
+	assertFieldViolations(t, err, "pairs[1].currency")
+
+	svc.AssertNotCalled(t, "SubscribeRates", mock.Anything, mock.Anything)
+}
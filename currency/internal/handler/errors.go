@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"my-currency-service/currency/internal/repository"
+	"my-currency-service/currency/internal/service"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errorStatus maps service and repository errors to gRPC statuses.
+// Unexpected errors become Internal without details of the cause.
+func errorStatus(err error) *status.Status {
+	if st, ok := status.FromError(err); ok {
+		return st
+	}
+
+	switch {
+	case errors.Is(err, service.ErrUnknownCurrency):
+		return status.New(codes.InvalidArgument, err.Error())
+	case errors.Is(err, service.ErrRateNotFound), errors.Is(err, repository.ErrNotFound):
+		return status.New(codes.NotFound, err.Error())
+	case errors.Is(err, service.ErrJobRunsUnavailable):
+		return status.New(codes.Unimplemented, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.New(codes.DeadlineExceeded, "deadline exceeded")
+	case errors.Is(err, context.Canceled):
+		return status.New(codes.Canceled, "request canceled")
+	case errors.Is(err, repository.ErrUnavailable):
+		return status.New(codes.Unavailable, "rate storage is unavailable, retry later")
+	default:
+		return status.New(codes.Internal, "internal error")
+	}
+}
+
+// statusError converts err returned by the service from method into a
+// gRPC status error. Server-side failures are logged with the cause.
+func (s CurrencyServer) statusError(ctx context.Context, method string, err error) error {
+	st := errorStatus(err)
+
+	switch st.Code() {
+	case codes.Internal, codes.Unavailable:
+		s.logger.ErrorContext(ctx, "request failed",
+			slog.String("method", method),
+			slog.String("code", st.Code().String()),
+			slog.Any("error", err))
+	}
+
+	return st.Err()
+}
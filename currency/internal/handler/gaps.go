@@ -0,0 +1,27 @@
+package handler
+
+import (
+	"context"
+	"my-currency-service/currency/internal/dto"
+	"my-currency-service/pkg/currency"
+)
+
+// GetGapReport checks every requested pair against the publishing calendar
+// of the provider.
+func (s CurrencyServer) GetGapReport(ctx context.Context, request *currency.GetGapReportRequest) (*currency.GetGapReportResponse, error) {
+	if err := validateGetGapReportRequest(request); err != nil {
+		return nil, err
+	}
+
+	queries := dto.GapQueryDTOsFromProtobuf(request)
+	reports := make([]*currency.GapReport, 0, len(queries))
+	for i := range queries {
+		report, err := s.service.FindGaps(ctx, &queries[i])
+		if err != nil {
+			return nil, s.statusError(ctx, "GetGapReport", err)
+		}
+		reports = append(reports, report.ToProtobuf())
+	}
+
+	return &currency.GetGapReportResponse{Reports: reports}, nil
+}
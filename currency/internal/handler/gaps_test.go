@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"context"
+	"my-currency-service/currency/internal/dto"
+	"my-currency-service/currency/internal/repository"
+	"my-currency-service/pkg/currency"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestGetGapReport_Success(t *testing.T) {
+	server, service := newTestServer(t)
+
+	from := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 1, 8, 0, 0, 0, 0, time.UTC)
+
+	service.On("FindGaps", mock.Anything, &dto.GapQueryDTO{
+		Provider: "ecb", BaseCurrency: "EUR", TargetCurrency: "USD", DateFrom: from, DateTo: to,
+	}).Return(&dto.GapReportDTO{
+		Provider: "ecb", BaseCurrency: "EUR", TargetCurrency: "USD", DateFrom: from, DateTo: to,
+		ExpectedDays: 5,
+		Missing:      []time.Time{from.AddDate(0, 0, 1)},
+	}, nil)
+	service.On("FindGaps", mock.Anything, &dto.GapQueryDTO{
+		Provider: "ecb", BaseCurrency: dto.DefaultBaseCurrency, TargetCurrency: "RUB", DateFrom: from, DateTo: to,
+	}).Return(&dto.GapReportDTO{
+		Provider: "ecb", BaseCurrency: dto.DefaultBaseCurrency, TargetCurrency: "RUB", DateFrom: from, DateTo: to,
+		ExpectedDays: 5,
+	}, nil)
+
+	resp, err := server.GetGapReport(context.Background(), &currency.GetGapReportRequest{
+		Pairs:    []*currency.CurrencyPair{{BaseCurrency: "EUR", Currency: "USD"}, {Currency: "RUB"}},
+		Provider: "ecb",
+		DateFrom: timestamppb.New(from),
+		DateTo:   timestamppb.New(to),
+	})
+	require.NoError(t, err)
+
+	require.Len(t, resp.Reports, 2)
+	assert.Equal(t, "USD", resp.Reports[0].Currency)
+	assert.Equal(t, int32(5), resp.Reports[0].ExpectedDays)
+	require.Len(t, resp.Reports[0].MissingDates, 1)
+	assert.Equal(t, from.AddDate(0, 0, 1), resp.Reports[0].MissingDates[0].AsTime())
+	assert.Equal(t, "RUB", resp.Reports[1].Currency)
+	assert.Empty(t, resp.Reports[1].MissingDates)
+}
+
+func TestGetGapReport_Validation(t *testing.T) {
+	server, service := newTestServer(t)
+
+	_, err := server.GetGapReport(context.Background(), &currency.GetGapReportRequest{})
+	assertFieldViolations(t, err, "pairs")
+
+	_, err = server.GetGapReport(context.Background(), &currency.GetGapReportRequest{
+		Pairs:    []*currency.CurrencyPair{{Currency: "XYZ"}},
+		Provider: "bloomberg",
+		DateFrom: timestamppb.New(time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)),
+		DateTo:   timestamppb.New(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)),
+	})
+	assertFieldViolations(t, err, "pairs[0].currency", "provider")
+
+	_, err = server.GetGapReport(context.Background(), &currency.GetGapReportRequest{
+		Pairs:    []*currency.CurrencyPair{{Currency: "USD"}},
+		DateFrom: timestamppb.New(time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)),
+		DateTo:   timestamppb.New(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)),
+	})
+	assertFieldViolations(t, err, "date_from")
+
+	service.AssertNotCalled(t, "FindGaps", mock.Anything, mock.Anything)
+}
+
+func TestGetGapReport_Unavailable(t *testing.T) {
+	server, service := newTestServer(t)
+
+	service.On("FindGaps", mock.Anything, mock.Anything).Return(nil, repository.ErrUnavailable)
+
+	_, err := server.GetGapReport(context.Background(), &currency.GetGapReportRequest{
+		Pairs: []*currency.CurrencyPair{{Currency: "USD"}},
+	})
+
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+}
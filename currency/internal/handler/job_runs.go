@@ -0,0 +1,25 @@
+package handler
+
+import (
+	"context"
+	"my-currency-service/currency/internal/dto"
+	"my-currency-service/pkg/currency"
+)
+
+// GetJobRuns returns the run history of the worker jobs, newest first.
+func (s CurrencyServer) GetJobRuns(ctx context.Context, request *currency.GetJobRunsRequest) (*currency.GetJobRunsResponse, error) {
+	if err := validateGetJobRunsRequest(request); err != nil {
+		return nil, err
+	}
+
+	runs, err := s.service.GetJobRuns(ctx, dto.JobRunQueryDTOFromProtobuf(request))
+	if err != nil {
+		return nil, s.statusError(ctx, "GetJobRuns", err)
+	}
+
+	response := &currency.GetJobRunsResponse{Runs: make([]*currency.JobRun, 0, len(runs))}
+	for i := range runs {
+		response.Runs = append(response.Runs, runs[i].ToProtobuf())
+	}
+	return response, nil
+}
@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"context"
+	"my-currency-service/currency/internal/dto"
+	"my-currency-service/currency/internal/service"
+	"my-currency-service/pkg/currency"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGetJobRuns_Success(t *testing.T) {
+	server, svc := newTestServer(t)
+
+	start := time.Date(2025, 1, 15, 16, 30, 0, 0, time.UTC)
+	svc.On("GetJobRuns", mock.Anything, &dto.JobRunQueryDTO{Job: "ecb-eur", TargetCurrency: "USD", Status: "success", Limit: 1}).
+		Return([]dto.JobRunDTO{{
+			ID: 7, Job: "ecb-eur", Trigger: "schedule", Provider: "ecb", BaseCurrency: "EUR", TargetCurrency: "USD",
+			StartedAt: start, FinishedAt: start.Add(1500 * time.Millisecond), Status: dto.JobRunSuccess, RowsWritten: 2,
+		}}, nil)
+
+	resp, err := server.GetJobRuns(context.Background(), &currency.GetJobRunsRequest{
+		Job: "ecb-eur", Currency: "USD", Status: "success", Limit: 1,
+	})
+	require.NoError(t, err)
+
+	require.Len(t, resp.Runs, 1)
+	run := resp.Runs[0]
+	assert.Equal(t, int64(7), run.Id)
+	assert.Equal(t, "USD", run.Currency)
+	assert.Equal(t, start, run.StartedAt.AsTime())
+	assert.Equal(t, 1500*time.Millisecond, run.Duration.AsDuration())
+	assert.Equal(t, int32(2), run.RowsWritten)
+}
+
+func TestGetJobRuns_Validation(t *testing.T) {
+	server, svc := newTestServer(t)
+
+	_, err := server.GetJobRuns(context.Background(), &currency.GetJobRunsRequest{
+		Currency: "XYZ",
+		Status:   "running",
+		Limit:    5000,
+	})
+	assertFieldViolations(t, err, "currency", "status", "limit")
+
+	svc.AssertNotCalled(t, "GetJobRuns", mock.Anything, mock.Anything)
+}
+
+func TestGetJobRuns_NoHistory(t *testing.T) {
+	server, svc := newTestServer(t)
+
+	svc.On("GetJobRuns", mock.Anything, mock.Anything).Return(nil, service.ErrJobRunsUnavailable)
+
+	_, err := server.GetJobRuns(context.Background(), &currency.GetJobRunsRequest{})
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+}
@@ -5,10 +5,13 @@ package mocks
 import (
 	context "context"
 	dto "my-currency-service/currency/internal/dto"
+	events "my-currency-service/currency/internal/events"
 
 	mock "github.com/stretchr/testify/mock"
 
 	repository "my-currency-service/currency/internal/repository"
+
+	service "my-currency-service/currency/internal/service"
 )
 
 // CurrencyService is an autogenerated mock type for the CurrencyService type
@@ -16,6 +19,96 @@ type CurrencyService struct {
 	mock.Mock
 }
 
+// BatchGetRates provides a mock function with given fields: ctx, queries
+func (_m *CurrencyService) BatchGetRates(ctx context.Context, queries []dto.RateQueryDTO) ([]service.BatchRateResult, error) {
+	ret := _m.Called(ctx, queries)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BatchGetRates")
+	}
+
+	var r0 []service.BatchRateResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []dto.RateQueryDTO) ([]service.BatchRateResult, error)); ok {
+		return rf(ctx, queries)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []dto.RateQueryDTO) []service.BatchRateResult); ok {
+		r0 = rf(ctx, queries)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]service.BatchRateResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []dto.RateQueryDTO) error); ok {
+		r1 = rf(ctx, queries)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Convert provides a mock function with given fields: ctx, reqDTO
+func (_m *CurrencyService) Convert(ctx context.Context, reqDTO *dto.ConvertRequestDTO) (*dto.ConversionDTO, error) {
+	ret := _m.Called(ctx, reqDTO)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Convert")
+	}
+
+	var r0 *dto.ConversionDTO
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *dto.ConvertRequestDTO) (*dto.ConversionDTO, error)); ok {
+		return rf(ctx, reqDTO)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *dto.ConvertRequestDTO) *dto.ConversionDTO); ok {
+		r0 = rf(ctx, reqDTO)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.ConversionDTO)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *dto.ConvertRequestDTO) error); ok {
+		r1 = rf(ctx, reqDTO)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindGaps provides a mock function with given fields: ctx, query
+func (_m *CurrencyService) FindGaps(ctx context.Context, query *dto.GapQueryDTO) (*dto.GapReportDTO, error) {
+	ret := _m.Called(ctx, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindGaps")
+	}
+
+	var r0 *dto.GapReportDTO
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *dto.GapQueryDTO) (*dto.GapReportDTO, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *dto.GapQueryDTO) *dto.GapReportDTO); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.GapReportDTO)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *dto.GapQueryDTO) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetCurrencyRatesInInterval provides a mock function with given fields: ctx, reqDTO
 func (_m *CurrencyService) GetCurrencyRatesInInterval(ctx context.Context, reqDTO *dto.CurrencyRequestDTO) ([]repository.CurrencyRate, error) {
 	ret := _m.Called(ctx, reqDTO)
@@ -46,6 +139,96 @@ func (_m *CurrencyService) GetCurrencyRatesInInterval(ctx context.Context, reqDT
 	return r0, r1
 }
 
+// GetJobRuns provides a mock function with given fields: ctx, query
+func (_m *CurrencyService) GetJobRuns(ctx context.Context, query *dto.JobRunQueryDTO) ([]dto.JobRunDTO, error) {
+	ret := _m.Called(ctx, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetJobRuns")
+	}
+
+	var r0 []dto.JobRunDTO
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *dto.JobRunQueryDTO) ([]dto.JobRunDTO, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *dto.JobRunQueryDTO) []dto.JobRunDTO); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]dto.JobRunDTO)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *dto.JobRunQueryDTO) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetLatestRate provides a mock function with given fields: ctx, reqDTO
+func (_m *CurrencyService) GetLatestRate(ctx context.Context, reqDTO *dto.CurrencyRequestDTO) (*service.LatestRate, error) {
+	ret := _m.Called(ctx, reqDTO)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLatestRate")
+	}
+
+	var r0 *service.LatestRate
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *dto.CurrencyRequestDTO) (*service.LatestRate, error)); ok {
+		return rf(ctx, reqDTO)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *dto.CurrencyRequestDTO) *service.LatestRate); ok {
+		r0 = rf(ctx, reqDTO)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*service.LatestRate)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *dto.CurrencyRequestDTO) error); ok {
+		r1 = rf(ctx, reqDTO)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SubscribeRates provides a mock function with given fields: ctx, pairs
+func (_m *CurrencyService) SubscribeRates(ctx context.Context, pairs []dto.CurrencyPairDTO) (*events.Subscription, error) {
+	ret := _m.Called(ctx, pairs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SubscribeRates")
+	}
+
+	var r0 *events.Subscription
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []dto.CurrencyPairDTO) (*events.Subscription, error)); ok {
+		return rf(ctx, pairs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []dto.CurrencyPairDTO) *events.Subscription); ok {
+		r0 = rf(ctx, pairs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*events.Subscription)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []dto.CurrencyPairDTO) error); ok {
+		r1 = rf(ctx, pairs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewCurrencyService creates a new instance of CurrencyService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewCurrencyService(t interface {
@@ -4,14 +4,20 @@ import (
 	"context"
 	"log/slog"
 	"my-currency-service/currency/internal/dto"
+	"my-currency-service/currency/internal/events"
 	"my-currency-service/currency/internal/repository"
+	"my-currency-service/currency/internal/service"
 	"my-currency-service/pkg/currency"
-
-	"github.com/prometheus/client_golang/prometheus"
 )
 
 type CurrencyService interface {
 	GetCurrencyRatesInInterval(ctx context.Context, reqDTO *dto.CurrencyRequestDTO) ([]repository.CurrencyRate, error)
+	Convert(ctx context.Context, reqDTO *dto.ConvertRequestDTO) (*dto.ConversionDTO, error)
+	GetLatestRate(ctx context.Context, reqDTO *dto.CurrencyRequestDTO) (*service.LatestRate, error)
+	BatchGetRates(ctx context.Context, queries []dto.RateQueryDTO) ([]service.BatchRateResult, error)
+	SubscribeRates(ctx context.Context, pairs []dto.CurrencyPairDTO) (*events.Subscription, error)
+	FindGaps(ctx context.Context, query *dto.GapQueryDTO) (*dto.GapReportDTO, error)
+	GetJobRuns(ctx context.Context, query *dto.JobRunQueryDTO) ([]dto.JobRunDTO, error)
 }
 
 // todo tests
@@ -19,21 +25,13 @@ type CurrencyServer struct {
 	currency.UnimplementedCurrencyServiceServer
 	service CurrencyService
 	logger  *slog.Logger
-
-	requestCount    *prometheus.CounterVec
-	requestDuration *prometheus.HistogramVec
-	appUptime       *prometheus.Gauge
 }
 
-func NewCurrencyServer(svc CurrencyService, logger *slog.Logger,
-	requestCount *prometheus.CounterVec, requestDuration *prometheus.HistogramVec,
-	appUptime *prometheus.Gauge) *CurrencyServer {
-
+// NewCurrencyServer creates the gRPC handler. Metrics, access logs and
+// panic recovery are provided by the interceptor chain of the server.
+func NewCurrencyServer(svc CurrencyService, logger *slog.Logger) *CurrencyServer {
 	return &CurrencyServer{
-		service:         svc,
-		logger:          logger,
-		requestCount:    requestCount,
-		requestDuration: requestDuration,
-		appUptime:       appUptime,
+		service: svc,
+		logger:  logger,
 	}
 }
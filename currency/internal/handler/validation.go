@@ -0,0 +1,186 @@
+package handler
+
+import (
+	"fmt"
+	currencyClient "my-currency-service/currency/internal/clients/currency"
+	"my-currency-service/currency/internal/dto"
+	"my-currency-service/currency/internal/iso4217"
+	"my-currency-service/currency/internal/service"
+	"my-currency-service/pkg/currency"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Максимальная длина интервала GetRate: год с запасом на високосный
+const maxRateRangeDays = 366
+
+// Пар в одном GetGapReport: каждая — отдельный запрос к базе
+const maxGapPairs = 100
+
+// violations collects the invalid fields of a request and turns them into
+// an InvalidArgument status with a BadRequest detail.
+type violations []*errdetails.BadRequest_FieldViolation
+
+func (v *violations) add(field, format string, args ...any) {
+	*v = append(*v, &errdetails.BadRequest_FieldViolation{
+		Field:       field,
+		Description: fmt.Sprintf(format, args...),
+	})
+}
+
+// currency checks an ISO 4217 code; an empty optional code is valid.
+func (v *violations) currency(field, code string, required bool) {
+	switch {
+	case code == "" && required:
+		v.add(field, "currency is required")
+	case code != "" && !iso4217.Valid(code):
+		v.add(field, "unknown ISO 4217 currency code %q", code)
+	}
+}
+
+func (v *violations) timestamp(field string, ts *timestamppb.Timestamp) {
+	switch {
+	case ts == nil:
+		v.add(field, "timestamp is required")
+	case ts.CheckValid() != nil:
+		v.add(field, "invalid timestamp: %v", ts.CheckValid())
+	}
+}
+
+func (v violations) err() error {
+	if len(v) == 0 {
+		return nil
+	}
+
+	st := status.New(codes.InvalidArgument, v.message())
+	detailed, err := st.WithDetails(&errdetails.BadRequest{FieldViolations: v})
+	if err != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
+
+func (v violations) message() string {
+	if len(v) == 1 {
+		return fmt.Sprintf("invalid %s: %s", v[0].Field, v[0].Description)
+	}
+	return fmt.Sprintf("invalid %s: %s (and %d more)", v[0].Field, v[0].Description, len(v)-1)
+}
+
+func validateGetRateRequest(req *currency.GetRateRequest) error {
+	var v violations
+	v.currency("currency", req.GetCurrency(), true)
+	v.currency("base_currency", req.GetBaseCurrency(), false)
+	v.timestamp("data_from", req.GetDataFrom())
+	v.timestamp("date_to", req.GetDateTo())
+
+	if len(v) == 0 {
+		dateFrom, dateTo := req.GetDataFrom().AsTime(), req.GetDateTo().AsTime()
+		switch {
+		case dateFrom.After(dateTo):
+			v.add("data_from", "must not be after date_to")
+		case dateTo.Sub(dateFrom) > maxRateRangeDays*24*time.Hour:
+			v.add("date_to", "interval must not exceed %d days", maxRateRangeDays)
+		}
+	}
+
+	return v.err()
+}
+
+func validateGetLatestRateRequest(req *currency.GetLatestRateRequest) error {
+	var v violations
+	v.currency("currency", req.GetCurrency(), true)
+	v.currency("base_currency", req.GetBaseCurrency(), false)
+	return v.err()
+}
+
+func validateConvertRequest(req *currency.ConvertRequest) error {
+	var v violations
+	if req.GetAmount() == "" {
+		v.add("amount", "amount is required")
+	} else if _, err := decimal.NewFromString(req.GetAmount()); err != nil {
+		v.add("amount", "invalid decimal %q", req.GetAmount())
+	}
+	v.currency("from", req.GetFrom(), true)
+	v.currency("to", req.GetTo(), true)
+	if req.GetAsOf() != nil {
+		v.timestamp("as_of", req.GetAsOf())
+	}
+	return v.err()
+}
+
+// validateRateQuery validates one BatchGetRates query; field names are
+// relative to the query.
+func validateRateQuery(query *currency.RateQuery) violations {
+	var v violations
+	v.currency("currency", query.GetCurrency(), true)
+	v.currency("base_currency", query.GetBaseCurrency(), false)
+	v.timestamp("date", query.GetDate())
+	return v
+}
+
+func validateGetGapReportRequest(req *currency.GetGapReportRequest) error {
+	var v violations
+	if len(req.GetPairs()) == 0 || len(req.GetPairs()) > maxGapPairs {
+		v.add("pairs", "number of pairs must be between 1 and %d, got %d", maxGapPairs, len(req.GetPairs()))
+	}
+	for i, pair := range req.GetPairs() {
+		v.currency(fmt.Sprintf("pairs[%d].currency", i), pair.GetCurrency(), true)
+		v.currency(fmt.Sprintf("pairs[%d].base_currency", i), pair.GetBaseCurrency(), false)
+	}
+	if provider := req.GetProvider(); provider != "" && !slices.Contains(currencyClient.Providers(), strings.ToLower(provider)) {
+		v.add("provider", "unknown provider %q", provider)
+	}
+	if req.GetDateFrom() != nil {
+		v.timestamp("date_from", req.GetDateFrom())
+	}
+	if req.GetDateTo() != nil {
+		v.timestamp("date_to", req.GetDateTo())
+	}
+
+	if len(v) == 0 && req.GetDateFrom() != nil && req.GetDateTo() != nil {
+		dateFrom, dateTo := req.GetDateFrom().AsTime(), req.GetDateTo().AsTime()
+		switch {
+		case dateFrom.After(dateTo):
+			v.add("date_from", "must not be after date_to")
+		case dateTo.Sub(dateFrom) > maxRateRangeDays*24*time.Hour:
+			v.add("date_to", "interval must not exceed %d days", maxRateRangeDays)
+		}
+	}
+
+	return v.err()
+}
+
+func validateGetJobRunsRequest(req *currency.GetJobRunsRequest) error {
+	var v violations
+	v.currency("base_currency", req.GetBaseCurrency(), false)
+	v.currency("currency", req.GetCurrency(), false)
+	switch strings.ToLower(req.GetStatus()) {
+	case "", dto.JobRunSuccess, dto.JobRunFailure:
+	default:
+		v.add("status", "status must be %q or %q, got %q", dto.JobRunSuccess, dto.JobRunFailure, req.GetStatus())
+	}
+	if req.GetLimit() < 0 || req.GetLimit() > service.MaxJobRunsLimit {
+		v.add("limit", "limit must be between 0 and %d, got %d", service.MaxJobRunsLimit, req.GetLimit())
+	}
+	return v.err()
+}
+
+func validateSubscribeRatesRequest(req *currency.SubscribeRatesRequest) error {
+	var v violations
+	if len(req.GetPairs()) == 0 {
+		v.add("pairs", "at least one pair is required")
+	}
+	for i, pair := range req.GetPairs() {
+		v.currency(fmt.Sprintf("pairs[%d].currency", i), pair.GetCurrency(), true)
+		v.currency(fmt.Sprintf("pairs[%d].base_currency", i), pair.GetBaseCurrency(), false)
+	}
+	return v.err()
+}
@@ -0,0 +1,166 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"my-currency-service/currency/internal/repository"
+	"my-currency-service/currency/internal/service"
+	"my-currency-service/pkg/currency"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// assertFieldViolations проверяет код InvalidArgument и поля в BadRequest
+func assertFieldViolations(t *testing.T, err error, fields ...string) {
+	t.Helper()
+
+	st := status.Convert(err)
+	require.Equal(t, codes.InvalidArgument, st.Code(), st.Message())
+
+	var got []string
+	for _, detail := range st.Details() {
+		if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+			for _, violation := range badRequest.GetFieldViolations() {
+				got = append(got, violation.GetField())
+			}
+		}
+	}
+	assert.Equal(t, fields, got)
+}
+
+func TestGetRate_Validation(t *testing.T) {
+	date := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		req    *currency.GetRateRequest
+		fields []string
+	}{
+		{
+			name:   "missing currency and timestamps",
+			req:    &currency.GetRateRequest{},
+			fields: []string{"currency", "data_from", "date_to"},
+		},
+		{
+			name: "unknown codes",
+			req: &currency.GetRateRequest{
+				Currency: "EURO", BaseCurrency: "ABC",
+				DataFrom: timestamppb.New(date), DateTo: timestamppb.New(date),
+			},
+			fields: []string{"currency", "base_currency"},
+		},
+		{
+			name: "reversed dates",
+			req: &currency.GetRateRequest{
+				Currency: "EUR",
+				DataFrom: timestamppb.New(date), DateTo: timestamppb.New(date.AddDate(0, 0, -1)),
+			},
+			fields: []string{"data_from"},
+		},
+		{
+			name: "range too long",
+			req: &currency.GetRateRequest{
+				Currency: "EUR",
+				DataFrom: timestamppb.New(date), DateTo: timestamppb.New(date.AddDate(0, 0, maxRateRangeDays+1)),
+			},
+			fields: []string{"date_to"},
+		},
+		{
+			name: "invalid timestamp",
+			req: &currency.GetRateRequest{
+				Currency: "EUR",
+				DataFrom: &timestamppb.Timestamp{Seconds: date.Unix(), Nanos: -1}, DateTo: timestamppb.New(date),
+			},
+			fields: []string{"data_from"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, svc := newTestServer(t)
+
+			_, err := server.GetRate(context.Background(), tt.req)
+
+			assertFieldViolations(t, err, tt.fields...)
+			svc.AssertNotCalled(t, "GetCurrencyRatesInInterval", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestGetRate_ErrorMapping(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code codes.Code
+	}{
+		{"unknown currency", fmt.Errorf("%w: XYZ", service.ErrUnknownCurrency), codes.InvalidArgument},
+		{"rate not found", fmt.Errorf("%w: EUR/USD", service.ErrRateNotFound), codes.NotFound},
+		{"repository not found", fmt.Errorf("query: %w", repository.ErrNotFound), codes.NotFound},
+		{"database unavailable", fmt.Errorf("query: %w: %w", repository.ErrUnavailable, errors.New("connection refused")), codes.Unavailable},
+		{"deadline", fmt.Errorf("query: %w", context.DeadlineExceeded), codes.DeadlineExceeded},
+		{"canceled", fmt.Errorf("query: %w", context.Canceled), codes.Canceled},
+		{"unexpected", errors.New("boom"), codes.Internal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, svc := newTestServer(t)
+			svc.On("GetCurrencyRatesInInterval", mock.Anything, mock.Anything).Return(nil, tt.err)
+
+			_, err := server.GetRate(context.Background(), &currency.GetRateRequest{
+				Currency: "EUR",
+				DataFrom: timestamppb.New(time.Now()),
+				DateTo:   timestamppb.New(time.Now()),
+			})
+
+			assert.Equal(t, tt.code, status.Code(err))
+		})
+	}
+}
+
+func TestConvert_Validation(t *testing.T) {
+	server, _ := newTestServer(t)
+
+	_, err := server.Convert(context.Background(), &currency.ConvertRequest{Amount: "1,5", From: "usd", To: "XYZ"})
+
+	assertFieldViolations(t, err, "amount", "to")
+}
+
+func TestGetLatestRate_Validation(t *testing.T) {
+	server, _ := newTestServer(t)
+
+	_, err := server.GetLatestRate(context.Background(), &currency.GetLatestRateRequest{BaseCurrency: "US"})
+
+	assertFieldViolations(t, err, "currency", "base_currency")
+}
+
+func TestBatchGetRates_TooManyQueries(t *testing.T) {
+	server, _ := newTestServer(t)
+
+	_, err := server.BatchGetRates(context.Background(), &currency.BatchGetRatesRequest{
+		Queries: make([]*currency.RateQuery, maxBatchSize+1),
+	})
+
+	assertFieldViolations(t, err, "queries")
+}
+
+func TestBatchGetRates_InvalidQuery(t *testing.T) {
+	server, _ := newTestServer(t)
+
+	resp, err := server.BatchGetRates(context.Background(), &currency.BatchGetRatesRequest{
+		Queries: []*currency.RateQuery{{Currency: "XYZ", Date: timestamppb.Now()}},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, int32(codes.InvalidArgument), resp.Results[0].GetError().GetCode())
+	assert.Contains(t, resp.Results[0].GetError().GetMessage(), "currency")
+}
@@ -0,0 +1,41 @@
+package interceptor
+
+import (
+	"log/slog"
+	"my-currency-service/currency/internal/auth"
+	"my-currency-service/currency/internal/ratelimit"
+
+	"google.golang.org/grpc"
+)
+
+// ServerOptions returns the interceptor chain of the server. Recovery is
+// innermost so that metrics and logs see a panic as codes.Internal.
+// Authentication runs before logging so that access logs carry the
+// caller; rejected calls are logged by the authenticator. Rate limiting
+// runs after both: it is keyed by the caller and its rejections are
+// logged. A nil authenticator or limiter disables the step.
+func ServerOptions(logger *slog.Logger, metrics *Metrics, authenticator *auth.Authenticator, limiter *ratelimit.Interceptor) []grpc.ServerOption {
+	unary := []grpc.UnaryServerInterceptor{metrics.Unary()}
+	stream := []grpc.StreamServerInterceptor{metrics.Stream()}
+
+	if authenticator != nil {
+		unary = append(unary, authenticator.Unary())
+		stream = append(stream, authenticator.Stream())
+	}
+
+	unary = append(unary, UnaryLogging(logger))
+	stream = append(stream, StreamLogging(logger))
+
+	if limiter != nil {
+		unary = append(unary, limiter.Unary())
+		stream = append(stream, limiter.Stream())
+	}
+
+	unary = append(unary, UnaryRecovery(logger))
+	stream = append(stream, StreamRecovery(logger))
+
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(unary...),
+		grpc.ChainStreamInterceptor(stream...),
+	}
+}
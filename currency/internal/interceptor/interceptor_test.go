@@ -0,0 +1,151 @@
+package interceptor
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"log/slog"
+	"net"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const testMethod = "/currency.CurrencyService/GetRate"
+
+func newTestMetrics() (*Metrics, *prometheus.CounterVec) {
+	requestCount := prometheus.NewCounterVec(
+		prometheus.CounterOpts{Name: "test_request_count", Help: "test"},
+		[]string{"method", "code"},
+	)
+	requestDuration := prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{Name: "test_request_duration", Help: "test"},
+		[]string{"method", "code"},
+	)
+	return NewMetrics(requestCount, requestDuration), requestCount
+}
+
+// chainUnary собирает цепочку так же, как grpc.ChainUnaryInterceptor
+func chainUnary(interceptors ...grpc.UnaryServerInterceptor) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		next := handler
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor, h := interceptors[i], next
+			next = func(ctx context.Context, req any) (any, error) {
+				return interceptor(ctx, req, info, h)
+			}
+		}
+		return next(ctx, req)
+	}
+}
+
+func TestMetrics_Unary(t *testing.T) {
+	metrics, requestCount := newTestMetrics()
+	info := &grpc.UnaryServerInfo{FullMethod: testMethod}
+
+	_, _ = metrics.Unary()(context.Background(), nil, info, func(context.Context, any) (any, error) {
+		return "ok", nil
+	})
+	_, _ = metrics.Unary()(context.Background(), nil, info, func(context.Context, any) (any, error) {
+		return nil, status.Error(codes.NotFound, "rate not found")
+	})
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(requestCount.WithLabelValues("GetRate", "OK")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(requestCount.WithLabelValues("GetRate", "NotFound")))
+}
+
+func TestRecovery_PanicBecomesInternal(t *testing.T) {
+	metrics, requestCount := newTestMetrics()
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&logs, nil))
+
+	chain := chainUnary(metrics.Unary(), UnaryLogging(logger), UnaryRecovery(logger))
+
+	resp, err := chain(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: testMethod},
+		func(context.Context, any) (any, error) {
+			panic("nil map")
+		})
+
+	assert.Nil(t, resp)
+	assert.Equal(t, codes.Internal, status.Code(err))
+	assert.Equal(t, 1.0, testutil.ToFloat64(requestCount.WithLabelValues("GetRate", "Internal")))
+	assert.Contains(t, logs.String(), "panic in gRPC handler")
+}
+
+func TestUnaryLogging(t *testing.T) {
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&logs, nil))
+
+	_, _ = UnaryLogging(logger)(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: testMethod},
+		func(context.Context, any) (any, error) {
+			return nil, status.Error(codes.InvalidArgument, "currency is required")
+		})
+
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(logs.Bytes(), &entry))
+	assert.Equal(t, "WARN", entry["level"])
+	assert.Equal(t, "GetRate", entry["method"])
+	assert.Equal(t, "InvalidArgument", entry["code"])
+	assert.Equal(t, "currency is required", entry["error"])
+	assert.Contains(t, entry, "duration")
+}
+
+func TestUnaryLogging_ClientCertSubject(t *testing.T) {
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&logs, nil))
+
+	// Аутентификация выключена, субъект берётся из сертификата
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: "reporting"}}
+	ctx := peer.NewContext(context.Background(), &peer.Peer{
+		Addr: &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 40000},
+		AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{
+			VerifiedChains: [][]*x509.Certificate{{cert}},
+		}},
+	})
+
+	_, _ = UnaryLogging(logger)(ctx, nil, &grpc.UnaryServerInfo{FullMethod: testMethod},
+		func(context.Context, any) (any, error) { return "ok", nil })
+
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(logs.Bytes(), &entry))
+	assert.Equal(t, "reporting", entry["subject"])
+	assert.Equal(t, "10.0.0.1:40000", entry["peer"])
+}
+
+type testServerStream struct {
+	grpc.ServerStream
+}
+
+func (testServerStream) Context() context.Context { return context.Background() }
+
+func TestStreamChain(t *testing.T) {
+	metrics, requestCount := newTestMetrics()
+	logger := slog.New(slog.NewJSONHandler(&bytes.Buffer{}, nil))
+	info := &grpc.StreamServerInfo{FullMethod: "/currency.CurrencyService/SubscribeRates", IsServerStream: true}
+
+	recovering := func(srv any, ss grpc.ServerStream) error {
+		return StreamRecovery(logger)(srv, ss, info, func(any, grpc.ServerStream) error {
+			panic("closed channel")
+		})
+	}
+	err := metrics.Stream()(nil, testServerStream{}, info, recovering)
+
+	assert.Equal(t, codes.Internal, status.Code(err))
+	assert.Equal(t, 1.0, testutil.ToFloat64(requestCount.WithLabelValues("SubscribeRates", "Internal")))
+
+	err = StreamLogging(logger)(nil, testServerStream{}, info, func(any, grpc.ServerStream) error {
+		return nil
+	})
+	assert.NoError(t, err)
+}
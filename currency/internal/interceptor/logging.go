@@ -0,0 +1,75 @@
+package interceptor
+
+import (
+	"context"
+	"log/slog"
+	"my-currency-service/currency/internal/auth"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryLogging writes an access log entry for every unary RPC.
+func UnaryLogging(logger *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		logAccess(ctx, logger, info.FullMethod, err, time.Since(start))
+		return resp, err
+	}
+}
+
+// StreamLogging writes an access log entry when a stream ends.
+func StreamLogging(logger *slog.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		logAccess(ss.Context(), logger, info.FullMethod, err, time.Since(start))
+		return err
+	}
+}
+
+func logAccess(ctx context.Context, logger *slog.Logger, fullMethod string, err error, duration time.Duration) {
+	st := status.Convert(err)
+
+	attrs := []slog.Attr{
+		slog.String("method", methodName(fullMethod)),
+		slog.String("code", st.Code().String()),
+		slog.Duration("duration", duration),
+	}
+	// Без аутентификации субъект берётся из клиентского сертификата, если он есть
+	if identity, ok := auth.FromContext(ctx); ok {
+		attrs = append(attrs, slog.String("subject", identity.Subject))
+	} else if identity, ok := auth.PeerIdentity(ctx); ok {
+		attrs = append(attrs, slog.String("subject", identity.Subject))
+	}
+	if p, ok := peer.FromContext(ctx); ok {
+		attrs = append(attrs, slog.String("peer", p.Addr.String()))
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", st.Message()))
+	}
+
+	logger.LogAttrs(ctx, level(st.Code()), "gRPC request", attrs...)
+}
+
+// level separates server failures from client errors.
+func level(code codes.Code) slog.Level {
+	switch code {
+	case codes.Internal, codes.Unknown, codes.Unavailable, codes.DataLoss, codes.Unimplemented:
+		return slog.LevelError
+	case codes.OK, codes.Canceled:
+		return slog.LevelInfo
+	default:
+		return slog.LevelWarn
+	}
+}
+
+// methodName returns the method of "/currency.CurrencyService/GetRate".
+func methodName(fullMethod string) string {
+	return fullMethod[strings.LastIndex(fullMethod, "/")+1:]
+}
@@ -0,0 +1,54 @@
+// Package interceptor provides the gRPC server interceptors applied to
+// every RPC: metrics, access logs and panic recovery.
+package interceptor
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// Metrics records the number and latency of RPCs. Both vectors must have
+// the labels "method" and "code".
+type Metrics struct {
+	requestCount    *prometheus.CounterVec
+	requestDuration *prometheus.HistogramVec
+}
+
+func NewMetrics(requestCount *prometheus.CounterVec, requestDuration *prometheus.HistogramVec) *Metrics {
+	return &Metrics{
+		requestCount:    requestCount,
+		requestDuration: requestDuration,
+	}
+}
+
+func (m *Metrics) Unary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		m.observe(info.FullMethod, err, time.Since(start))
+		return resp, err
+	}
+}
+
+// Stream records streams when they end; the latency is the stream lifetime.
+func (m *Metrics) Stream() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		m.observe(info.FullMethod, err, time.Since(start))
+		return err
+	}
+}
+
+func (m *Metrics) observe(fullMethod string, err error, duration time.Duration) {
+	labels := prometheus.Labels{
+		"method": methodName(fullMethod),
+		"code":   status.Code(err).String(),
+	}
+	m.requestCount.With(labels).Inc()
+	m.requestDuration.With(labels).Observe(duration.Seconds())
+}
@@ -0,0 +1,145 @@
+// Package lock elects the cron replica that runs each scheduled job.
+package lock
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"hash/fnv"
+	"log/slog"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Пространство ключей advisory-локов заданий cron: "CRON"
+const lockNamespace int32 = 0x43524f4e
+
+// PostgresLocker elects the replica running each job with session-level
+// Postgres advisory locks. A lock stays held between runs, so the other
+// replicas skip the job while the holder is alive. When the holder stops
+// or loses its connection, Postgres releases its locks and the next
+// replica to tick takes over.
+//
+// The session carries the replica id as application_name, so the holder
+// of a lock is visible in pg_locks joined with pg_stat_activity.
+type PostgresLocker struct {
+	db        *sql.DB
+	replicaID string
+	held      *prometheus.GaugeVec
+	logger    *slog.Logger
+
+	mu    sync.Mutex
+	conn  *sql.Conn
+	locks map[string]bool
+}
+
+// NewPostgresLocker returns a locker of replicaID. held is a gauge with
+// labels job and replica, set to 1 while the replica holds the lock of
+// the job; nil disables it.
+func NewPostgresLocker(db *sql.DB, replicaID string, held *prometheus.GaugeVec, logger *slog.Logger) *PostgresLocker {
+	return &PostgresLocker{
+		db:        db,
+		replicaID: replicaID,
+		held:      held,
+		logger:    logger.With(slog.String("replica", replicaID)),
+		locks:     make(map[string]bool),
+	}
+}
+
+// Acquire reports whether this replica holds the lock of name, taking it
+// if it is free. An error loses all locks of the replica: their session is
+// closed so that they cannot stay held unnoticed.
+func (l *PostgresLocker) Acquire(ctx context.Context, name string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	got, err := l.tryLock(ctx, name)
+	if err != nil {
+		l.logger.Error("Failed to acquire job lock", slog.String("job", name), slog.Any("error", err))
+		l.reset()
+		return false
+	}
+
+	l.set(name, got)
+	return got
+}
+
+// Close releases all locks of the replica so that another one takes over
+// without waiting for the session to time out.
+func (l *PostgresLocker) Close() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.reset()
+}
+
+func (l *PostgresLocker) tryLock(ctx context.Context, name string) (bool, error) {
+	if l.conn == nil {
+		conn, err := l.db.Conn(ctx)
+		if err != nil {
+			return false, err
+		}
+		l.conn = conn
+
+		if _, err := conn.ExecContext(ctx, `SELECT set_config('application_name', $1, false)`, l.replicaID); err != nil {
+			return false, err
+		}
+	}
+
+	key := lockKey(name)
+
+	var got bool
+	if err := l.conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock($1, $2)`, lockNamespace, key).Scan(&got); err != nil {
+		return false, err
+	}
+
+	// Повторный захват в той же сессии увеличивает счётчик, держится один
+	if got && l.locks[name] {
+		if _, err := l.conn.ExecContext(ctx, `SELECT pg_advisory_unlock($1, $2)`, lockNamespace, key); err != nil {
+			return false, err
+		}
+	}
+
+	return got, nil
+}
+
+// reset drops the session: closing the connection is the only way to be
+// sure Postgres has released its locks.
+func (l *PostgresLocker) reset() {
+	if l.conn != nil {
+		// ErrBadConn убирает соединение из пула вместо возврата в него
+		_ = l.conn.Raw(func(any) error { return driver.ErrBadConn })
+		_ = l.conn.Close()
+		l.conn = nil
+	}
+
+	for name := range l.locks {
+		l.set(name, false)
+	}
+}
+
+func (l *PostgresLocker) set(name string, held bool) {
+	if held != l.locks[name] {
+		if held {
+			l.logger.Info("job lock acquired", slog.String("job", name))
+		} else {
+			l.logger.Warn("job lock lost", slog.String("job", name))
+		}
+	}
+	l.locks[name] = held
+
+	if l.held != nil {
+		value := 0.0
+		if held {
+			value = 1
+		}
+		l.held.WithLabelValues(name, l.replicaID).Set(value)
+	}
+}
+
+func lockKey(name string) int32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(name))
+	return int32(h.Sum32())
+}
@@ -0,0 +1,57 @@
+//go:build integration
+
+package lock_test
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"my-currency-service/currency/internal/config"
+	"my-currency-service/currency/internal/db"
+	"my-currency-service/currency/internal/lock"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newConnection(t *testing.T, cfg *config.AppConfig) *sql.DB {
+	conn, err := db.NewDatabaseConnection(cfg.Database)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+func TestPostgresLocker_Failover(t *testing.T) {
+	cfg := config.MustLoad()
+	ctx := context.Background()
+
+	held := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "test_lock_held"}, []string{"job", "replica"})
+
+	// Реплики с отдельными пулами, как разные процессы
+	first := lock.NewPostgresLocker(newConnection(t, cfg), "replica-a", held, slog.Default())
+	second := lock.NewPostgresLocker(newConnection(t, cfg), "replica-b", held, slog.Default())
+	t.Cleanup(first.Close)
+	t.Cleanup(second.Close)
+
+	const job = "integration-test:EUR"
+
+	assert.True(t, first.Acquire(ctx, job))
+	assert.False(t, second.Acquire(ctx, job))
+	// Лок держится между запусками
+	assert.True(t, first.Acquire(ctx, job))
+	assert.False(t, second.Acquire(ctx, job))
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(held.WithLabelValues(job, "replica-a")))
+	assert.Equal(t, 0.0, testutil.ToFloat64(held.WithLabelValues(job, "replica-b")))
+
+	// Держатель остановился: следующая реплика забирает задание
+	first.Close()
+	assert.True(t, second.Acquire(ctx, job))
+	assert.False(t, first.Acquire(ctx, job))
+
+	assert.Equal(t, 0.0, testutil.ToFloat64(held.WithLabelValues(job, "replica-a")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(held.WithLabelValues(job, "replica-b")))
+}
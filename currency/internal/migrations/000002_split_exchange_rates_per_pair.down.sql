@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS exchange_rates;
+
+ALTER TABLE exchange_rates_legacy RENAME TO exchange_rates;
+ALTER SEQUENCE exchange_rates_legacy_id_seq RENAME TO exchange_rates_id_seq;
+ALTER INDEX exchange_rates_legacy_pkey RENAME TO exchange_rates_pkey;
+ALTER INDEX exchange_rates_legacy_date_base_currency_key RENAME TO exchange_rates_date_base_currency_key;
+ALTER INDEX idx_exchange_rates_legacy_date_base_currency RENAME TO idx_exchange_rates_date_base_currency;
@@ -0,0 +1,36 @@
+-- Курсы хранятся построчно: одна строка на (дату наблюдения, базовую, целевую валюту).
+-- Старая таблица с JSONB переименовывается в exchange_rates_legacy. Записи, где ключом
+-- JSONB был код валюты, переносятся. В записях, где ключом была дата (формат старого
+-- FetchCurrentRates), целевая валюта не сохранялась: она бралась из конфига воркера
+-- (worker.currency_pair.target_currency). Их переносит мигратор с флагом
+-- --legacy-target, см. ConvertLegacyRates; до этого они остаются в exchange_rates_legacy.
+ALTER TABLE exchange_rates RENAME TO exchange_rates_legacy;
+ALTER SEQUENCE exchange_rates_id_seq RENAME TO exchange_rates_legacy_id_seq;
+ALTER INDEX exchange_rates_pkey RENAME TO exchange_rates_legacy_pkey;
+ALTER INDEX exchange_rates_date_base_currency_key RENAME TO exchange_rates_legacy_date_base_currency_key;
+ALTER INDEX idx_exchange_rates_date_base_currency RENAME TO idx_exchange_rates_legacy_date_base_currency;
+
+CREATE TABLE exchange_rates (
+                                id BIGSERIAL PRIMARY KEY,
+                                date DATE NOT NULL,
+                                base_currency VARCHAR(10) NOT NULL,
+                                target_currency VARCHAR(10) NOT NULL,
+                                rate NUMERIC NOT NULL,
+                                created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+                                updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+                                UNIQUE (base_currency, target_currency, date)
+);
+
+INSERT INTO exchange_rates (date, base_currency, target_currency, rate, created_at, updated_at)
+SELECT DISTINCT ON (UPPER(l.base_currency), UPPER(r.key), l.date::date)
+       l.date::date,
+       UPPER(l.base_currency),
+       UPPER(r.key),
+       (r.value #>> '{}')::numeric,
+       COALESCE(l.created_at, NOW()),
+       COALESCE(l.created_at, NOW())
+FROM exchange_rates_legacy l
+         CROSS JOIN LATERAL jsonb_each(l.currency_rates) r
+WHERE r.key ~ '^[A-Za-z]{3}$'
+  AND jsonb_typeof(r.value) = 'number'
+ORDER BY UPPER(l.base_currency), UPPER(r.key), l.date::date, l.created_at DESC NULLS LAST;
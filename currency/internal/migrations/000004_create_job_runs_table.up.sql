@@ -0,0 +1,18 @@
+-- История запусков заданий cron: одна строка на пару в каждом запуске.
+CREATE TABLE job_runs (
+                          id BIGSERIAL PRIMARY KEY,
+                          job VARCHAR(100) NOT NULL,
+                          trigger VARCHAR(20) NOT NULL,
+                          provider VARCHAR(100) NOT NULL,
+                          base_currency VARCHAR(10) NOT NULL,
+                          target_currency VARCHAR(10) NOT NULL,
+                          started_at TIMESTAMPTZ NOT NULL,
+                          finished_at TIMESTAMPTZ NOT NULL,
+                          status VARCHAR(10) NOT NULL,
+                          error TEXT NOT NULL DEFAULT '',
+                          rows_written INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX idx_job_runs_job_pair_started_at ON job_runs (job, base_currency, target_currency, started_at DESC);
+-- Для удаления истории старше срока хранения
+CREATE INDEX idx_job_runs_started_at ON job_runs (started_at);
@@ -0,0 +1,5 @@
+ALTER TABLE exchange_rates
+    DROP COLUMN IF EXISTS payload_hash,
+    DROP COLUMN IF EXISTS fetched_at,
+    DROP COLUMN IF EXISTS series_key,
+    DROP COLUMN IF EXISTS provider;
@@ -0,0 +1,10 @@
+-- Происхождение каждого курса: провайдер, ключ ряда у источника, время загрузки
+-- и SHA-256 ответа, из которого взят курс. У строк, загруженных раньше,
+-- провайдер и ключ неизвестны и остаются пустыми, временем загрузки считается updated_at.
+ALTER TABLE exchange_rates
+    ADD COLUMN provider VARCHAR(100) NOT NULL DEFAULT '',
+    ADD COLUMN series_key VARCHAR(100) NOT NULL DEFAULT '',
+    ADD COLUMN fetched_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ADD COLUMN payload_hash VARCHAR(64) NOT NULL DEFAULT '';
+
+UPDATE exchange_rates SET fetched_at = updated_at;
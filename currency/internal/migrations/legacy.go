@@ -0,0 +1,77 @@
+package migrator
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// Ключ JSONB в записях старого FetchCurrentRates — дата наблюдения
+const legacyDateKey = `'^[0-9]{4}-[0-9]{2}-[0-9]{2}$'`
+
+// CountLegacyRates returns the number of date keyed observations in
+// exchange_rates_legacy that have no rate quoted in their base currency on
+// that date in exchange_rates yet.
+func CountLegacyRates(ctx context.Context, db *sql.DB) (int64, error) {
+	var count int64
+	err := db.QueryRowContext(ctx, `
+		SELECT COUNT(*)
+		FROM exchange_rates_legacy l
+		         CROSS JOIN LATERAL jsonb_each(l.currency_rates) r
+		WHERE r.key ~ `+legacyDateKey+`
+		  AND jsonb_typeof(r.value) = 'number'
+		  AND NOT EXISTS (SELECT 1
+		                  FROM exchange_rates e
+		                  WHERE e.target_currency = UPPER(l.base_currency)
+		                    AND e.date = r.key::date)`,
+	).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count legacy rates: %w", err)
+	}
+
+	return count, nil
+}
+
+// ConvertLegacyRates moves the date keyed observations of
+// exchange_rates_legacy into exchange_rates. The first worker stored them
+// without the target currency, so target must be the one it was configured
+// with (worker.currency_pair.target_currency). It requested the ECB series
+// D.<base>.<target>, which quotes the base currency per 1 unit of the
+// target, so target becomes the base of the converted rates and the legacy
+// base_currency their target. Rates already present are kept, so the
+// conversion can be repeated.
+func ConvertLegacyRates(ctx context.Context, db *sql.DB, target string) (int64, error) {
+	target = strings.ToUpper(target)
+
+	res, err := db.ExecContext(ctx, `
+		INSERT INTO exchange_rates (date, base_currency, target_currency, rate, created_at, updated_at, fetched_at)
+		SELECT DISTINCT ON (UPPER(l.base_currency), r.key::date)
+		       r.key::date,
+		       $1,
+		       UPPER(l.base_currency),
+		       (r.value #>> '{}')::numeric,
+		       COALESCE(l.created_at, NOW()),
+		       COALESCE(l.created_at, NOW()),
+		       COALESCE(l.created_at, NOW())
+		FROM exchange_rates_legacy l
+		         CROSS JOIN LATERAL jsonb_each(l.currency_rates) r
+		WHERE r.key ~ `+legacyDateKey+`
+		  AND jsonb_typeof(r.value) = 'number'
+		  AND UPPER(l.base_currency) <> $1
+		-- Старый воркер загружал вчера и сегодня, наблюдение встречается в двух записях
+		ORDER BY UPPER(l.base_currency), r.key::date, l.created_at DESC NULLS LAST
+		ON CONFLICT (base_currency, target_currency, date) DO NOTHING`,
+		target,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to convert legacy rates: %w", err)
+	}
+
+	converted, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to convert legacy rates: %w", err)
+	}
+
+	return converted, nil
+}
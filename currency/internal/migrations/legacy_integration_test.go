@@ -0,0 +1,83 @@
+//go:build integration
+
+package migrator_test
+
+import (
+	"context"
+	"my-currency-service/currency/internal/config"
+	"my-currency-service/currency/internal/db"
+	migrator "my-currency-service/currency/internal/migrations"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertLegacyRates(t *testing.T) {
+	cfg := config.MustLoad()
+
+	// Мигратор закрывает соединение, поэтому для него открывается отдельное
+	migrationConn, err := db.NewDatabaseConnection(cfg.Database)
+	require.NoError(t, err)
+	require.NoError(t, migrator.MustGetNewMigrator(migrator.MigrationsFS, ".").ApplyMigrations(migrationConn))
+
+	conn, err := db.NewDatabaseConnection(cfg.Database)
+	require.NoError(t, err)
+
+	cleanup := func() {
+		_, err := conn.Exec(`DELETE FROM exchange_rates_legacy WHERE date BETWEEN '1999-01-04' AND '1999-01-06'`)
+		require.NoError(t, err)
+		_, err = conn.Exec(`
+			DELETE FROM exchange_rates
+			WHERE base_currency = 'EUR' AND target_currency = 'USD' AND date BETWEEN '1999-01-04' AND '1999-01-06'`)
+		require.NoError(t, err)
+	}
+	cleanup()
+	t.Cleanup(func() {
+		cleanup()
+		_ = conn.Close()
+	})
+
+	// Так писал старый FetchCurrentRates с base_currency USD и target_currency EUR:
+	// ряд D.USD.EUR, вчера и сегодня в каждой записи. Первые фиксинги ЕЦБ:
+	// 1 EUR = 1.1789 USD 4 января 1999, 1.1790 — 5 января, 1.1743 — 6 января.
+	// Запись за 5 января ошибочна, её исправляет запись за 6 января
+	_, err = conn.Exec(`
+		INSERT INTO exchange_rates_legacy (date, base_currency, currency_rates, created_at) VALUES
+		('1999-01-05', 'USD', '{"1999-01-04": 1.1789, "1999-01-05": 1.18}', '1999-01-05 16:00:00+00'),
+		('1999-01-06', 'USD', '{"1999-01-05": 1.1790, "1999-01-06": 1.1743}', '1999-01-06 16:00:00+00')`)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	converted, err := migrator.ConvertLegacyRates(ctx, conn, "eur")
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, converted)
+
+	rows, err := conn.Query(`
+		SELECT date, base_currency, target_currency, rate::text FROM exchange_rates
+		WHERE date BETWEEN '1999-01-04' AND '1999-01-06' ORDER BY date`)
+	require.NoError(t, err)
+	defer rows.Close()
+
+	var got []string
+	for rows.Next() {
+		var (
+			date               time.Time
+			base, target, rate string
+		)
+		require.NoError(t, rows.Scan(&date, &base, &target, &rate))
+		got = append(got, date.Format(time.DateOnly)+" "+base+"/"+target+" "+rate)
+	}
+	require.NoError(t, rows.Err())
+	// За 5 января берётся более поздняя запись
+	assert.Equal(t, []string{
+		"1999-01-04 EUR/USD 1.1789",
+		"1999-01-05 EUR/USD 1.1790",
+		"1999-01-06 EUR/USD 1.1743",
+	}, got)
+
+	converted, err = migrator.ConvertLegacyRates(ctx, conn, "EUR")
+	require.NoError(t, err)
+	assert.Zero(t, converted)
+}
@@ -0,0 +1,140 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"my-currency-service/currency/internal/auth"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// Заголовок ответа с задержкой до повтора в секундах, как Retry-After в HTTP
+const retryAfterHeader = "retry-after"
+
+// Запросы HTTP-шлюза приходят по соединению в памяти (bufconn), адрес
+// клиента шлюз передаёт в метаданных. Другим пирам этот заголовок не
+// доверяется, иначе анонимный клиент мог бы обойти лимит.
+const (
+	inProcessNetwork   = "bufconn"
+	forwardedForHeader = "x-forwarded-for"
+)
+
+// Пробы балансировщика не ограничиваются
+var exemptServices = []string{"/grpc.health.v1.Health/"}
+
+// Interceptor rejects requests over the limits with ResourceExhausted.
+// It must run after authentication to key limits by the caller identity.
+type Interceptor struct {
+	limiter    *Limiter
+	rejections *prometheus.CounterVec
+}
+
+// NewInterceptor creates the interceptor; rejections must have the labels
+// "method" and "reason".
+func NewInterceptor(limiter *Limiter, rejections *prometheus.CounterVec) *Interceptor {
+	return &Interceptor{
+		limiter:    limiter,
+		rejections: rejections,
+	}
+}
+
+func (i *Interceptor) Unary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := i.check(ctx, info.FullMethod, func(md metadata.MD) error {
+			return grpc.SetHeader(ctx, md)
+		}); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func (i *Interceptor) Stream() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := i.check(ss.Context(), info.FullMethod, ss.SetHeader); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+func (i *Interceptor) check(ctx context.Context, fullMethod string, setHeader func(metadata.MD) error) error {
+	for _, prefix := range exemptServices {
+		if strings.HasPrefix(fullMethod, prefix) {
+			return nil
+		}
+	}
+
+	method := fullMethod[strings.LastIndex(fullMethod, "/")+1:]
+	decision := i.limiter.Allow(clientKey(ctx), method)
+	if decision.Allowed {
+		return nil
+	}
+
+	i.rejections.WithLabelValues(method, decision.Reason).Inc()
+
+	retryAfter := int64(math.Ceil(decision.RetryAfter.Seconds()))
+	_ = setHeader(metadata.Pairs(retryAfterHeader, strconv.FormatInt(retryAfter, 10)))
+
+	return rejection(method, decision)
+}
+
+// rejection builds a ResourceExhausted status with RetryInfo and
+// QuotaFailure details.
+func rejection(method string, decision Decision) error {
+	var description string
+	switch decision.Reason {
+	case ReasonQuota:
+		description = fmt.Sprintf("daily quota of %d requests to %s is exhausted", int(decision.Limit), method)
+	default:
+		description = fmt.Sprintf("rate limit of %g requests per second to %s is exceeded", decision.Limit, method)
+	}
+
+	st := status.New(codes.ResourceExhausted, description)
+	detailed, err := st.WithDetails(
+		&errdetails.RetryInfo{RetryDelay: durationpb.New(decision.RetryAfter)},
+		&errdetails.QuotaFailure{Violations: []*errdetails.QuotaFailure_Violation{{
+			Subject:     method,
+			Description: description,
+		}}},
+	)
+	if err != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
+
+// clientKey identifies the caller: the authenticated subject or, for
+// anonymous calls, the peer host or the HTTP client of the gateway.
+func clientKey(ctx context.Context) string {
+	if identity, ok := auth.FromContext(ctx); ok {
+		return "subject:" + identity.Subject
+	}
+
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		if p.Addr.Network() == inProcessNetwork {
+			if forwarded := metadata.ValueFromIncomingContext(ctx, forwardedForHeader); len(forwarded) > 0 {
+				return "peer:" + forwarded[0]
+			}
+		}
+
+		addr := p.Addr.String()
+		if host, _, err := net.SplitHostPort(addr); err == nil {
+			addr = host
+		}
+		return "peer:" + addr
+	}
+
+	return "anonymous"
+}
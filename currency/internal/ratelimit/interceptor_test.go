@@ -0,0 +1,107 @@
+package ratelimit
+
+import (
+	"context"
+	"my-currency-service/currency/internal/auth"
+	"my-currency-service/currency/internal/config"
+	"net"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+type testServerStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	header metadata.MD
+}
+
+func (s *testServerStream) Context() context.Context { return s.ctx }
+
+func (s *testServerStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func newTestInterceptor(limit config.LimitConfig) (*Interceptor, *prometheus.CounterVec) {
+	rejections := prometheus.NewCounterVec(
+		prometheus.CounterOpts{Name: "test_rate_limit_rejections", Help: "test"},
+		[]string{"method", "reason"},
+	)
+	limiter, _ := newTestLimiter(config.RateLimitConfig{Default: limit})
+	return NewInterceptor(limiter, rejections), rejections
+}
+
+func TestInterceptor_Stream(t *testing.T) {
+	interceptor, rejections := newTestInterceptor(config.LimitConfig{DailyQuota: 1})
+
+	ctx := auth.NewContext(context.Background(), auth.Identity{Subject: "reporting"})
+	info := &grpc.StreamServerInfo{FullMethod: "/currency.CurrencyService/SubscribeRates"}
+	handler := func(any, grpc.ServerStream) error { return nil }
+
+	require.NoError(t, interceptor.Stream()(nil, &testServerStream{ctx: ctx}, info, handler))
+
+	stream := &testServerStream{ctx: ctx}
+	err := interceptor.Stream()(nil, stream, info, handler)
+
+	st := status.Convert(err)
+	require.Equal(t, codes.ResourceExhausted, st.Code())
+	assert.Equal(t, []string{"43200"}, stream.header.Get("retry-after"))
+	assert.Equal(t, 1.0, testutil.ToFloat64(rejections.WithLabelValues("SubscribeRates", ReasonQuota)))
+
+	var retryInfo *errdetails.RetryInfo
+	for _, detail := range st.Details() {
+		if d, ok := detail.(*errdetails.RetryInfo); ok {
+			retryInfo = d
+		}
+	}
+	require.NotNil(t, retryInfo)
+	assert.Positive(t, retryInfo.GetRetryDelay().AsDuration())
+}
+
+func TestInterceptor_Unary(t *testing.T) {
+	interceptor, rejections := newTestInterceptor(config.LimitConfig{RatePerSecond: 1, Burst: 1})
+
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 40000}})
+	info := &grpc.UnaryServerInfo{FullMethod: "/currency.CurrencyService/GetRate"}
+	handler := func(context.Context, any) (any, error) { return "ok", nil }
+
+	_, err := interceptor.Unary()(ctx, nil, info, handler)
+	require.NoError(t, err)
+
+	// Тот же хост с другого порта — тот же клиент
+	ctx = peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 40001}})
+	_, err = interceptor.Unary()(ctx, nil, info, handler)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.Equal(t, 1.0, testutil.ToFloat64(rejections.WithLabelValues("GetRate", ReasonRate)))
+
+	// Health не ограничивается
+	_, err = interceptor.Unary()(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/grpc.health.v1.Health/Check"}, handler)
+	assert.NoError(t, err)
+}
+
+type testAddr string
+
+func (a testAddr) Network() string { return string(a) }
+func (a testAddr) String() string  { return string(a) }
+
+func TestClientKey_Forwarded(t *testing.T) {
+	forwarded := metadata.Pairs(forwardedForHeader, "203.0.113.7")
+
+	// Клиент HTTP-шлюза определяется по переданному адресу
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: testAddr(inProcessNetwork)})
+	assert.Equal(t, "peer:203.0.113.7", clientKey(metadata.NewIncomingContext(ctx, forwarded)))
+
+	// Внешнему пиру заголовок не доверяется
+	ctx = peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 40000}})
+	assert.Equal(t, "peer:10.0.0.1", clientKey(metadata.NewIncomingContext(ctx, forwarded)))
+}
@@ -0,0 +1,143 @@
+// Package ratelimit limits the request rate and daily quota of each client
+// per gRPC method.
+package ratelimit
+
+import (
+	"math"
+	"my-currency-service/currency/internal/config"
+	"sync"
+	"time"
+)
+
+// Причины отказа, они же значения метки reason в метриках
+const (
+	ReasonRate  = "rate"
+	ReasonQuota = "quota"
+)
+
+// Как часто удалять состояния клиентов, которые давно не обращались
+const sweepInterval = 10 * time.Minute
+
+// Decision is the outcome of a limiter check.
+type Decision struct {
+	Allowed bool
+	// Reason and RetryAfter are set when the request is rejected.
+	Reason     string
+	RetryAfter time.Duration
+	// Limit is the rejected limit: requests per second or per day.
+	Limit float64
+}
+
+type bucketKey struct {
+	client, method string
+}
+
+// bucket is the state of one client on one method.
+type bucket struct {
+	tokens   float64
+	updated  time.Time
+	quotaDay time.Time
+	used     int
+}
+
+// Limiter keeps a token bucket and a daily quota per client and method.
+type Limiter struct {
+	defaults config.LimitConfig
+	methods  map[string]config.LimitConfig
+
+	mu        sync.Mutex
+	buckets   map[bucketKey]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+func NewLimiter(cfg config.RateLimitConfig) *Limiter {
+	return &Limiter{
+		defaults: cfg.Default,
+		methods:  cfg.Methods,
+		buckets:  make(map[bucketKey]*bucket),
+		now:      time.Now,
+	}
+}
+
+func (l *Limiter) limit(method string) config.LimitConfig {
+	if limit, ok := l.methods[method]; ok {
+		return limit
+	}
+	return l.defaults
+}
+
+// Allow takes a token and one unit of the daily quota of client on method.
+// A rejected request consumes neither.
+func (l *Limiter) Allow(client, method string) Decision {
+	limit := l.limit(method)
+	if limit.RatePerSecond <= 0 && limit.DailyQuota <= 0 {
+		return Decision{Allowed: true}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	key := bucketKey{client: client, method: method}
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: burst(limit), updated: now}
+		l.buckets[key] = b
+	}
+
+	day := now.UTC().Truncate(24 * time.Hour)
+	if !b.quotaDay.Equal(day) {
+		b.quotaDay, b.used = day, 0
+	}
+	if limit.DailyQuota > 0 && b.used >= limit.DailyQuota {
+		return Decision{
+			Reason:     ReasonQuota,
+			RetryAfter: day.Add(24 * time.Hour).Sub(now),
+			Limit:      float64(limit.DailyQuota),
+		}
+	}
+
+	if limit.RatePerSecond > 0 {
+		b.tokens = math.Min(burst(limit), b.tokens+now.Sub(b.updated).Seconds()*limit.RatePerSecond)
+		b.updated = now
+
+		if b.tokens < 1 {
+			wait := (1 - b.tokens) / limit.RatePerSecond
+			return Decision{
+				Reason:     ReasonRate,
+				RetryAfter: time.Duration(math.Ceil(wait * float64(time.Second))),
+				Limit:      limit.RatePerSecond,
+			}
+		}
+		b.tokens--
+	}
+
+	b.used++
+	return Decision{Allowed: true}
+}
+
+// sweep drops clients idle for a day: their bucket is full and their
+// quota has been reset since.
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < sweepInterval {
+		return
+	}
+	l.lastSweep = now
+
+	for key, b := range l.buckets {
+		if now.Sub(b.updated) > 24*time.Hour && now.Sub(b.quotaDay) > 24*time.Hour {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// burst defaults to one second worth of tokens, at least one.
+func burst(limit config.LimitConfig) float64 {
+	if limit.Burst > 0 {
+		return float64(limit.Burst)
+	}
+	return math.Max(1, math.Ceil(limit.RatePerSecond))
+}
@@ -0,0 +1,105 @@
+package ratelimit
+
+import (
+	"my-currency-service/currency/internal/config"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testClock struct {
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time { return c.now }
+
+func newTestLimiter(cfg config.RateLimitConfig) (*Limiter, *testClock) {
+	clock := &testClock{now: time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)}
+	limiter := NewLimiter(cfg)
+	limiter.now = clock.Now
+	return limiter, clock
+}
+
+func TestLimiter_TokenBucket(t *testing.T) {
+	limiter, clock := newTestLimiter(config.RateLimitConfig{
+		Default: config.LimitConfig{RatePerSecond: 2, Burst: 3},
+	})
+
+	for range 3 {
+		assert.True(t, limiter.Allow("a", "GetRate").Allowed)
+	}
+
+	decision := limiter.Allow("a", "GetRate")
+	require.False(t, decision.Allowed)
+	assert.Equal(t, ReasonRate, decision.Reason)
+	assert.Equal(t, 500*time.Millisecond, decision.RetryAfter)
+
+	// Другой клиент и другой метод не затронуты
+	assert.True(t, limiter.Allow("b", "GetRate").Allowed)
+	assert.True(t, limiter.Allow("a", "Convert").Allowed)
+
+	clock.now = clock.now.Add(500 * time.Millisecond)
+	assert.True(t, limiter.Allow("a", "GetRate").Allowed)
+	assert.False(t, limiter.Allow("a", "GetRate").Allowed)
+
+	// Бакет не наполняется выше burst
+	clock.now = clock.now.Add(time.Hour)
+	for range 3 {
+		assert.True(t, limiter.Allow("a", "GetRate").Allowed)
+	}
+	assert.False(t, limiter.Allow("a", "GetRate").Allowed)
+}
+
+func TestLimiter_DailyQuota(t *testing.T) {
+	limiter, clock := newTestLimiter(config.RateLimitConfig{
+		Methods: map[string]config.LimitConfig{
+			"BatchGetRates": {DailyQuota: 2},
+		},
+	})
+
+	assert.True(t, limiter.Allow("a", "BatchGetRates").Allowed)
+	assert.True(t, limiter.Allow("a", "BatchGetRates").Allowed)
+
+	decision := limiter.Allow("a", "BatchGetRates")
+	require.False(t, decision.Allowed)
+	assert.Equal(t, ReasonQuota, decision.Reason)
+	// До полуночи UTC
+	assert.Equal(t, 12*time.Hour, decision.RetryAfter)
+
+	// Методы без лимитов не ограничены
+	for range 10 {
+		assert.True(t, limiter.Allow("a", "GetRate").Allowed)
+	}
+
+	clock.now = clock.now.Add(12 * time.Hour)
+	assert.True(t, limiter.Allow("a", "BatchGetRates").Allowed)
+}
+
+func TestLimiter_RejectedRequestsDoNotUseQuota(t *testing.T) {
+	limiter, clock := newTestLimiter(config.RateLimitConfig{
+		Default: config.LimitConfig{RatePerSecond: 1, Burst: 1, DailyQuota: 2},
+	})
+
+	assert.True(t, limiter.Allow("a", "GetRate").Allowed)
+	assert.Equal(t, ReasonRate, limiter.Allow("a", "GetRate").Reason)
+
+	clock.now = clock.now.Add(time.Second)
+	assert.True(t, limiter.Allow("a", "GetRate").Allowed)
+
+	clock.now = clock.now.Add(time.Second)
+	assert.Equal(t, ReasonQuota, limiter.Allow("a", "GetRate").Reason)
+}
+
+func TestLimiter_SweepsIdleClients(t *testing.T) {
+	limiter, clock := newTestLimiter(config.RateLimitConfig{
+		Default: config.LimitConfig{RatePerSecond: 1},
+	})
+
+	limiter.Allow("a", "GetRate")
+	clock.now = clock.now.Add(25 * time.Hour)
+	limiter.Allow("b", "GetRate")
+
+	assert.Len(t, limiter.buckets, 1)
+}
@@ -7,8 +7,25 @@ import (
 )
 
 type ExchangeRateRepository interface {
-	Save(ctx context.Context, date time.Time, baseCurrency string, rates map[string]float64) error
+	// Save upserts observations, one row per (date, base, target).
+	Save(ctx context.Context, rates []dto.RateRecordDTO) error
 	FindInInterval(ctx context.Context, dto *dto.CurrencyRequestDTO) ([]CurrencyRate, error)
+	// FindLatest returns the newest observation of the pair or ErrNotFound.
+	FindLatest(ctx context.Context, baseCurrency, targetCurrency string) (*CurrencyRate, error)
+	// FindBatch answers all queries in one round trip. For each query the
+	// latest observation on or before its date, at most lookbackDays old, is
+	// returned under the index of the query; unanswered queries are absent.
+	FindBatch(ctx context.Context, queries []dto.RateQueryDTO, lookbackDays int) (map[int]CurrencyRate, error)
+}
+
+// JobRunRepository stores the run history of the worker jobs.
+type JobRunRepository interface {
+	SaveJobRun(ctx context.Context, run dto.JobRunDTO) error
+	// FindJobRuns returns the runs matching query, newest first.
+	FindJobRuns(ctx context.Context, query *dto.JobRunQueryDTO) ([]dto.JobRunDTO, error)
+	// DeleteJobRunsBefore removes runs started before before and returns
+	// their number.
+	DeleteJobRunsBefore(ctx context.Context, before time.Time) (int64, error)
 }
 
 type Currency struct {
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+
+	"github.com/lib/pq"
+)
+
+var (
+	ErrNotFound = errors.New("not found")
+	// ErrUnavailable marks errors of a lost or refused database connection.
+	// The request may succeed if retried later.
+	ErrUnavailable = errors.New("database unavailable")
+)
+
+// classify marks connection failures with ErrUnavailable and reports
+// statements cancelled by ctx as ctx.Err(), so callers can tell them
+// apart from query errors.
+func classify(ctx context.Context, err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch {
+		// query_canceled: lib/pq отменяет запрос на сервере при отмене ctx
+		case pqErr.Code == "57014" && ctx.Err() != nil:
+			return fmt.Errorf("%w: %w", ctx.Err(), err)
+		// connection_exception, insufficient_resources, operator_intervention
+		case pqErr.Code.Class() == "08", pqErr.Code.Class() == "53",
+			pqErr.Code == "57P01", pqErr.Code == "57P02", pqErr.Code == "57P03":
+			return fmt.Errorf("%w: %w", ErrUnavailable, err)
+		}
+		return err
+	}
+
+	var netErr net.Error
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone) ||
+		errors.Is(err, io.ErrUnexpectedEOF) || errors.As(err, &netErr) {
+		return fmt.Errorf("%w: %w", ErrUnavailable, err)
+	}
+
+	return err
+}
@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassify(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		err    error
+		target error
+	}{
+		{"bad connection", context.Background(), driver.ErrBadConn, ErrUnavailable},
+		{"connection refused", context.Background(), &net.OpError{Op: "dial", Err: errors.New("connection refused")}, ErrUnavailable},
+		{"connection failure", context.Background(), &pq.Error{Code: "08006"}, ErrUnavailable},
+		{"too many connections", context.Background(), &pq.Error{Code: "53300"}, ErrUnavailable},
+		{"shutting down", context.Background(), &pq.Error{Code: "57P01"}, ErrUnavailable},
+		{"canceled statement", canceled, &pq.Error{Code: "57014"}, context.Canceled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, classify(tt.ctx, tt.err), tt.target)
+		})
+	}
+}
+
+func TestClassify_QueryError(t *testing.T) {
+	err := classify(context.Background(), &pq.Error{Code: "42P01"})
+
+	assert.NotErrorIs(t, err, ErrUnavailable)
+	assert.NotErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"my-currency-service/currency/internal/dto"
+	"time"
+)
+
+func (repo *PostgresRepository) SaveJobRun(ctx context.Context, run dto.JobRunDTO) error {
+	_, err := repo.DB.ExecContext(
+		ctx,
+		`INSERT INTO job_runs (job, trigger, provider, base_currency, target_currency,
+				started_at, finished_at, status, error, rows_written)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+		run.Job, run.Trigger, run.Provider, run.BaseCurrency, run.TargetCurrency,
+		run.StartedAt, run.FinishedAt, run.Status, run.Error, run.RowsWritten,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save job run: %w", classify(ctx, err))
+	}
+	return nil
+}
+
+func (repo *PostgresRepository) FindJobRuns(ctx context.Context, query *dto.JobRunQueryDTO) ([]dto.JobRunDTO, error) {
+	// Пустой фильтр совпадает с любым значением
+	rows, err := repo.DB.QueryContext(
+		ctx,
+		`SELECT id, job, trigger, provider, base_currency, target_currency,
+				started_at, finished_at, status, error, rows_written
+		FROM job_runs
+		WHERE ($1::text = '' OR job = $1)
+		  AND ($2::text = '' OR base_currency = $2)
+		  AND ($3::text = '' OR target_currency = $3)
+		  AND ($4::text = '' OR status = $4)
+		ORDER BY started_at DESC, id DESC
+		LIMIT $5`,
+		query.Job, query.BaseCurrency, query.TargetCurrency, query.Status, query.Limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query job runs: %w", classify(ctx, err))
+	}
+	defer func() { _ = rows.Close() }()
+
+	var runs []dto.JobRunDTO
+	for rows.Next() {
+		var run dto.JobRunDTO
+		if err := rows.Scan(&run.ID, &run.Job, &run.Trigger, &run.Provider, &run.BaseCurrency, &run.TargetCurrency,
+			&run.StartedAt, &run.FinishedAt, &run.Status, &run.Error, &run.RowsWritten); err != nil {
+			return nil, fmt.Errorf("failed to scan job run: %w", err)
+		}
+		runs = append(runs, run)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", classify(ctx, err))
+	}
+
+	return runs, nil
+}
+
+func (repo *PostgresRepository) DeleteJobRunsBefore(ctx context.Context, before time.Time) (int64, error) {
+	result, err := repo.DB.ExecContext(ctx, `DELETE FROM job_runs WHERE started_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete job runs: %w", classify(ctx, err))
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count deleted job runs: %w", err)
+	}
+	return deleted, nil
+}
@@ -3,12 +3,13 @@ package repository
 import (
 	"context"
 	"database/sql"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"my-currency-service/currency/internal/dto"
 	"time"
 
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
+	"github.com/shopspring/decimal"
 )
 
 // PostgresRepository implements ExchangeRateRepository for PostgreSQL.
@@ -22,32 +23,98 @@ func NewPostgresRepository(db *sql.DB) *PostgresRepository {
 
 type CurrencyRate struct {
 	Date time.Time
-	Rate float32
+	Rate decimal.Decimal
+	// FetchedAt is when the stored value was fetched from Provider.
+	FetchedAt time.Time
+	Provider  string
+
+	// Derived is set for rates computed from stored observations (inverse or
+	// cross rates). Legs lists them in path order from base to target.
+	Derived bool
+	Legs    []RateLeg
+}
+
+// RateLeg is a stored observation used to derive a rate. Inverted legs
+// contribute 1/Rate.
+type RateLeg struct {
+	BaseCurrency   string
+	TargetCurrency string
+	Rate           decimal.Decimal
+	Inverted       bool
+	FetchedAt      time.Time
+	Provider       string
 }
 
 func (repo *PostgresRepository) Save(
 	ctx context.Context,
-	date time.Time,
-	baseCurrency string,
-	rates map[string]float64,
+	rates []dto.RateRecordDTO,
 ) error {
-	ratesJSON, err := json.Marshal(rates)
-	if err != nil {
-		return fmt.Errorf("failed to marshal currency rates: %w", err)
+	if len(rates) == 0 {
+		return nil
 	}
 
-	_, err = repo.DB.ExecContext(
+	// ON CONFLICT DO UPDATE cannot touch the same row twice in one statement,
+	// so duplicates are collapsed here, the last one wins.
+	type key struct {
+		date, base, target string
+	}
+	index := make(map[key]int, len(rates))
+	unique := make([]dto.RateRecordDTO, 0, len(rates))
+	for _, rate := range rates {
+		k := key{rate.Date.Format("2006-01-02"), rate.BaseCurrency, rate.TargetCurrency}
+		if i, ok := index[k]; ok {
+			unique[i] = rate
+			continue
+		}
+		index[k] = len(unique)
+		unique = append(unique, rate)
+	}
+
+	dates := make([]string, len(unique))
+	bases := make([]string, len(unique))
+	targets := make([]string, len(unique))
+	values := make([]string, len(unique))
+	providers := make([]string, len(unique))
+	seriesKeys := make([]string, len(unique))
+	fetchedAt := make([]string, len(unique))
+	hashes := make([]string, len(unique))
+	for i, rate := range unique {
+		dates[i] = rate.Date.Format("2006-01-02")
+		bases[i] = rate.BaseCurrency
+		targets[i] = rate.TargetCurrency
+		values[i] = rate.Value.String()
+		providers[i] = rate.Provider
+		seriesKeys[i] = rate.SeriesKey
+		if !rate.FetchedAt.IsZero() {
+			fetchedAt[i] = rate.FetchedAt.Format(time.RFC3339Nano)
+		}
+		hashes[i] = rate.PayloadHash
+	}
+
+	// Пустое время загрузки заменяется временем записи
+	_, err := repo.DB.ExecContext(
 		ctx,
-		`INSERT INTO exchange_rates (date, base_currency, currency_rates)
-				VALUES ($1, $2, $3)
-				ON CONFLICT (date, base_currency)
-				DO UPDATE SET 
-				currency_rates = exchange_rates.currency_rates || EXCLUDED.currency_rates`,
-		date, baseCurrency, ratesJSON,
+		`INSERT INTO exchange_rates (date, base_currency, target_currency, rate,
+				provider, series_key, fetched_at, payload_hash)
+				SELECT r.date, r.base_currency, r.target_currency, r.rate,
+					r.provider, r.series_key, COALESCE(NULLIF(r.fetched_at, '')::timestamptz, NOW()), r.payload_hash
+				FROM unnest($1::date[], $2::varchar[], $3::varchar[], $4::numeric[],
+					$5::varchar[], $6::varchar[], $7::text[], $8::varchar[])
+					AS r(date, base_currency, target_currency, rate, provider, series_key, fetched_at, payload_hash)
+				ON CONFLICT (base_currency, target_currency, date)
+				DO UPDATE SET
+				rate = EXCLUDED.rate,
+				provider = EXCLUDED.provider,
+				series_key = EXCLUDED.series_key,
+				fetched_at = EXCLUDED.fetched_at,
+				payload_hash = EXCLUDED.payload_hash,
+				updated_at = NOW()`,
+		pq.Array(dates), pq.Array(bases), pq.Array(targets), pq.Array(values),
+		pq.Array(providers), pq.Array(seriesKeys), pq.Array(fetchedAt), pq.Array(hashes),
 	)
 
 	if err != nil {
-		return fmt.Errorf("failed to save exchange rates: %w", err)
+		return fmt.Errorf("failed to save exchange rates: %w", classify(ctx, err))
 	}
 	return nil
 }
@@ -57,22 +124,23 @@ func (repo *PostgresRepository) FindInInterval(
 	dto *dto.CurrencyRequestDTO,
 ) ([]CurrencyRate, error) {
 	query := `
-		SELECT date, (currency_rates ->> $1)::float 
+		SELECT date, rate, fetched_at, provider
 		FROM exchange_rates
-		WHERE date::date BETWEEN $2 AND $3 AND base_currency = $4
+		WHERE base_currency = $1 AND target_currency = $2 AND date BETWEEN $3 AND $4
+		ORDER BY date
 	`
 
 	rows, err := repo.DB.QueryContext(
 		ctx,
 		query,
+		dto.BaseCurrency,
 		dto.TargetCurrency,
 		dto.DateFrom.Format("2006-01-02"),
 		dto.DateTo.Format("2006-01-02"),
-		dto.BaseCurrency,
 	)
 
 	if err != nil {
-		return nil, fmt.Errorf("failed to query exchange rates: %w", err)
+		return nil, fmt.Errorf("failed to query exchange rates: %w", classify(ctx, err))
 	}
 	defer func(rows *sql.Rows) {
 		err := rows.Close()
@@ -84,7 +152,7 @@ func (repo *PostgresRepository) FindInInterval(
 	var rates []CurrencyRate
 	for rows.Next() {
 		var rate CurrencyRate
-		if err := rows.Scan(&rate.Date, &rate.Rate); err != nil {
+		if err := rows.Scan(&rate.Date, &rate.Rate, &rate.FetchedAt, &rate.Provider); err != nil {
 			return nil, fmt.Errorf("failed to scan row: %w", err)
 		}
 
@@ -92,7 +160,97 @@ func (repo *PostgresRepository) FindInInterval(
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("error during rows iteration: %w", err)
+		return nil, fmt.Errorf("error during rows iteration: %w", classify(ctx, err))
+	}
+
+	return rates, nil
+}
+
+func (repo *PostgresRepository) FindLatest(
+	ctx context.Context,
+	baseCurrency string,
+	targetCurrency string,
+) (*CurrencyRate, error) {
+	// Обратный проход по уникальному индексу (base_currency, target_currency, date)
+	query := `
+		SELECT date, rate, fetched_at, provider
+		FROM exchange_rates
+		WHERE base_currency = $1 AND target_currency = $2
+		ORDER BY date DESC
+		LIMIT 1
+	`
+
+	var rate CurrencyRate
+	err := repo.DB.QueryRowContext(ctx, query, baseCurrency, targetCurrency).
+		Scan(&rate.Date, &rate.Rate, &rate.FetchedAt, &rate.Provider)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("latest rate %s/%s: %w", baseCurrency, targetCurrency, ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query latest exchange rate: %w", classify(ctx, err))
+	}
+
+	return &rate, nil
+}
+
+func (repo *PostgresRepository) FindBatch(
+	ctx context.Context,
+	queries []dto.RateQueryDTO,
+	lookbackDays int,
+) (map[int]CurrencyRate, error) {
+	rates := make(map[int]CurrencyRate, len(queries))
+	if len(queries) == 0 {
+		return rates, nil
+	}
+
+	bases := make([]string, len(queries))
+	targets := make([]string, len(queries))
+	dates := make([]string, len(queries))
+	for i, q := range queries {
+		bases[i] = q.BaseCurrency
+		targets[i] = q.TargetCurrency
+		dates[i] = q.Date.Format("2006-01-02")
+	}
+
+	// Для каждого запроса — одно чтение уникального индекса в обратном порядке
+	query := `
+		SELECT q.idx - 1, r.date, r.rate, r.fetched_at, r.provider
+		FROM unnest($1::varchar[], $2::varchar[], $3::date[])
+			WITH ORDINALITY AS q(base_currency, target_currency, date, idx)
+		CROSS JOIN LATERAL (
+			SELECT e.date, e.rate, e.fetched_at, e.provider
+			FROM exchange_rates e
+			WHERE e.base_currency = q.base_currency
+				AND e.target_currency = q.target_currency
+				AND e.date <= q.date
+				AND e.date > q.date - $4::int
+			ORDER BY e.date DESC
+			LIMIT 1
+		) r
+	`
+
+	rows, err := repo.DB.QueryContext(ctx, query,
+		pq.Array(bases), pq.Array(targets), pq.Array(dates), lookbackDays)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query exchange rates batch: %w", classify(ctx, err))
+	}
+	defer func(rows *sql.Rows) {
+		_ = rows.Close()
+	}(rows)
+
+	for rows.Next() {
+		var (
+			idx  int
+			rate CurrencyRate
+		)
+		if err := rows.Scan(&idx, &rate.Date, &rate.Rate, &rate.FetchedAt, &rate.Provider); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		rates[idx] = rate
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during rows iteration: %w", classify(ctx, err))
 	}
 
 	return rates, nil
@@ -0,0 +1,33 @@
+package servertls
+
+import (
+	"net"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// Сеть соединений в памяти, по которым HTTP-шлюз вызывает gRPC-сервер
+const inProcessNetwork = "bufconn"
+
+// Credentials returns transport credentials of the gRPC server.
+// Connections of the in-process listener used by the HTTP gateway never
+// leave the process and skip the TLS handshake.
+func (r *Reloader) Credentials() credentials.TransportCredentials {
+	return &serverCredentials{TransportCredentials: credentials.NewTLS(r.Config())}
+}
+
+type serverCredentials struct {
+	credentials.TransportCredentials
+}
+
+func (c *serverCredentials) ServerHandshake(conn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	if conn.LocalAddr().Network() == inProcessNetwork {
+		return insecure.NewCredentials().ServerHandshake(conn)
+	}
+	return c.TransportCredentials.ServerHandshake(conn)
+}
+
+func (c *serverCredentials) Clone() credentials.TransportCredentials {
+	return &serverCredentials{TransportCredentials: c.TransportCredentials.Clone()}
+}
@@ -0,0 +1,184 @@
+// Package servertls provides TLS credentials of the gRPC server that are
+// reloaded when the certificate files change.
+package servertls
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log/slog"
+	"my-currency-service/currency/internal/config"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const defaultReloadInterval = 30 * time.Second
+
+// fileState is what a file looked like when it was loaded.
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// Reloader keeps the server certificate and the client CA bundle loaded
+// from files. Every handshake uses the files loaded last, so open
+// connections keep their certificate and new ones get the rotated one.
+type Reloader struct {
+	cfg    config.TLSConfig
+	logger *slog.Logger
+
+	current atomic.Pointer[tls.Config]
+
+	mu     sync.Mutex
+	loaded map[string]fileState
+}
+
+func NewReloader(cfg config.TLSConfig, logger *slog.Logger) (*Reloader, error) {
+	if cfg.CertFile == "" || cfg.KeyFile == "" {
+		return nil, errors.New("TLS is enabled but cert_file or key_file is empty")
+	}
+	if cfg.RequireClientCert && cfg.ClientCAFile == "" {
+		return nil, errors.New("require_client_cert needs client_ca_file")
+	}
+	if cfg.ReloadInterval <= 0 {
+		cfg.ReloadInterval = defaultReloadInterval
+	}
+
+	r := &Reloader{
+		cfg:    cfg,
+		logger: logger,
+	}
+	if err := r.reload(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Config returns the server TLS config. It resolves the loaded files on
+// every handshake.
+func (r *Reloader) Config() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			return r.current.Load(), nil
+		},
+	}
+}
+
+// Run checks the files for changes until ctx is done. A failed reload is
+// logged and the previous certificate stays in use: during a rotation the
+// certificate and the key may be written at different moments.
+func (r *Reloader) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.cfg.ReloadInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			changed, err := r.changed()
+			if err != nil {
+				r.logger.Error("failed to check TLS files", slog.Any("error", err))
+				continue
+			}
+			if !changed {
+				continue
+			}
+
+			if err := r.reload(); err != nil {
+				r.logger.Error("failed to reload TLS certificate, keeping the previous one", slog.Any("error", err))
+				continue
+			}
+			r.logger.Info("TLS certificate reloaded", slog.String("cert_file", r.cfg.CertFile))
+		}
+	}
+}
+
+func (r *Reloader) files() []string {
+	files := []string{r.cfg.CertFile, r.cfg.KeyFile}
+	if r.cfg.ClientCAFile != "" {
+		files = append(files, r.cfg.ClientCAFile)
+	}
+	return files
+}
+
+// changed reports whether any file differs from the loaded one.
+func (r *Reloader) changed() (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, path := range r.files() {
+		state, err := stat(path)
+		if err != nil {
+			return false, err
+		}
+		if state != r.loaded[path] {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (r *Reloader) reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Состояние снимается до чтения: если файл перепишут во время загрузки,
+	// следующая проверка увидит изменение
+	loaded := make(map[string]fileState, 3)
+	for _, path := range r.files() {
+		state, err := stat(path)
+		if err != nil {
+			return err
+		}
+		loaded[path] = state
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.cfg.CertFile, r.cfg.KeyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load TLS key pair: %w", err)
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+	}
+
+	if r.cfg.ClientCAFile != "" {
+		data, err := os.ReadFile(r.cfg.ClientCAFile)
+		if err != nil {
+			return fmt.Errorf("failed to read client CA file: %w", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return fmt.Errorf("no certificates found in client CA file %s", r.cfg.ClientCAFile)
+		}
+
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+		if r.cfg.RequireClientCert {
+			tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+		}
+	}
+
+	r.current.Store(tlsConfig)
+	r.loaded = loaded
+
+	return nil
+}
+
+func stat(path string) (fileState, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileState{}, fmt.Errorf("failed to stat TLS file: %w", err)
+	}
+	return fileState{modTime: info.ModTime(), size: info.Size()}, nil
+}
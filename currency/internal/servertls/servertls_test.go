@@ -0,0 +1,279 @@
+package servertls
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"log/slog"
+	"math/big"
+	"my-currency-service/currency/internal/auth"
+	"my-currency-service/currency/internal/config"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return &testCA{cert: cert, key: key}
+}
+
+// issue returns a PEM certificate and key signed by the CA.
+func (ca *testCA) issue(t *testing.T, serial int64, commonName string, usage x509.ExtKeyUsage) ([]byte, []byte) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: commonName},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func (ca *testCA) pool() *x509.CertPool {
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.cert)
+	return pool
+}
+
+func (ca *testCA) pem() []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.cert.Raw})
+}
+
+func writeFile(t *testing.T, path string, data []byte) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+}
+
+// writeServerCert writes a server certificate and moves its mtime forward
+// so that the change is seen even on filesystems with coarse timestamps.
+func writeServerCert(t *testing.T, ca *testCA, cfg config.TLSConfig, serial int64) {
+	t.Helper()
+
+	certPEM, keyPEM := ca.issue(t, serial, "localhost", x509.ExtKeyUsageServerAuth)
+	writeFile(t, cfg.CertFile, certPEM)
+	writeFile(t, cfg.KeyFile, keyPEM)
+
+	mtime := time.Now().Add(time.Duration(serial) * time.Second)
+	require.NoError(t, os.Chtimes(cfg.CertFile, mtime, mtime))
+	require.NoError(t, os.Chtimes(cfg.KeyFile, mtime, mtime))
+}
+
+func newTestConfig(t *testing.T, ca *testCA) config.TLSConfig {
+	t.Helper()
+
+	dir := t.TempDir()
+	cfg := config.TLSConfig{
+		Enabled:           true,
+		CertFile:          filepath.Join(dir, "server.crt"),
+		KeyFile:           filepath.Join(dir, "server.key"),
+		ClientCAFile:      filepath.Join(dir, "clients-ca.crt"),
+		RequireClientCert: true,
+		ReloadInterval:    10 * time.Millisecond,
+	}
+	writeServerCert(t, ca, cfg, 2)
+	writeFile(t, cfg.ClientCAFile, ca.pem())
+
+	return cfg
+}
+
+// serve starts a server with health checks and returns its address and
+// the identity of the last caller.
+func serve(t *testing.T, creds credentials.TransportCredentials, listener net.Listener) *auth.Identity {
+	t.Helper()
+
+	var identity auth.Identity
+	server := grpc.NewServer(
+		grpc.Creds(creds),
+		grpc.UnaryInterceptor(func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+			identity, _ = auth.PeerIdentity(ctx)
+			return handler(ctx, req)
+		}),
+	)
+	grpc_health_v1.RegisterHealthServer(server, health.NewServer())
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	return &identity
+}
+
+func check(t *testing.T, addr string, opts ...grpc.DialOption) (*tls.ConnectionState, error) {
+	t.Helper()
+
+	conn, err := grpc.NewClient(addr, opts...)
+	require.NoError(t, err)
+	defer func() { _ = conn.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var p peer.Peer
+	_, err = grpc_health_v1.NewHealthClient(conn).Check(ctx, &grpc_health_v1.HealthCheckRequest{}, grpc.Peer(&p))
+	if err != nil {
+		return nil, err
+	}
+	if tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+		return &tlsInfo.State, nil
+	}
+	return nil, nil
+}
+
+func clientCreds(t *testing.T, ca *testCA, commonName string) credentials.TransportCredentials {
+	t.Helper()
+
+	tlsConfig := &tls.Config{RootCAs: ca.pool(), ServerName: "localhost", MinVersion: tls.VersionTLS12}
+	if commonName != "" {
+		certPEM, keyPEM := ca.issue(t, 10, commonName, x509.ExtKeyUsageClientAuth)
+		cert, err := tls.X509KeyPair(certPEM, keyPEM)
+		require.NoError(t, err)
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return credentials.NewTLS(tlsConfig)
+}
+
+func TestReloader_MutualTLS(t *testing.T) {
+	ca := newTestCA(t)
+	reloader, err := NewReloader(newTestConfig(t, ca), slog.Default())
+	require.NoError(t, err)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	identity := serve(t, reloader.Credentials(), listener)
+
+	state, err := check(t, listener.Addr().String(), grpc.WithTransportCredentials(clientCreds(t, ca, "reporting")))
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), state.PeerCertificates[0].SerialNumber.Int64())
+	assert.Equal(t, auth.Identity{Subject: "reporting", Method: "mtls"}, *identity)
+
+	// Без клиентского сертификата соединение отклоняется
+	_, err = check(t, listener.Addr().String(), grpc.WithTransportCredentials(clientCreds(t, ca, "")))
+	assert.Error(t, err)
+
+	// Сертификат чужого CA не принимается
+	_, err = check(t, listener.Addr().String(), grpc.WithTransportCredentials(clientCreds(t, newTestCA(t), "reporting")))
+	assert.Error(t, err)
+}
+
+func TestReloader_Reload(t *testing.T) {
+	ca := newTestCA(t)
+	cfg := newTestConfig(t, ca)
+	reloader, err := NewReloader(cfg, slog.Default())
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go reloader.Run(ctx)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	serve(t, reloader.Credentials(), listener)
+
+	serial := func() int64 {
+		state, err := check(t, listener.Addr().String(), grpc.WithTransportCredentials(clientCreds(t, ca, "reporting")))
+		require.NoError(t, err)
+		return state.PeerCertificates[0].SerialNumber.Int64()
+	}
+
+	writeServerCert(t, ca, cfg, 3)
+	assert.Eventually(t, func() bool { return serial() == 3 }, 5*time.Second, 20*time.Millisecond)
+
+	// Битый ключ не заменяет рабочий сертификат
+	writeFile(t, cfg.KeyFile, []byte("not a key"))
+	changed, err := reloader.changed()
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Error(t, reloader.reload())
+	assert.Equal(t, int64(3), serial())
+}
+
+func TestReloader_InProcess(t *testing.T) {
+	ca := newTestCA(t)
+	reloader, err := NewReloader(newTestConfig(t, ca), slog.Default())
+	require.NoError(t, err)
+
+	// Соединения шлюза в памяти обходятся без TLS
+	listener := bufconn.Listen(1 << 20)
+	identity := serve(t, reloader.Credentials(), listener)
+
+	state, err := check(t, "passthrough:///in-process",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	assert.Nil(t, state)
+	assert.Empty(t, identity.Subject)
+}
+
+func TestNewReloader_Invalid(t *testing.T) {
+	ca := newTestCA(t)
+	cfg := newTestConfig(t, ca)
+
+	_, err := NewReloader(config.TLSConfig{Enabled: true, CertFile: cfg.CertFile}, slog.Default())
+	assert.Error(t, err)
+
+	_, err = NewReloader(config.TLSConfig{Enabled: true, CertFile: cfg.CertFile, KeyFile: cfg.KeyFile, RequireClientCert: true}, slog.Default())
+	assert.Error(t, err)
+
+	// В CA-бандле нет сертификатов
+	writeFile(t, cfg.ClientCAFile, []byte("empty"))
+	_, err = NewReloader(cfg, slog.Default())
+	assert.Error(t, err)
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"my-currency-service/currency/internal/config"
+	"my-currency-service/currency/internal/dto"
+	"my-currency-service/currency/internal/events"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackfillCurrencyRates_KeepsPeriod(t *testing.T) {
+	repo := &memoryRepository{}
+	provider := &recordingProvider{stubProvider: stubProvider{records: []dto.RateRecordDTO{
+		rate(day(2), "eur", "usd", "1.03"),
+		rate(day(3), "eur", "usd", "1.04"),
+	}}}
+	bus := events.NewBus(nil, slog.Default())
+	svc := NewCurrency(config.RatesConfig{}, repo, provider, bus, nil, nil, slog.Default())
+
+	sub, err := svc.SubscribeRates(context.Background(), []dto.CurrencyPairDTO{{BaseCurrency: "EUR", TargetCurrency: "USD"}})
+	require.NoError(t, err)
+	defer sub.Close()
+
+	count, err := svc.BackfillCurrencyRates(context.Background(), &dto.CurrencyRequestDTO{
+		BaseCurrency:   "eur",
+		TargetCurrency: "usd",
+		DateFrom:       day(1),
+		DateTo:         day(31),
+	})
+	require.NoError(t, err)
+
+	// Период не подменяется на вчера/сегодня
+	assert.Equal(t, day(1), provider.req.DateFrom)
+	assert.Equal(t, day(31), provider.req.DateTo)
+	assert.Equal(t, "EUR", provider.req.BaseCurrency)
+
+	assert.Equal(t, 2, count)
+	require.Len(t, repo.rates, 2)
+	assert.Equal(t, "USD", repo.rates[0].TargetCurrency)
+
+	// История подписчикам не рассылается
+	assert.Empty(t, sub.Events())
+}
+
+func TestBackfillCurrencyRates_RequiresPeriod(t *testing.T) {
+	svc := newTestService()
+
+	_, err := svc.BackfillCurrencyRates(context.Background(), &dto.CurrencyRequestDTO{BaseCurrency: "EUR", TargetCurrency: "USD"})
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,235 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"my-currency-service/currency/internal/dto"
+	"my-currency-service/currency/internal/iso4217"
+	"my-currency-service/currency/internal/repository"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// BatchRateResult is the answer to one query of a batch: either Rate or Err.
+// FixingDate is the fixing that applies on the query date by the provider
+// calendar; Rate is the latest observation on or before it.
+type BatchRateResult struct {
+	Rate       *repository.CurrencyRate
+	FixingDate time.Time
+	Err        error
+}
+
+type batchKey struct {
+	base, target string
+	date         time.Time
+}
+
+// BatchGetRates answers queries in request order. Identical queries are
+// looked up once; stored pairs take one set-based query, pairs that need
+// an inverse or a cross rate take one more for all of them together.
+func (s *Currency) BatchGetRates(ctx context.Context, queries []dto.RateQueryDTO) ([]BatchRateResult, error) {
+	results := make([]BatchRateResult, len(queries))
+
+	var unique []dto.RateQueryDTO
+	positions := make(map[batchKey]int)
+	owners := make([]int, len(queries))
+	for i, q := range queries {
+		q.BaseCurrency = strings.ToUpper(q.BaseCurrency)
+		q.TargetCurrency = strings.ToUpper(q.TargetCurrency)
+		q.Date = s.applicableDate(q.Date)
+
+		switch {
+		case !iso4217.Valid(q.BaseCurrency):
+			results[i].Err = fmt.Errorf("%w: %q", ErrUnknownCurrency, q.BaseCurrency)
+			owners[i] = -1
+			continue
+		case !iso4217.Valid(q.TargetCurrency):
+			results[i].Err = fmt.Errorf("%w: %q", ErrUnknownCurrency, q.TargetCurrency)
+			owners[i] = -1
+			continue
+		}
+
+		key := batchKey{q.BaseCurrency, q.TargetCurrency, q.Date}
+		pos, ok := positions[key]
+		if !ok {
+			pos = len(unique)
+			positions[key] = pos
+			unique = append(unique, q)
+		}
+		owners[i] = pos
+	}
+
+	found, err := s.resolveBatch(ctx, unique)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, pos := range owners {
+		if pos < 0 {
+			continue
+		}
+		results[i].FixingDate = unique[pos].Date
+		if rate, ok := found[pos]; ok {
+			results[i].Rate = &rate
+			continue
+		}
+
+		q := unique[pos]
+		results[i].Err = fmt.Errorf("%w: %s/%s on or before %s",
+			ErrRateNotFound, q.BaseCurrency, q.TargetCurrency, q.Date.Format("2006-01-02"))
+	}
+
+	return results, nil
+}
+
+func (s *Currency) resolveBatch(ctx context.Context, queries []dto.RateQueryDTO) (map[int]repository.CurrencyRate, error) {
+	found, err := s.currencyRepo.FindBatch(ctx, queries, rateLookbackDays)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch currency rates batch: %w", err)
+	}
+
+	for i, q := range queries {
+		if q.BaseCurrency == q.TargetCurrency {
+			found[i] = repository.CurrencyRate{Date: q.Date, Rate: decimal.NewFromInt(1)}
+		}
+	}
+
+	var missing []int
+	for i := range queries {
+		if _, ok := found[i]; !ok {
+			missing = append(missing, i)
+		}
+	}
+
+	// Ноги всех недостающих пар собираются в один запрос. Если последние
+	// наблюдения ног кросс-курса пришлись на разные даты, ноги запрашиваются
+	// снова на более раннюю из них, пока не найдётся общая дата, как в deriveRates.
+	dates := make(map[int]time.Time, len(missing))
+	for _, i := range missing {
+		dates[i] = queries[i].Date
+	}
+	for round := 0; len(missing) > 0; round++ {
+		var err error
+		missing, err = s.resolveLegs(ctx, queries, missing, dates, round == 0, found)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return found, nil
+}
+
+// resolveLegs derives the queries at indices missing from their legs as of
+// dates. The inverse pair is only tried when inverse is set. It returns the
+// cross rates whose legs were last observed on different dates; their date
+// is moved to the earlier one.
+func (s *Currency) resolveLegs(
+	ctx context.Context,
+	queries []dto.RateQueryDTO,
+	missing []int,
+	dates map[int]time.Time,
+	inverse bool,
+	found map[int]repository.CurrencyRate,
+) ([]int, error) {
+	var legQueries []dto.RateQueryDTO
+	legIndex := make(map[batchKey]int)
+	addLeg := func(base, target string, date time.Time) {
+		key := batchKey{base, target, date}
+		if _, ok := legIndex[key]; ok {
+			return
+		}
+		legIndex[key] = len(legQueries)
+		legQueries = append(legQueries, dto.RateQueryDTO{BaseCurrency: base, TargetCurrency: target, Date: date})
+	}
+
+	for _, i := range missing {
+		q, date := queries[i], dates[i]
+		if inverse {
+			addLeg(q.TargetCurrency, q.BaseCurrency, date)
+		}
+		if q.BaseCurrency != s.pivotCurrency && q.TargetCurrency != s.pivotCurrency {
+			addLeg(q.BaseCurrency, s.pivotCurrency, date)
+			addLeg(s.pivotCurrency, q.BaseCurrency, date)
+			addLeg(s.pivotCurrency, q.TargetCurrency, date)
+			addLeg(q.TargetCurrency, s.pivotCurrency, date)
+		}
+	}
+
+	if len(legQueries) == 0 {
+		return nil, nil
+	}
+
+	legRates, err := s.currencyRepo.FindBatch(ctx, legQueries, rateLookbackDays)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch currency rate legs batch: %w", err)
+	}
+
+	lookup := func(base, target string, date time.Time) (repository.CurrencyRate, bool) {
+		idx, ok := legIndex[batchKey{base, target, date}]
+		if !ok {
+			return repository.CurrencyRate{}, false
+		}
+		rate, ok := legRates[idx]
+		return rate, ok
+	}
+
+	// leg returns the base->target observation stored in either direction
+	leg := func(base, target string, date time.Time) (map[time.Time]repository.RateLeg, time.Time, bool) {
+		if rate, ok := lookup(base, target, date); ok {
+			return map[time.Time]repository.RateLeg{rate.Date: {
+				BaseCurrency: base, TargetCurrency: target, Rate: rate.Rate, FetchedAt: rate.FetchedAt, Provider: rate.Provider,
+			}}, rate.Date, true
+		}
+		if rate, ok := lookup(target, base, date); ok {
+			return map[time.Time]repository.RateLeg{rate.Date: {
+				BaseCurrency: target, TargetCurrency: base, Rate: rate.Rate, Inverted: true, FetchedAt: rate.FetchedAt, Provider: rate.Provider,
+			}}, rate.Date, true
+		}
+		return nil, time.Time{}, false
+	}
+
+	var retry []int
+	for _, i := range missing {
+		q, date := queries[i], dates[i]
+
+		if inverse {
+			if legs, _, ok := leg(q.BaseCurrency, q.TargetCurrency, date); ok {
+				if derived := ratesFromLegs(legs); len(derived) > 0 {
+					found[i] = derived[0]
+				}
+				continue
+			}
+		}
+		if q.BaseCurrency == s.pivotCurrency || q.TargetCurrency == s.pivotCurrency {
+			continue
+		}
+
+		first, firstDate, okFirst := leg(q.BaseCurrency, s.pivotCurrency, date)
+		second, secondDate, okSecond := leg(s.pivotCurrency, q.TargetCurrency, date)
+		if !okFirst || !okSecond {
+			continue
+		}
+
+		// Общая дата ищется только в окне исходного запроса
+		earlier := firstDate
+		if secondDate.Before(earlier) {
+			earlier = secondDate
+		}
+		if !earlier.After(q.Date.AddDate(0, 0, -rateLookbackDays)) {
+			continue
+		}
+		if !firstDate.Equal(secondDate) {
+			dates[i] = earlier
+			retry = append(retry, i)
+			continue
+		}
+
+		if derived := ratesFromLegs(first, second); len(derived) > 0 {
+			found[i] = derived[0]
+		}
+	}
+
+	return retry, nil
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"my-currency-service/currency/internal/config"
+	"my-currency-service/currency/internal/dto"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchGetRates(t *testing.T) {
+	repo := &memoryRepository{rates: []dto.RateRecordDTO{
+		rate(day(13), "EUR", "USD", "1.25"),
+		rate(day(15), "EUR", "USD", "1.28"),
+		rate(day(15), "EUR", "GBP", "0.8"),
+		rate(day(15), "EUR", "JPY", "160"),
+	}}
+	svc := NewCurrency(config.RatesConfig{}, repo, stubProvider{}, nil, nil, nil, slog.Default())
+
+	results, err := svc.BatchGetRates(context.Background(), []dto.RateQueryDTO{
+		{BaseCurrency: "eur", TargetCurrency: "usd", Date: day(14)},
+		{BaseCurrency: "EUR", TargetCurrency: "USD", Date: day(16)},
+		{BaseCurrency: "USD", TargetCurrency: "EUR", Date: day(13)},
+		{BaseCurrency: "GBP", TargetCurrency: "JPY", Date: day(15)},
+		{BaseCurrency: "EUR", TargetCurrency: "USD", Date: day(14)},
+		{BaseCurrency: "EUR", TargetCurrency: "CHF", Date: day(15)},
+		{BaseCurrency: "EUR", TargetCurrency: "ABC", Date: day(15)},
+		{BaseCurrency: "GBP", TargetCurrency: "GBP", Date: day(15)},
+	})
+
+	require.NoError(t, err)
+	require.Len(t, results, 8)
+
+	// Результаты в порядке запросов
+	require.NoError(t, results[0].Err)
+	assert.Equal(t, day(13), results[0].Rate.Date)
+	assert.Equal(t, "1.25", results[0].Rate.Rate.String())
+
+	require.NoError(t, results[1].Err)
+	assert.Equal(t, "1.28", results[1].Rate.Rate.String())
+
+	require.NoError(t, results[2].Err)
+	assert.True(t, results[2].Rate.Derived)
+	assert.Equal(t, "0.8", results[2].Rate.Rate.String())
+
+	require.NoError(t, results[3].Err)
+	assert.True(t, results[3].Rate.Derived)
+	assert.Equal(t, "200", results[3].Rate.Rate.String())
+	assert.Len(t, results[3].Rate.Legs, 2)
+
+	require.NoError(t, results[4].Err)
+	assert.Equal(t, results[0].Rate.Rate, results[4].Rate.Rate)
+
+	assert.ErrorIs(t, results[5].Err, ErrRateNotFound)
+	assert.Nil(t, results[5].Rate)
+	assert.ErrorIs(t, results[6].Err, ErrUnknownCurrency)
+
+	require.NoError(t, results[7].Err)
+	assert.Equal(t, "1", results[7].Rate.Rate.String())
+
+	// Один запрос за прямыми парами и один за ногами производных
+	assert.Equal(t, 2, repo.batchCalls)
+}
+
+func TestBatchGetRates_CrossLegsOnDifferentDates(t *testing.T) {
+	// 16-го иены нет: общая дата ног — 15-е, как и у GetCurrencyRatesInInterval
+	repo := &memoryRepository{rates: []dto.RateRecordDTO{
+		rate(day(15), "EUR", "GBP", "0.8"),
+		rate(day(16), "EUR", "GBP", "0.75"),
+		rate(day(15), "EUR", "JPY", "160"),
+	}}
+	svc := NewCurrency(config.RatesConfig{}, repo, stubProvider{}, nil, nil, nil, slog.Default())
+
+	results, err := svc.BatchGetRates(context.Background(), []dto.RateQueryDTO{
+		{BaseCurrency: "GBP", TargetCurrency: "JPY", Date: day(16)},
+	})
+
+	require.NoError(t, err)
+	require.NoError(t, results[0].Err)
+	assert.Equal(t, day(15), results[0].Rate.Date)
+	assert.Equal(t, "200", results[0].Rate.Rate.String())
+	// Прямые пары, ноги и повтор ног на общую дату
+	assert.Equal(t, 3, repo.batchCalls)
+
+	rates, err := svc.GetCurrencyRatesInInterval(context.Background(), &dto.CurrencyRequestDTO{
+		BaseCurrency: "GBP", TargetCurrency: "JPY", DateFrom: day(16).AddDate(0, 0, -rateLookbackDays), DateTo: day(16),
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, rates)
+	assert.Equal(t, rates[len(rates)-1].Date, results[0].Rate.Date)
+	assert.Equal(t, rates[len(rates)-1].Rate, results[0].Rate.Rate)
+}
+
+func TestBatchGetRates_AllStored(t *testing.T) {
+	repo := &memoryRepository{rates: []dto.RateRecordDTO{rate(day(15), "EUR", "USD", "1.28")}}
+	svc := NewCurrency(config.RatesConfig{}, repo, stubProvider{}, nil, nil, nil, slog.Default())
+
+	results, err := svc.BatchGetRates(context.Background(), []dto.RateQueryDTO{
+		{BaseCurrency: "EUR", TargetCurrency: "USD", Date: day(15)},
+		{BaseCurrency: "EUR", TargetCurrency: "USD", Date: day(16)},
+	})
+
+	require.NoError(t, err)
+	assert.NoError(t, results[0].Err)
+	assert.NoError(t, results[1].Err)
+	assert.Equal(t, 1, repo.batchCalls)
+}
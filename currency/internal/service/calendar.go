@@ -0,0 +1,29 @@
+package service
+
+import (
+	"my-currency-service/currency/internal/calendar"
+	"my-currency-service/currency/internal/clients/currency"
+	"time"
+)
+
+// Calendar returns the publishing calendar of the provider of the service.
+func (s *Currency) Calendar() calendar.Calendar {
+	return s.calendars.ForProvider(currency.PrimaryName(s.provider))
+}
+
+// FixingDate returns the date of the fixing that applies on date: the last
+// day on or before it on which the provider publishes. On a holiday or a
+// weekend that is the fixing of the previous business day. ok is false if
+// the provider published nothing within a month before date.
+func (s *Currency) FixingDate(date time.Time) (time.Time, bool) {
+	return calendar.FixingDate(s.Calendar(), date)
+}
+
+// applicableDate returns the fixing date that applies on date, or the day
+// of date itself if the calendar knows no fixing before it.
+func (s *Currency) applicableDate(date time.Time) time.Time {
+	if fixing, ok := s.FixingDate(date); ok {
+		return fixing
+	}
+	return truncateDay(date)
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"my-currency-service/currency/internal/calendar"
+	"my-currency-service/currency/internal/config"
+	"my-currency-service/currency/internal/dto"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newCalendarTestService returns a service whose provider publishes by the calendar name.
+func newCalendarTestService(t *testing.T, name string, now time.Time, rates ...dto.RateRecordDTO) *Currency {
+	calendars, err := calendar.New(config.CalendarConfig{Providers: map[string]string{"stub": name}})
+	require.NoError(t, err)
+
+	svc := NewCurrency(config.RatesConfig{}, &memoryRepository{rates: rates}, stubProvider{}, nil, calendars, nil, slog.Default())
+	svc.now = func() time.Time { return now }
+	return svc
+}
+
+func date(s string) time.Time {
+	t, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestTARGET2Holiday(t *testing.T) {
+	// Страстная пятница 18.04.2025 и Пасхальный понедельник 21.04 — праздники TARGET2
+	svc := newCalendarTestService(t, calendar.NameTARGET2, date("2025-04-22").Add(10*time.Hour),
+		rate(date("2025-04-16"), "EUR", "USD", "1.1355"),
+		rate(date("2025-04-17"), "EUR", "USD", "1.1369"),
+	)
+	ctx := context.Background()
+
+	// Во вторник утром фиксинг четверга старше max_age, но пропусков нет
+	latest, err := svc.GetLatestRate(ctx, &dto.CurrencyRequestDTO{BaseCurrency: "EUR", TargetCurrency: "USD"})
+	require.NoError(t, err)
+	assert.Equal(t, date("2025-04-17"), latest.Date)
+	assert.Greater(t, latest.Age, latest.MaxAge)
+	assert.False(t, latest.Stale)
+	assert.Equal(t, date("2025-04-22"), latest.FixingDate)
+
+	// Период из одних праздников отвечает действующим на них фиксингом
+	rates, err := svc.GetCurrencyRatesInInterval(ctx, &dto.CurrencyRequestDTO{
+		BaseCurrency: "EUR", TargetCurrency: "USD", DateFrom: date("2025-04-18"), DateTo: date("2025-04-21"),
+	})
+	require.NoError(t, err)
+	require.Len(t, rates, 1)
+	assert.Equal(t, date("2025-04-17"), rates[0].Date)
+
+	conversion, err := svc.Convert(ctx, &dto.ConvertRequestDTO{
+		Amount: decimal.RequireFromString("100"), From: "EUR", To: "USD", AsOf: date("2025-04-21"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, date("2025-04-17"), conversion.FixingDate)
+	assert.Equal(t, date("2025-04-17"), conversion.RateDate)
+	assert.Equal(t, "113.69", conversion.Amount.String())
+
+	// Через неделю не хватает фиксингов 22-24 апреля
+	svc.now = func() time.Time { return date("2025-04-28").Add(12 * time.Hour) }
+	latest, err = svc.GetLatestRate(ctx, &dto.CurrencyRequestDTO{BaseCurrency: "EUR", TargetCurrency: "USD"})
+	require.NoError(t, err)
+	assert.True(t, latest.Stale)
+}
+
+func TestRussianHoliday(t *testing.T) {
+	// День России 12.06.2025: курс, установленный 11 июня, датирован 12-м,
+	// а на 13 июня ЦБ курс не устанавливает
+	svc := newCalendarTestService(t, calendar.NameCBR, date("2025-06-13").Add(12*time.Hour),
+		rate(date("2025-06-11"), "USD", "RUB", "78.9197"),
+		rate(date("2025-06-12"), "USD", "RUB", "78.8361"),
+	)
+	ctx := context.Background()
+
+	rates, err := svc.GetCurrencyRatesInInterval(ctx, &dto.CurrencyRequestDTO{
+		BaseCurrency: "USD", TargetCurrency: "RUB", DateFrom: date("2025-06-13"), DateTo: date("2025-06-13"),
+	})
+	require.NoError(t, err)
+	require.Len(t, rates, 1)
+	assert.Equal(t, date("2025-06-12"), rates[0].Date)
+
+	results, err := svc.BatchGetRates(ctx, []dto.RateQueryDTO{
+		{BaseCurrency: "USD", TargetCurrency: "RUB", Date: date("2025-06-13")},
+		{BaseCurrency: "USD", TargetCurrency: "RUB", Date: date("2025-06-11")},
+	})
+	require.NoError(t, err)
+	require.NoError(t, results[0].Err)
+	assert.Equal(t, date("2025-06-12"), results[0].FixingDate)
+	assert.Equal(t, "78.8361", results[0].Rate.Rate.String())
+	require.NoError(t, results[1].Err)
+	assert.Equal(t, date("2025-06-11"), results[1].FixingDate)
+	assert.Equal(t, "78.9197", results[1].Rate.Rate.String())
+
+	latest, err := svc.GetLatestRate(ctx, &dto.CurrencyRequestDTO{BaseCurrency: "USD", TargetCurrency: "RUB"})
+	require.NoError(t, err)
+	assert.Equal(t, date("2025-06-12"), latest.FixingDate)
+	assert.False(t, latest.Stale)
+}
@@ -4,28 +4,87 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"my-currency-service/currency/internal/calendar"
 	"my-currency-service/currency/internal/clients/currency"
+	"my-currency-service/currency/internal/config"
 	"my-currency-service/currency/internal/dto"
+	"my-currency-service/currency/internal/events"
+	"my-currency-service/currency/internal/iso4217"
 	"my-currency-service/currency/internal/repository"
+	"slices"
 	"strings"
 	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Сколько дней назад от запрошенной даты искать последний фиксинг:
+// покрывает выходные и длинные праздники
+const rateLookbackDays = 14
+
+const (
+	DefaultPivotCurrency = "EUR"
+
+	// Допустимая задержка фиксинга сверх календаря провайдера
+	DefaultMaxAge = 96 * time.Hour
 )
 
 type Currency struct {
-	currencyRepo repository.ExchangeRateRepository
-	client       currency.Currency
-	logger       *slog.Logger
+	currencyRepo  repository.ExchangeRateRepository
+	provider      currency.RateProvider
+	events        *events.Bus
+	calendars     *calendar.Calendars
+	jobRuns       repository.JobRunRepository
+	pivotCurrency string
+	maxAge        time.Duration
+	pairMaxAge    map[string]time.Duration
+	now           func() time.Time
+	logger        *slog.Logger
 }
 
 func NewCurrency(
+	cfg config.RatesConfig,
 	repo repository.ExchangeRateRepository,
-	client currency.Currency,
+	provider currency.RateProvider,
+	bus *events.Bus,
+	calendars *calendar.Calendars,
+	jobRuns repository.JobRunRepository,
 	logger *slog.Logger,
 ) *Currency {
+	if bus == nil {
+		bus = events.NewBus(nil, logger)
+	}
+	if calendars == nil {
+		// Встроенные календари без файлов пользователя
+		calendars, _ = calendar.New(config.CalendarConfig{})
+	}
+
+	pivot := strings.ToUpper(cfg.PivotCurrency)
+	if pivot == "" {
+		pivot = DefaultPivotCurrency
+	}
+
+	maxAge := cfg.MaxAge
+	if maxAge <= 0 {
+		maxAge = DefaultMaxAge
+	}
+
+	pairMaxAge := make(map[string]time.Duration, len(cfg.PairMaxAge))
+	for pair, age := range cfg.PairMaxAge {
+		pairMaxAge[strings.ToUpper(pair)] = age
+	}
+
 	return &Currency{
-		currencyRepo: repo,
-		client:       client,
-		logger:       logger,
+		currencyRepo:  repo,
+		provider:      provider,
+		events:        bus,
+		calendars:     calendars,
+		jobRuns:       jobRuns,
+		pivotCurrency: pivot,
+		maxAge:        maxAge,
+		pairMaxAge:    pairMaxAge,
+		now:           time.Now,
+		logger:        logger,
 	}
 }
 
@@ -34,37 +93,210 @@ func (s *Currency) GetCurrencyRatesInInterval(ctx context.Context, reqDTO *dto.C
 	reqDTO.BaseCurrency = strings.ToUpper(reqDTO.BaseCurrency)
 	reqDTO.TargetCurrency = strings.ToUpper(reqDTO.TargetCurrency)
 
+	// Период, начатый в день без публикации, включает действующий в этот день фиксинг
+	if from := s.applicableDate(reqDTO.DateFrom); from.Before(truncateDay(reqDTO.DateFrom)) {
+		reqDTO.DateFrom = from
+	}
+
 	rates, err := s.currencyRepo.FindInInterval(ctx, reqDTO)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch currency rates in interval: %w", err)
 	}
 
+	if len(rates) > 0 || reqDTO.BaseCurrency == reqDTO.TargetCurrency {
+		return rates, nil
+	}
+
+	rates, err = s.deriveRates(ctx, reqDTO)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive currency rates in interval: %w", err)
+	}
+
 	return rates, nil
 
 }
 
-func (s *Currency) FetchAndSaveCurrencyRates(ctx context.Context, reqDTO *dto.CurrencyRequestDTO) error {
+// Convert converts reqDTO.Amount with the latest rate observed on or before
+// the fixing that applies on reqDTO.AsOf and rounds the result to the minor
+// units of the target currency.
+// Amounts in currencies without minor units, such as XAU, are not rounded.
+func (s *Currency) Convert(ctx context.Context, reqDTO *dto.ConvertRequestDTO) (*dto.ConversionDTO, error) {
+	from := strings.ToUpper(reqDTO.From)
+	to := strings.ToUpper(reqDTO.To)
+
+	if !iso4217.Valid(from) {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownCurrency, from)
+	}
+	minorUnits, ok := iso4217.MinorUnits(to)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownCurrency, to)
+	}
+
+	asOf := reqDTO.AsOf
+	if asOf.IsZero() {
+		asOf = s.now().UTC()
+	}
+	fixingDate := s.applicableDate(asOf)
+
+	rate, rateDate := decimal.NewFromInt(1), fixingDate
+	if from != to {
+		rates, err := s.GetCurrencyRatesInInterval(ctx, &dto.CurrencyRequestDTO{
+			BaseCurrency:   from,
+			TargetCurrency: to,
+			DateFrom:       fixingDate.AddDate(0, 0, -rateLookbackDays),
+			DateTo:         fixingDate,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(rates) == 0 {
+			return nil, fmt.Errorf("%w: %s/%s on or before %s",
+				ErrRateNotFound, from, to, asOf.Format("2006-01-02"))
+		}
+
+		latest := rates[0]
+		for _, r := range rates[1:] {
+			if r.Date.After(latest.Date) {
+				latest = r
+			}
+		}
+		rate, rateDate = latest.Rate, latest.Date
+
+		// Более старый курс допустим, но означает пропущенный фиксинг
+		if latest.Date.Before(fixingDate) {
+			s.logger.Warn("applicable fixing is missing, an older rate is used",
+				slog.String("from", from),
+				slog.String("to", to),
+				slog.Time("fixing_date", fixingDate),
+				slog.Time("rate_date", latest.Date))
+		}
+	}
+
+	unrounded := reqDTO.Amount.Mul(rate)
+
+	amount, roundingMode := unrounded.Round(minorUnits), dto.RoundingHalfAwayFromZero
+	if minorUnits == iso4217.NoMinorUnits {
+		amount, roundingMode = unrounded, dto.RoundingNone
+	}
+
+	return &dto.ConversionDTO{
+		Amount:          amount,
+		UnroundedAmount: unrounded,
+		From:            from,
+		To:              to,
+		Rate:            rate,
+		RateDate:        rateDate,
+		FixingDate:      fixingDate,
+		MinorUnits:      minorUnits,
+		RoundingMode:    roundingMode,
+	}, nil
+}
+
+// FetchAndSaveCurrencyRates fetches and saves the rates of yesterday and
+// today and returns the number of saved observations and the providers
+// that supplied them.
+func (s *Currency) FetchAndSaveCurrencyRates(ctx context.Context, reqDTO *dto.CurrencyRequestDTO) (dto.FetchResultDTO, error) {
+
+	today := truncateDay(s.now().UTC())
+
+	reqDTO.DateFrom = today.AddDate(0, 0, -1)
+	reqDTO.DateTo = today
+
+	records, err := s.fetchAndSave(ctx, reqDTO)
+	if err != nil {
+		return dto.FetchResultDTO{}, err
+	}
+
+	s.events.Publish(ctx, records)
+
+	result := dto.FetchResultDTO{Rows: len(records), Provider: suppliers(records)}
+	if result.Provider == "" {
+		result.Provider = s.provider.Name()
+	}
+
+	s.logger.Info("successfully saved currency rates",
+		slog.String("provider", result.Provider),
+		slog.String("base_currency", reqDTO.BaseCurrency),
+		slog.String("target_currency", reqDTO.TargetCurrency),
+		slog.Int("count", len(records)))
+	return result, nil
+
+}
+
+// BackfillCurrencyRates fetches and saves rates for exactly the requested
+// period and returns the number of saved observations. Saving is an upsert,
+// so a period can be loaded again. Subscribers are not notified: they
+// follow current rates, not history.
+func (s *Currency) BackfillCurrencyRates(ctx context.Context, reqDTO *dto.CurrencyRequestDTO) (int, error) {
+	if reqDTO.DateFrom.IsZero() || reqDTO.DateTo.IsZero() {
+		return 0, fmt.Errorf("backfill period is required: DateFrom %s, DateTo %s", reqDTO.DateFrom, reqDTO.DateTo)
+	}
+
+	records, err := s.fetchAndSave(ctx, reqDTO)
+	if err != nil {
+		return 0, err
+	}
 
-	var dayNow = time.Now()
-	var dayYesterday = time.Now().AddDate(0, 0, -1)
+	s.logger.Info("successfully backfilled currency rates",
+		slog.String("provider", s.provider.Name()),
+		slog.String("base_currency", reqDTO.BaseCurrency),
+		slog.String("target_currency", reqDTO.TargetCurrency),
+		slog.Time("date_from", reqDTO.DateFrom),
+		slog.Time("date_to", reqDTO.DateTo),
+		slog.Int("count", len(records)))
+	return len(records), nil
+}
 
-	reqDTO.DateFrom = dayYesterday
-	reqDTO.DateTo = dayNow
+func (s *Currency) fetchAndSave(ctx context.Context, reqDTO *dto.CurrencyRequestDTO) ([]dto.RateRecordDTO, error) {
 	reqDTO.BaseCurrency = strings.ToUpper(reqDTO.BaseCurrency)
 	reqDTO.TargetCurrency = strings.ToUpper(reqDTO.TargetCurrency)
 
-	rates, err := s.client.FetchCurrentRates(ctx, reqDTO)
+	records, err := s.provider.FetchRates(ctx, reqDTO)
 
 	if err != nil {
-		return fmt.Errorf("failed to fetch currency rates in interval: %w", err)
+		return nil, fmt.Errorf("failed to fetch currency rates in interval: %w", err)
+	}
+
+	fetchedAt := s.now().UTC()
+	kept := make([]dto.RateRecordDTO, 0, len(records))
+	for _, record := range records {
+		record.BaseCurrency = strings.ToUpper(record.BaseCurrency)
+		record.TargetCurrency = strings.ToUpper(record.TargetCurrency)
+		// JSON отдаёт всю таблицу, задание же загружает пары по одной:
+		// остальные валюты сохранились бы и опубликовались по разу на каждую пару
+		if reqDTO.TargetCurrency != "" && record.TargetCurrency != reqDTO.TargetCurrency {
+			continue
+		}
+		if record.Provider == "" {
+			record.Provider = s.provider.Name()
+		}
+		if record.FetchedAt.IsZero() {
+			record.FetchedAt = fetchedAt
+		}
+		kept = append(kept, record)
 	}
+	records = kept
 
-	if err := s.currencyRepo.Save(ctx, dayNow, reqDTO.BaseCurrency, rates); err != nil {
-		return fmt.Errorf("failed to save currency rates in interval: %w", err)
+	if err := s.currencyRepo.Save(ctx, records); err != nil {
+		return nil, fmt.Errorf("failed to save currency rates in interval: %w", err)
 	}
 
-	s.logger.Info("successfully saved currency rates", slog.Any("rates", rates))
-	return nil
+	return records, nil
+}
+
+// suppliers returns the providers of records in order of appearance,
+// joined with a comma.
+func suppliers(records []dto.RateRecordDTO) string {
+	var names []string
+	for _, record := range records {
+		if !slices.Contains(names, record.Provider) {
+			names = append(names, record.Provider)
+		}
+	}
+	return strings.Join(names, ",")
+}
 
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
 }
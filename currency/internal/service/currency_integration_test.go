@@ -0,0 +1,131 @@
+//go:build integration
+
+package service_test
+
+import (
+	"context"
+	"log/slog"
+	"my-currency-service/currency/internal/calendar"
+	"my-currency-service/currency/internal/config"
+	"my-currency-service/currency/internal/db"
+	"my-currency-service/currency/internal/dto"
+	"my-currency-service/currency/internal/handler"
+	migrator "my-currency-service/currency/internal/migrations"
+	"my-currency-service/currency/internal/repository"
+	"my-currency-service/currency/internal/service"
+	"my-currency-service/currency/internal/worker"
+	"my-currency-service/pkg/currency"
+	"testing"
+	"time"
+
+	"github.com/go-co-op/gocron"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// XTS и XXX зарезервированы ISO 4217 для тестов и не пересекаются с реальными данными
+const (
+	testBaseCurrency   = "XTS"
+	testTargetCurrency = "XXX"
+)
+
+type stubProvider struct {
+	records []dto.RateRecordDTO
+}
+
+func (p stubProvider) Name() string { return "stub" }
+
+func (p stubProvider) FetchRates(context.Context, *dto.CurrencyRequestDTO) ([]dto.RateRecordDTO, error) {
+	return append([]dto.RateRecordDTO(nil), p.records...), nil
+}
+
+func newTestRepository(t *testing.T) *repository.PostgresRepository {
+	cfg := config.MustLoad()
+
+	// Мигратор закрывает соединение, поэтому для него открывается отдельное
+	migrationConn, err := db.NewDatabaseConnection(cfg.Database)
+	require.NoError(t, err)
+	require.NoError(t, migrator.MustGetNewMigrator(migrator.MigrationsFS, ".").ApplyMigrations(migrationConn))
+
+	conn, err := db.NewDatabaseConnection(cfg.Database)
+	require.NoError(t, err)
+
+	cleanup := func() {
+		_, err := conn.Exec(`DELETE FROM exchange_rates WHERE base_currency = $1`, testBaseCurrency)
+		require.NoError(t, err)
+		_, err = conn.Exec(`DELETE FROM job_runs WHERE base_currency = $1`, testBaseCurrency)
+		require.NoError(t, err)
+	}
+	cleanup()
+	t.Cleanup(func() {
+		cleanup()
+		_ = conn.Close()
+	})
+
+	return repository.NewPostgresRepository(conn)
+}
+
+func TestWorkerFetch_VisibleViaGetRate(t *testing.T) {
+	repo := newTestRepository(t)
+
+	now := time.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	yesterday := today.AddDate(0, 0, -1)
+
+	// Тест не должен зависеть от дня недели запуска
+	calendars, err := calendar.New(config.CalendarConfig{Providers: map[string]string{"stub": calendar.NameEveryDay}})
+	require.NoError(t, err)
+
+	svc := service.NewCurrency(config.RatesConfig{}, repo, stubProvider{records: []dto.RateRecordDTO{
+		{Date: yesterday, BaseCurrency: testBaseCurrency, TargetCurrency: testTargetCurrency, Value: decimal.RequireFromString("1.25")},
+		{Date: today, BaseCurrency: testBaseCurrency, TargetCurrency: testTargetCurrency, Value: decimal.RequireFromString("157.709631745")},
+	}}, nil, calendars, repo, slog.Default())
+
+	workerCfg := config.WorkerConfig{Jobs: []config.WorkerJobConfig{{
+		BaseCurrency:     testBaseCurrency,
+		TargetCurrencies: []string{testTargetCurrency},
+		Schedule:         "@daily",
+	}}}
+
+	currencyWorker, err := worker.NewCurrency(workerCfg, map[string]worker.CurrencyService{"": svc}, gocron.NewScheduler(time.UTC), nil, repo, nil, slog.Default())
+	require.NoError(t, err)
+	require.NoError(t, currencyWorker.StartFetchingCurrencyRates())
+	t.Cleanup(func() { _ = currencyWorker.Stop() })
+
+	server := handler.NewCurrencyServer(svc, slog.Default())
+
+	req := &currency.GetRateRequest{
+		Currency:     testTargetCurrency,
+		BaseCurrency: testBaseCurrency,
+		DataFrom:     timestamppb.New(yesterday),
+		DateTo:       timestamppb.New(today),
+	}
+
+	var resp *currency.GetRateResponse
+	require.Eventually(t, func() bool {
+		var err error
+		resp, err = server.GetRate(context.Background(), req)
+		return err == nil && len(resp.Rates) == 2
+	}, 5*time.Second, 100*time.Millisecond)
+
+	assert.Equal(t, yesterday, resp.Rates[0].Date.AsTime().UTC())
+	assert.Equal(t, "1.25", resp.Rates[0].ExactRate)
+	assert.Equal(t, today, resp.Rates[1].Date.AsTime().UTC())
+	assert.Equal(t, "157.709631745", resp.Rates[1].ExactRate)
+
+	// Запуск при старте записывается в историю после сохранения курсов
+	var runs *currency.GetJobRunsResponse
+	require.Eventually(t, func() bool {
+		var err error
+		runs, err = server.GetJobRuns(context.Background(), &currency.GetJobRunsRequest{
+			BaseCurrency: testBaseCurrency,
+			Status:       dto.JobRunSuccess,
+		})
+		return err == nil && len(runs.Runs) == 1
+	}, 5*time.Second, 100*time.Millisecond)
+	assert.Equal(t, "startup", runs.Runs[0].Trigger)
+	assert.Equal(t, "stub", runs.Runs[0].Provider)
+	assert.Equal(t, int32(2), runs.Runs[0].RowsWritten)
+}
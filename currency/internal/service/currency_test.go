@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"my-currency-service/currency/internal/config"
+	"my-currency-service/currency/internal/dto"
+	"my-currency-service/currency/internal/iso4217"
+	"my-currency-service/currency/internal/repository"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryRepository is an in-memory ExchangeRateRepository for service tests.
+type memoryRepository struct {
+	rates      []dto.RateRecordDTO
+	batchCalls int
+}
+
+func (r *memoryRepository) Save(_ context.Context, rates []dto.RateRecordDTO) error {
+	r.rates = append(r.rates, rates...)
+	return nil
+}
+
+func (r *memoryRepository) FindInInterval(_ context.Context, req *dto.CurrencyRequestDTO) ([]repository.CurrencyRate, error) {
+	var found []repository.CurrencyRate
+	for _, rate := range r.rates {
+		if rate.BaseCurrency != req.BaseCurrency || rate.TargetCurrency != req.TargetCurrency {
+			continue
+		}
+		if rate.Date.Before(truncate(req.DateFrom)) || rate.Date.After(truncate(req.DateTo)) {
+			continue
+		}
+		found = append(found, repository.CurrencyRate{Date: rate.Date, Rate: rate.Value, FetchedAt: rate.Date.Add(time.Hour), Provider: rate.Provider})
+	}
+	return found, nil
+}
+
+func (r *memoryRepository) FindLatest(_ context.Context, base, target string) (*repository.CurrencyRate, error) {
+	var latest *repository.CurrencyRate
+	for _, rate := range r.rates {
+		if rate.BaseCurrency != base || rate.TargetCurrency != target {
+			continue
+		}
+		if latest == nil || rate.Date.After(latest.Date) {
+			latest = &repository.CurrencyRate{Date: rate.Date, Rate: rate.Value, FetchedAt: rate.Date.Add(time.Hour), Provider: rate.Provider}
+		}
+	}
+	if latest == nil {
+		return nil, repository.ErrNotFound
+	}
+	return latest, nil
+}
+
+func (r *memoryRepository) FindBatch(_ context.Context, queries []dto.RateQueryDTO, lookbackDays int) (map[int]repository.CurrencyRate, error) {
+	r.batchCalls++
+
+	found := make(map[int]repository.CurrencyRate)
+	for i, q := range queries {
+		var latest *dto.RateRecordDTO
+		for j, rate := range r.rates {
+			if rate.BaseCurrency != q.BaseCurrency || rate.TargetCurrency != q.TargetCurrency {
+				continue
+			}
+			if rate.Date.After(q.Date) || !rate.Date.After(q.Date.AddDate(0, 0, -lookbackDays)) {
+				continue
+			}
+			if latest == nil || rate.Date.After(latest.Date) {
+				latest = &r.rates[j]
+			}
+		}
+		if latest != nil {
+			found[i] = repository.CurrencyRate{Date: latest.Date, Rate: latest.Value, FetchedAt: latest.Date.Add(time.Hour), Provider: latest.Provider}
+		}
+	}
+	return found, nil
+}
+
+func truncate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func day(d int) time.Time {
+	return time.Date(2025, 1, d, 0, 0, 0, 0, time.UTC)
+}
+
+func newTestService(rates ...dto.RateRecordDTO) *Currency {
+	return NewCurrency(config.RatesConfig{}, &memoryRepository{rates: rates}, stubProvider{}, nil, nil, nil, slog.Default())
+}
+
+func rate(date time.Time, base, target, value string) dto.RateRecordDTO {
+	return dto.RateRecordDTO{Date: date, BaseCurrency: base, TargetCurrency: target, Value: decimal.RequireFromString(value)}
+}
+
+func TestConvert_UsesLatestRateOnOrBeforeAsOf(t *testing.T) {
+	svc := newTestService(
+		rate(day(10), "EUR", "USD", "1.0301"),
+		rate(day(13), "EUR", "USD", "1.0245"),
+		rate(day(16), "EUR", "USD", "1.0299"),
+	)
+
+	// 15 января — без фиксинга, берётся 13-е
+	conversion, err := svc.Convert(context.Background(), &dto.ConvertRequestDTO{
+		Amount: decimal.RequireFromString("100.125"),
+		From:   "eur",
+		To:     "usd",
+		AsOf:   day(15),
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "EUR", conversion.From)
+	assert.Equal(t, "USD", conversion.To)
+	assert.Equal(t, "1.0245", conversion.Rate.String())
+	assert.Equal(t, day(13), conversion.RateDate)
+	assert.Equal(t, "102.5780625", conversion.UnroundedAmount.String())
+	assert.Equal(t, "102.58", conversion.Amount.String())
+	assert.Equal(t, int32(2), conversion.MinorUnits)
+	assert.Equal(t, dto.RoundingHalfAwayFromZero, conversion.RoundingMode)
+}
+
+func TestConvert_MinorUnits(t *testing.T) {
+	svc := newTestService(
+		rate(day(15), "EUR", "JPY", "162.5"),
+		rate(day(15), "EUR", "KWD", "0.3171"),
+	)
+
+	jpy, err := svc.Convert(context.Background(), &dto.ConvertRequestDTO{
+		Amount: decimal.RequireFromString("1.01"), From: "EUR", To: "JPY", AsOf: day(15),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int32(0), jpy.MinorUnits)
+	assert.Equal(t, "164", jpy.Amount.String())
+
+	kwd, err := svc.Convert(context.Background(), &dto.ConvertRequestDTO{
+		Amount: decimal.RequireFromString("10.0005"), From: "EUR", To: "KWD", AsOf: day(15),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), kwd.MinorUnits)
+	assert.Equal(t, "3.171", kwd.Amount.String())
+}
+
+func TestConvert_NoMinorUnits(t *testing.T) {
+	svc := newTestService(rate(day(15), "USD", "XAU", "0.000425"))
+
+	xau, err := svc.Convert(context.Background(), &dto.ConvertRequestDTO{
+		Amount: decimal.RequireFromString("100"), From: "USD", To: "XAU", AsOf: day(15),
+	})
+
+	require.NoError(t, err)
+	// У золота нет минорных единиц, сумма не округляется до целых унций
+	assert.Equal(t, "0.0425", xau.Amount.String())
+	assert.Equal(t, iso4217.NoMinorUnits, xau.MinorUnits)
+	assert.Equal(t, dto.RoundingNone, xau.RoundingMode)
+	assert.Equal(t, "0.0425", xau.ToProtobuf().Amount)
+}
+
+func TestConvert_DefaultsToNow(t *testing.T) {
+	svc := newTestService(
+		rate(day(13), "EUR", "USD", "1.0245"),
+		rate(day(16), "EUR", "USD", "1.0299"),
+	)
+	svc.now = func() time.Time { return day(16).Add(17 * time.Hour) }
+
+	conversion, err := svc.Convert(context.Background(), &dto.ConvertRequestDTO{
+		Amount: decimal.RequireFromString("100"), From: "EUR", To: "USD",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, day(16), conversion.FixingDate)
+	assert.Equal(t, day(16), conversion.RateDate)
+	assert.Equal(t, "102.99", conversion.Amount.String())
+}
+
+func TestConvert_SameCurrency(t *testing.T) {
+	svc := newTestService()
+
+	conversion, err := svc.Convert(context.Background(), &dto.ConvertRequestDTO{
+		Amount: decimal.RequireFromString("10.005"), From: "USD", To: "USD", AsOf: day(15),
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "1", conversion.Rate.String())
+	assert.Equal(t, "10.01", conversion.Amount.String())
+}
+
+func TestConvert_Errors(t *testing.T) {
+	svc := newTestService(rate(day(1), "EUR", "USD", "1.03"))
+
+	_, err := svc.Convert(context.Background(), &dto.ConvertRequestDTO{
+		Amount: decimal.NewFromInt(1), From: "EUR", To: "ABC", AsOf: day(15),
+	})
+	assert.ErrorIs(t, err, ErrUnknownCurrency)
+
+	// Фиксинг старше окна поиска не используется
+	_, err = svc.Convert(context.Background(), &dto.ConvertRequestDTO{
+		Amount: decimal.NewFromInt(1), From: "EUR", To: "USD", AsOf: day(31),
+	})
+	assert.ErrorIs(t, err, ErrRateNotFound)
+}
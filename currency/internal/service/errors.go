@@ -0,0 +1,10 @@
+package service
+
+import "errors"
+
+var (
+	ErrRateNotFound    = errors.New("rate not found")
+	ErrUnknownCurrency = errors.New("unknown currency")
+	// ErrJobRunsUnavailable is returned when the service has no job run history.
+	ErrJobRunsUnavailable = errors.New("job run history is not available")
+)
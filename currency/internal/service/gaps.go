@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"my-currency-service/currency/internal/calendar"
+	"my-currency-service/currency/internal/clients/currency"
+	"my-currency-service/currency/internal/dto"
+	"my-currency-service/currency/internal/iso4217"
+	"strings"
+)
+
+// Период проверки пропусков по умолчанию
+const DefaultGapLookbackDays = 30
+
+// FindGaps returns the publishing days of the pair in the requested period
+// that have no stored observation. The period defaults to the last
+// DefaultGapLookbackDays days before today: today's fixing may not be
+// published yet.
+func (s *Currency) FindGaps(ctx context.Context, query *dto.GapQueryDTO) (*dto.GapReportDTO, error) {
+	base := strings.ToUpper(query.BaseCurrency)
+	target := strings.ToUpper(query.TargetCurrency)
+	for _, code := range []string{base, target} {
+		if !iso4217.Valid(code) {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownCurrency, code)
+		}
+	}
+
+	provider := strings.ToLower(query.Provider)
+	if provider == "" {
+		provider = currency.PrimaryName(s.provider)
+	}
+
+	dateTo := truncateDay(query.DateTo)
+	if query.DateTo.IsZero() {
+		dateTo = truncateDay(s.now().UTC()).AddDate(0, 0, -1)
+	}
+	dateFrom := truncateDay(query.DateFrom)
+	if query.DateFrom.IsZero() {
+		dateFrom = dateTo.AddDate(0, 0, -DefaultGapLookbackDays)
+	}
+	if dateFrom.After(dateTo) {
+		return nil, fmt.Errorf("DateFrom %s is after DateTo %s", dateFrom, dateTo)
+	}
+
+	stored, err := s.currencyRepo.FindInInterval(ctx, &dto.CurrencyRequestDTO{
+		BaseCurrency:   base,
+		TargetCurrency: target,
+		DateFrom:       dateFrom,
+		DateTo:         dateTo,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stored rates: %w", err)
+	}
+
+	have := make(map[string]bool, len(stored))
+	for _, rate := range stored {
+		have[rate.Date.Format("2006-01-02")] = true
+	}
+
+	expected := calendar.PublishingDays(s.calendars.ForProvider(provider), dateFrom, dateTo)
+	report := &dto.GapReportDTO{
+		Provider:       provider,
+		BaseCurrency:   base,
+		TargetCurrency: target,
+		DateFrom:       dateFrom,
+		DateTo:         dateTo,
+		ExpectedDays:   len(expected),
+	}
+	for _, day := range expected {
+		if !have[day.Format("2006-01-02")] {
+			report.Missing = append(report.Missing, day)
+		}
+	}
+
+	return report, nil
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"my-currency-service/currency/internal/calendar"
+	"my-currency-service/currency/internal/config"
+	"my-currency-service/currency/internal/dto"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindGaps(t *testing.T) {
+	svc := newTestService(
+		rate(day(2), "EUR", "USD", "1.03"),
+		rate(day(6), "EUR", "USD", "1.04"),
+		rate(day(8), "EUR", "GBP", "0.83"),
+	)
+
+	report, err := svc.FindGaps(context.Background(), &dto.GapQueryDTO{
+		Provider:       "ecb",
+		BaseCurrency:   "eur",
+		TargetCurrency: "usd",
+		DateFrom:       day(2),
+		DateTo:         day(8),
+	})
+	require.NoError(t, err)
+
+	// 4 и 5 января — выходные
+	assert.Equal(t, 5, report.ExpectedDays)
+	assert.Equal(t, []time.Time{day(3), day(7), day(8)}, report.Missing)
+	assert.Equal(t, "EUR", report.BaseCurrency)
+	assert.Equal(t, "ecb", report.Provider)
+}
+
+func TestFindGaps_Defaults(t *testing.T) {
+	svc := NewCurrency(config.RatesConfig{}, &memoryRepository{}, stubProvider{}, nil, nil, nil, slog.Default())
+	svc.now = func() time.Time { return day(31).Add(10 * time.Hour) }
+
+	report, err := svc.FindGaps(context.Background(), &dto.GapQueryDTO{BaseCurrency: "EUR", TargetCurrency: "USD"})
+	require.NoError(t, err)
+
+	// Календарь провайдера сервиса, период — 30 дней до вчерашнего
+	assert.Equal(t, "stub", report.Provider)
+	assert.Equal(t, day(30), report.DateTo)
+	assert.Equal(t, day(30).AddDate(0, 0, -DefaultGapLookbackDays), report.DateFrom)
+	assert.Len(t, report.Missing, report.ExpectedDays)
+}
+
+func TestFindGaps_Invalid(t *testing.T) {
+	svc := newTestService()
+
+	_, err := svc.FindGaps(context.Background(), &dto.GapQueryDTO{Provider: "ecb", BaseCurrency: "EUR", TargetCurrency: "ABC"})
+	assert.ErrorIs(t, err, ErrUnknownCurrency)
+
+	_, err = svc.FindGaps(context.Background(), &dto.GapQueryDTO{
+		Provider: "ecb", BaseCurrency: "EUR", TargetCurrency: "USD", DateFrom: day(9), DateTo: day(8),
+	})
+	assert.Error(t, err)
+}
+
+func TestFindGaps_Holidays(t *testing.T) {
+	svc := newTestService(rate(day(2), "EUR", "USD", "1.03"))
+
+	// 1 января — закрытие TARGET2, ЕЦБ курс не публикует
+	report, err := svc.FindGaps(context.Background(), &dto.GapQueryDTO{
+		Provider: "ecb", BaseCurrency: "EUR", TargetCurrency: "USD", DateFrom: day(1), DateTo: day(3),
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, report.ExpectedDays)
+	assert.Equal(t, []time.Time{day(3)}, report.Missing)
+}
+
+func TestFixingDate(t *testing.T) {
+	calendars, err := calendar.New(config.CalendarConfig{Providers: map[string]string{"stub": "target2"}})
+	require.NoError(t, err)
+	svc := NewCurrency(config.RatesConfig{}, &memoryRepository{}, stubProvider{}, nil, calendars, nil, slog.Default())
+
+	// Воскресенье после Страстной пятницы: действует фиксинг четверга
+	fixing, ok := svc.FixingDate(time.Date(2025, 4, 20, 15, 0, 0, 0, time.UTC))
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2025, 4, 17, 0, 0, 0, 0, time.UTC), fixing)
+
+	fixing, ok = svc.FixingDate(time.Date(2025, 4, 22, 15, 0, 0, 0, time.UTC))
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2025, 4, 22, 0, 0, 0, 0, time.UTC), fixing)
+}
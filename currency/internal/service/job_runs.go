@@ -0,0 +1,42 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"my-currency-service/currency/internal/dto"
+	"strings"
+)
+
+// Размер ответа GetJobRuns
+const (
+	DefaultJobRunsLimit = 100
+	MaxJobRunsLimit     = 1000
+)
+
+// ProviderName returns the name of the rate provider of the service.
+func (s *Currency) ProviderName() string {
+	return s.provider.Name()
+}
+
+// GetJobRuns returns the recorded runs of the worker jobs matching query,
+// newest first.
+func (s *Currency) GetJobRuns(ctx context.Context, query *dto.JobRunQueryDTO) ([]dto.JobRunDTO, error) {
+	if s.jobRuns == nil {
+		return nil, ErrJobRunsUnavailable
+	}
+
+	q := *query
+	q.BaseCurrency = strings.ToUpper(q.BaseCurrency)
+	q.TargetCurrency = strings.ToUpper(q.TargetCurrency)
+	q.Status = strings.ToLower(q.Status)
+	if q.Limit <= 0 {
+		q.Limit = DefaultJobRunsLimit
+	}
+	q.Limit = min(q.Limit, MaxJobRunsLimit)
+
+	runs, err := s.jobRuns.FindJobRuns(ctx, &q)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job runs: %w", err)
+	}
+	return runs, nil
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"my-currency-service/currency/internal/config"
+	"my-currency-service/currency/internal/dto"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// jobRunRepository запоминает последний запрос к истории запусков
+type jobRunRepository struct {
+	runs  []dto.JobRunDTO
+	query dto.JobRunQueryDTO
+}
+
+func (r *jobRunRepository) SaveJobRun(_ context.Context, run dto.JobRunDTO) error {
+	r.runs = append(r.runs, run)
+	return nil
+}
+
+func (r *jobRunRepository) FindJobRuns(_ context.Context, query *dto.JobRunQueryDTO) ([]dto.JobRunDTO, error) {
+	r.query = *query
+	return r.runs, nil
+}
+
+func (r *jobRunRepository) DeleteJobRunsBefore(context.Context, time.Time) (int64, error) {
+	return 0, nil
+}
+
+func TestGetJobRuns(t *testing.T) {
+	repo := &jobRunRepository{runs: []dto.JobRunDTO{{ID: 1, Job: "ecb-eur", Status: dto.JobRunSuccess}}}
+	svc := NewCurrency(config.RatesConfig{}, &memoryRepository{}, stubProvider{}, nil, nil, repo, slog.Default())
+
+	runs, err := svc.GetJobRuns(context.Background(), &dto.JobRunQueryDTO{
+		Job: "ecb-eur", BaseCurrency: "eur", TargetCurrency: "usd", Status: "SUCCESS",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, repo.runs, runs)
+	assert.Equal(t, dto.JobRunQueryDTO{
+		Job: "ecb-eur", BaseCurrency: "EUR", TargetCurrency: "USD", Status: dto.JobRunSuccess, Limit: DefaultJobRunsLimit,
+	}, repo.query)
+
+	_, err = svc.GetJobRuns(context.Background(), &dto.JobRunQueryDTO{Limit: 5000})
+	require.NoError(t, err)
+	assert.Equal(t, MaxJobRunsLimit, repo.query.Limit)
+}
+
+func TestGetJobRuns_NoHistory(t *testing.T) {
+	svc := newTestService()
+
+	_, err := svc.GetJobRuns(context.Background(), &dto.JobRunQueryDTO{})
+	assert.ErrorIs(t, err, ErrJobRunsUnavailable)
+}
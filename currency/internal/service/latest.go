@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"my-currency-service/currency/internal/dto"
+	"my-currency-service/currency/internal/repository"
+	"strings"
+	"time"
+)
+
+// LatestRate is the newest observation of a pair with its freshness.
+type LatestRate struct {
+	repository.CurrencyRate
+
+	// Age is the time passed since the observation date.
+	Age    time.Duration
+	MaxAge time.Duration
+	// FixingDate is the latest fixing the provider calendar expects by now.
+	FixingDate time.Time
+	// Stale is set when a fixing due more than MaxAge ago is missing.
+	Stale bool
+}
+
+// GetLatestRate returns the newest rate of the pair. Pairs that are not
+// stored directly are derived the same way as in GetCurrencyRatesInInterval.
+func (s *Currency) GetLatestRate(ctx context.Context, reqDTO *dto.CurrencyRequestDTO) (*LatestRate, error) {
+	base := strings.ToUpper(reqDTO.BaseCurrency)
+	target := strings.ToUpper(reqDTO.TargetCurrency)
+
+	rate, err := s.findLatest(ctx, base, target)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, fmt.Errorf("%w: latest %s/%s", ErrRateNotFound, base, target)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch latest currency rate: %w", err)
+	}
+
+	maxAge := s.maxAge
+	if pairMaxAge, ok := s.pairMaxAge[base+"/"+target]; ok {
+		maxAge = pairMaxAge
+	}
+
+	now := s.now()
+	age := now.Sub(rate.Date)
+	if age < 0 {
+		age = 0
+	}
+
+	// Праздники не делают курс устаревшим: сравнение идёт с фиксингом,
+	// который по календарю провайдера должен был выйти maxAge назад
+	stale := age > maxAge
+	if due, ok := s.FixingDate(now.Add(-maxAge)); ok {
+		stale = rate.Date.Before(due)
+	}
+
+	return &LatestRate{
+		CurrencyRate: *rate,
+		Age:          age,
+		MaxAge:       maxAge,
+		FixingDate:   s.applicableDate(now),
+		Stale:        stale,
+	}, nil
+}
+
+func (s *Currency) findLatest(ctx context.Context, base, target string) (*repository.CurrencyRate, error) {
+	rate, err := s.currencyRepo.FindLatest(ctx, base, target)
+	if !errors.Is(err, repository.ErrNotFound) {
+		return rate, err
+	}
+
+	// Для производного курса берутся даты последних наблюдений каждой ноги,
+	// и курс выводится на отрезке между ними
+	var dates []time.Time
+	inverse, err := s.currencyRepo.FindLatest(ctx, target, base)
+	switch {
+	case err == nil:
+		dates = []time.Time{inverse.Date}
+	case !errors.Is(err, repository.ErrNotFound):
+		return nil, err
+	case s.pivotCurrency == base || s.pivotCurrency == target:
+		return nil, err
+	default:
+		first, err := s.findLatestLeg(ctx, base, s.pivotCurrency)
+		if err != nil {
+			return nil, err
+		}
+		second, err := s.findLatestLeg(ctx, s.pivotCurrency, target)
+		if err != nil {
+			return nil, err
+		}
+		dates = []time.Time{first.Date, second.Date}
+	}
+
+	dateFrom, dateTo := dates[0], dates[len(dates)-1]
+	if dateFrom.After(dateTo) {
+		dateFrom, dateTo = dateTo, dateFrom
+	}
+
+	rates, err := s.deriveRates(ctx, &dto.CurrencyRequestDTO{
+		BaseCurrency:   base,
+		TargetCurrency: target,
+		DateFrom:       dateFrom,
+		DateTo:         dateTo,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(rates) == 0 {
+		return nil, fmt.Errorf("latest rate %s/%s: %w", base, target, repository.ErrNotFound)
+	}
+
+	return &rates[len(rates)-1], nil
+}
+
+// findLatestLeg returns the newest base->target observation stored in either direction.
+func (s *Currency) findLatestLeg(ctx context.Context, base, target string) (*repository.CurrencyRate, error) {
+	rate, err := s.currencyRepo.FindLatest(ctx, base, target)
+	if !errors.Is(err, repository.ErrNotFound) {
+		return rate, err
+	}
+
+	return s.currencyRepo.FindLatest(ctx, target, base)
+}
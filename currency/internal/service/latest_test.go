@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"my-currency-service/currency/internal/config"
+	"my-currency-service/currency/internal/dto"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newLatestTestService(now time.Time, cfg config.RatesConfig, rates ...dto.RateRecordDTO) *Currency {
+	svc := NewCurrency(cfg, &memoryRepository{rates: rates}, stubProvider{}, nil, nil, nil, slog.Default())
+	svc.now = func() time.Time { return now }
+	return svc
+}
+
+func TestGetLatestRate_Fresh(t *testing.T) {
+	svc := newLatestTestService(day(16).Add(12*time.Hour), config.RatesConfig{},
+		rate(day(14), "EUR", "USD", "1.01"),
+		rate(day(16), "EUR", "USD", "1.03"),
+		rate(day(15), "EUR", "USD", "1.02"),
+	)
+
+	latest, err := svc.GetLatestRate(context.Background(), &dto.CurrencyRequestDTO{BaseCurrency: "eur", TargetCurrency: "usd"})
+
+	require.NoError(t, err)
+	assert.Equal(t, day(16), latest.Date)
+	assert.Equal(t, "1.03", latest.Rate.String())
+	assert.Equal(t, day(16).Add(time.Hour), latest.FetchedAt)
+	assert.Equal(t, 12*time.Hour, latest.Age)
+	assert.Equal(t, DefaultMaxAge, latest.MaxAge)
+	assert.False(t, latest.Stale)
+}
+
+func TestGetLatestRate_PairThreshold(t *testing.T) {
+	svc := newLatestTestService(day(18), config.RatesConfig{
+		MaxAge:     240 * time.Hour,
+		PairMaxAge: map[string]time.Duration{"eur/usd": 24 * time.Hour},
+	},
+		rate(day(16), "EUR", "USD", "1.03"),
+		rate(day(16), "EUR", "GBP", "0.83"),
+	)
+
+	usd, err := svc.GetLatestRate(context.Background(), &dto.CurrencyRequestDTO{BaseCurrency: "EUR", TargetCurrency: "USD"})
+	require.NoError(t, err)
+	assert.Equal(t, 48*time.Hour, usd.Age)
+	assert.Equal(t, 24*time.Hour, usd.MaxAge)
+	assert.True(t, usd.Stale)
+
+	gbp, err := svc.GetLatestRate(context.Background(), &dto.CurrencyRequestDTO{BaseCurrency: "EUR", TargetCurrency: "GBP"})
+	require.NoError(t, err)
+	assert.Equal(t, 240*time.Hour, gbp.MaxAge)
+	assert.False(t, gbp.Stale)
+}
+
+func TestGetLatestRate_Derived(t *testing.T) {
+	svc := newLatestTestService(day(17), config.RatesConfig{},
+		rate(day(15), "EUR", "GBP", "0.8"),
+		rate(day(16), "EUR", "GBP", "0.75"),
+		rate(day(15), "EUR", "JPY", "160"),
+		rate(day(16), "EUR", "JPY", "150"),
+		rate(day(16), "EUR", "USD", "1.25"),
+	)
+
+	cross, err := svc.GetLatestRate(context.Background(), &dto.CurrencyRequestDTO{BaseCurrency: "GBP", TargetCurrency: "JPY"})
+	require.NoError(t, err)
+	assert.True(t, cross.Derived)
+	assert.Equal(t, day(16), cross.Date)
+	assert.Equal(t, "200", cross.Rate.String())
+
+	inverse, err := svc.GetLatestRate(context.Background(), &dto.CurrencyRequestDTO{BaseCurrency: "USD", TargetCurrency: "EUR"})
+	require.NoError(t, err)
+	assert.True(t, inverse.Derived)
+	assert.Equal(t, "0.8", inverse.Rate.String())
+}
+
+func TestGetLatestRate_NotFound(t *testing.T) {
+	svc := newLatestTestService(day(17), config.RatesConfig{}, rate(day(16), "EUR", "GBP", "0.75"))
+
+	_, err := svc.GetLatestRate(context.Background(), &dto.CurrencyRequestDTO{BaseCurrency: "GBP", TargetCurrency: "JPY"})
+
+	assert.ErrorIs(t, err, ErrRateNotFound)
+}
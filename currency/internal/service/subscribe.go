@@ -0,0 +1,35 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"my-currency-service/currency/internal/dto"
+	"my-currency-service/currency/internal/events"
+	"my-currency-service/currency/internal/iso4217"
+	"strings"
+)
+
+// Сколько непрочитанных событий держать для подписчика, прежде чем
+// отключить его как медленного
+const subscriptionBuffer = 256
+
+// SubscribeRates subscribes to observations of pairs as they are saved.
+// Only stored pairs are streamed: inverse and cross rates are not derived.
+// The caller must close the subscription.
+func (s *Currency) SubscribeRates(_ context.Context, pairs []dto.CurrencyPairDTO) (*events.Subscription, error) {
+	normalized := make([]dto.CurrencyPairDTO, len(pairs))
+	for i, pair := range pairs {
+		base := strings.ToUpper(pair.BaseCurrency)
+		target := strings.ToUpper(pair.TargetCurrency)
+
+		for _, code := range []string{base, target} {
+			if !iso4217.Valid(code) {
+				return nil, fmt.Errorf("%w: %s", ErrUnknownCurrency, code)
+			}
+		}
+
+		normalized[i] = dto.CurrencyPairDTO{BaseCurrency: base, TargetCurrency: target}
+	}
+
+	return s.events.Subscribe(normalized, subscriptionBuffer), nil
+}
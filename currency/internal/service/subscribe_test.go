@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"my-currency-service/currency/internal/config"
+	"my-currency-service/currency/internal/dto"
+	"my-currency-service/currency/internal/events"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubProvider struct {
+	records []dto.RateRecordDTO
+}
+
+func (p stubProvider) Name() string { return "stub" }
+
+func (p stubProvider) FetchRates(context.Context, *dto.CurrencyRequestDTO) ([]dto.RateRecordDTO, error) {
+	return append([]dto.RateRecordDTO(nil), p.records...), nil
+}
+
+// recordingProvider запоминает запрос, с которым его вызвали
+type recordingProvider struct {
+	stubProvider
+	req dto.CurrencyRequestDTO
+}
+
+func (p *recordingProvider) FetchRates(ctx context.Context, req *dto.CurrencyRequestDTO) ([]dto.RateRecordDTO, error) {
+	p.req = *req
+	return p.stubProvider.FetchRates(ctx, req)
+}
+
+func TestSubscribeRates_ReceivesSavedRates(t *testing.T) {
+	bus := events.NewBus(nil, slog.Default())
+	svc := NewCurrency(config.RatesConfig{}, &memoryRepository{}, stubProvider{records: []dto.RateRecordDTO{
+		rate(day(15), "eur", "usd", "1.03"),
+		rate(day(15), "EUR", "GBP", "0.83"),
+	}}, bus, nil, nil, slog.Default())
+
+	sub, err := svc.SubscribeRates(context.Background(), []dto.CurrencyPairDTO{{BaseCurrency: "eur", TargetCurrency: "usd"}})
+	require.NoError(t, err)
+	defer sub.Close()
+
+	result, err := svc.FetchAndSaveCurrencyRates(context.Background(), &dto.CurrencyRequestDTO{
+		BaseCurrency: "EUR",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, dto.FetchResultDTO{Rows: 2, Provider: "stub"}, result)
+
+	require.Len(t, sub.Events(), 1)
+	event := <-sub.Events()
+	assert.Equal(t, "EUR", event.BaseCurrency)
+	assert.Equal(t, "USD", event.TargetCurrency)
+	assert.Equal(t, "1.03", event.Value.String())
+}
+
+func TestFetchAndSaveCurrencyRates_RecordsProvenance(t *testing.T) {
+	repo := &memoryRepository{}
+	fetched := rate(day(15), "EUR", "USD", "1.03")
+	fetched.SeriesKey, fetched.PayloadHash = "EXR.D.USD.EUR.SP00.A", "ab12"
+	svc := NewCurrency(config.RatesConfig{}, repo, stubProvider{records: []dto.RateRecordDTO{fetched}}, nil, nil, nil, slog.Default())
+	now := time.Date(2025, 1, 16, 14, 30, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	_, err := svc.FetchAndSaveCurrencyRates(context.Background(), &dto.CurrencyRequestDTO{BaseCurrency: "EUR", TargetCurrency: "USD"})
+
+	require.NoError(t, err)
+	require.Len(t, repo.rates, 1)
+	assert.Equal(t, "stub", repo.rates[0].Provider)
+	assert.Equal(t, "EXR.D.USD.EUR.SP00.A", repo.rates[0].SeriesKey)
+	assert.Equal(t, now, repo.rates[0].FetchedAt)
+	assert.Equal(t, "ab12", repo.rates[0].PayloadHash)
+}
+
+func TestFetchAndSaveCurrencyRates_KeepsRequestedTarget(t *testing.T) {
+	repo := &memoryRepository{}
+	// Провайдер отдаёт всю таблицу, как JSON
+	svc := NewCurrency(config.RatesConfig{}, repo, stubProvider{records: []dto.RateRecordDTO{
+		rate(day(15), "eur", "gbp", "0.84"),
+		rate(day(15), "eur", "usd", "1.03"),
+		rate(day(15), "eur", "jpy", "160"),
+	}}, nil, nil, nil, slog.Default())
+
+	result, err := svc.FetchAndSaveCurrencyRates(context.Background(), &dto.CurrencyRequestDTO{BaseCurrency: "EUR", TargetCurrency: "usd"})
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Rows)
+	require.Len(t, repo.rates, 1)
+	assert.Equal(t, "USD", repo.rates[0].TargetCurrency)
+}
+
+func TestFetchAndSaveCurrencyRates_YesterdayAndToday(t *testing.T) {
+	provider := &recordingProvider{}
+	svc := NewCurrency(config.RatesConfig{}, &memoryRepository{}, provider, nil, nil, nil, slog.Default())
+	// Около полуночи по Москве, но ещё 15-е по UTC
+	svc.now = func() time.Time { return time.Date(2025, 1, 16, 2, 30, 0, 0, time.FixedZone("MSK", 3*60*60)) }
+
+	_, err := svc.FetchAndSaveCurrencyRates(context.Background(), &dto.CurrencyRequestDTO{BaseCurrency: "EUR", TargetCurrency: "USD"})
+
+	require.NoError(t, err)
+	assert.Equal(t, day(14), provider.req.DateFrom)
+	assert.Equal(t, day(15), provider.req.DateTo)
+}
+
+func TestSubscribeRates_UnknownCurrency(t *testing.T) {
+	svc := newTestService()
+
+	_, err := svc.SubscribeRates(context.Background(), []dto.CurrencyPairDTO{{BaseCurrency: "EUR", TargetCurrency: "ABC"}})
+
+	assert.ErrorIs(t, err, ErrUnknownCurrency)
+}
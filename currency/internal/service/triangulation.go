@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"my-currency-service/currency/internal/dto"
+	"my-currency-service/currency/internal/repository"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Число знаков после запятой у производных курсов: обратный курс в общем
+// случае не представим конечной десятичной дробью
+const derivedRatePrecision = 16
+
+// deriveRates answers a pair that is not stored directly: first from the
+// inverse pair (USD->EUR from EUR->USD), then as a cross rate through the
+// pivot currency (GBP->JPY from EUR->GBP and EUR->JPY).
+func (s *Currency) deriveRates(ctx context.Context, reqDTO *dto.CurrencyRequestDTO) ([]repository.CurrencyRate, error) {
+	base, target := reqDTO.BaseCurrency, reqDTO.TargetCurrency
+
+	inverse, err := s.findLegs(ctx, reqDTO, target, base, true)
+	if err != nil {
+		return nil, err
+	}
+	if len(inverse) > 0 {
+		return ratesFromLegs(inverse), nil
+	}
+
+	pivot := s.pivotCurrency
+	if pivot == base || pivot == target {
+		return nil, nil
+	}
+
+	first, err := s.findLeg(ctx, reqDTO, base, pivot)
+	if err != nil || len(first) == 0 {
+		return nil, err
+	}
+
+	second, err := s.findLeg(ctx, reqDTO, pivot, target)
+	if err != nil || len(second) == 0 {
+		return nil, err
+	}
+
+	return ratesFromLegs(first, second), nil
+}
+
+// findLeg returns base->target observations by date, stored either directly
+// or as the inverse pair.
+func (s *Currency) findLeg(ctx context.Context, reqDTO *dto.CurrencyRequestDTO, base, target string) (map[time.Time]repository.RateLeg, error) {
+	legs, err := s.findLegs(ctx, reqDTO, base, target, false)
+	if err != nil || len(legs) > 0 {
+		return legs, err
+	}
+
+	return s.findLegs(ctx, reqDTO, target, base, true)
+}
+
+// findLegs loads stored base->target observations for the request period.
+func (s *Currency) findLegs(ctx context.Context, reqDTO *dto.CurrencyRequestDTO, base, target string, inverted bool) (map[time.Time]repository.RateLeg, error) {
+	rates, err := s.currencyRepo.FindInInterval(ctx, &dto.CurrencyRequestDTO{
+		BaseCurrency:   base,
+		TargetCurrency: target,
+		DateFrom:       reqDTO.DateFrom,
+		DateTo:         reqDTO.DateTo,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	legs := make(map[time.Time]repository.RateLeg, len(rates))
+	for _, rate := range rates {
+		legs[rate.Date] = repository.RateLeg{
+			BaseCurrency:   base,
+			TargetCurrency: target,
+			Rate:           rate.Rate,
+			Inverted:       inverted,
+			FetchedAt:      rate.FetchedAt,
+			Provider:       rate.Provider,
+		}
+	}
+
+	return legs, nil
+}
+
+// ratesFromLegs joins legs by date and multiplies them along the path.
+// Dates missing in any leg are skipped. The result is sorted by date.
+func ratesFromLegs(path ...map[time.Time]repository.RateLeg) []repository.CurrencyRate {
+	var rates []repository.CurrencyRate
+
+	for date, first := range path[0] {
+		legs := []repository.RateLeg{first}
+		for _, next := range path[1:] {
+			leg, ok := next[date]
+			if !ok {
+				break
+			}
+			legs = append(legs, leg)
+		}
+		if len(legs) != len(path) {
+			continue
+		}
+
+		rate, ok := deriveRate(legs)
+		if !ok {
+			continue
+		}
+
+		// Производный курс не свежее самой старой из ног
+		fetchedAt := legs[0].FetchedAt
+		providers := []string{legs[0].Provider}
+		for _, leg := range legs[1:] {
+			if leg.FetchedAt.Before(fetchedAt) {
+				fetchedAt = leg.FetchedAt
+			}
+			if !slices.Contains(providers, leg.Provider) {
+				providers = append(providers, leg.Provider)
+			}
+		}
+
+		rates = append(rates, repository.CurrencyRate{
+			Date:      date,
+			Rate:      rate,
+			FetchedAt: fetchedAt,
+			Provider:  strings.Join(providers, ","),
+			Derived:   true,
+			Legs:      legs,
+		})
+	}
+
+	sort.Slice(rates, func(i, j int) bool {
+		return rates[i].Date.Before(rates[j].Date)
+	})
+
+	return rates
+}
+
+// deriveRate multiplies the legs, dividing once at the end to round once.
+func deriveRate(legs []repository.RateLeg) (decimal.Decimal, bool) {
+	numerator, denominator := decimal.NewFromInt(1), decimal.NewFromInt(1)
+	for _, leg := range legs {
+		if leg.Inverted {
+			denominator = denominator.Mul(leg.Rate)
+		} else {
+			numerator = numerator.Mul(leg.Rate)
+		}
+	}
+
+	if denominator.IsZero() {
+		return decimal.Decimal{}, false
+	}
+
+	return numerator.DivRound(denominator, derivedRatePrecision), true
+}
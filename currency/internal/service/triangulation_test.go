@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"my-currency-service/currency/internal/config"
+	"my-currency-service/currency/internal/dto"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func interval(base, target string) *dto.CurrencyRequestDTO {
+	return &dto.CurrencyRequestDTO{BaseCurrency: base, TargetCurrency: target, DateFrom: day(1), DateTo: day(31)}
+}
+
+func TestGetCurrencyRatesInInterval_Direct(t *testing.T) {
+	svc := newTestService(rate(day(15), "EUR", "USD", "1.0301"))
+
+	rates, err := svc.GetCurrencyRatesInInterval(context.Background(), interval("eur", "usd"))
+
+	require.NoError(t, err)
+	require.Len(t, rates, 1)
+	assert.False(t, rates[0].Derived)
+	assert.Empty(t, rates[0].Legs)
+	assert.Equal(t, "1.0301", rates[0].Rate.String())
+}
+
+func TestGetCurrencyRatesInInterval_Inverse(t *testing.T) {
+	svc := newTestService(rate(day(15), "EUR", "USD", "1.25"))
+
+	rates, err := svc.GetCurrencyRatesInInterval(context.Background(), interval("USD", "EUR"))
+
+	require.NoError(t, err)
+	require.Len(t, rates, 1)
+	assert.True(t, rates[0].Derived)
+	assert.Equal(t, "0.8", rates[0].Rate.String())
+	require.Len(t, rates[0].Legs, 1)
+	assert.Equal(t, "EUR", rates[0].Legs[0].BaseCurrency)
+	assert.Equal(t, "USD", rates[0].Legs[0].TargetCurrency)
+	assert.Equal(t, "1.25", rates[0].Legs[0].Rate.String())
+	assert.True(t, rates[0].Legs[0].Inverted)
+}
+
+func TestGetCurrencyRatesInInterval_CrossThroughPivot(t *testing.T) {
+	gbp, jpy := rate(day(15), "EUR", "GBP", "0.8"), rate(day(15), "EUR", "JPY", "160")
+	gbp.Provider, jpy.Provider = "ecb", "json"
+	svc := newTestService(
+		rate(day(14), "EUR", "GBP", "0.84"),
+		gbp,
+		jpy,
+		rate(day(16), "EUR", "JPY", "161"),
+	)
+
+	rates, err := svc.GetCurrencyRatesInInterval(context.Background(), interval("GBP", "JPY"))
+
+	require.NoError(t, err)
+	// Только дата, на которую есть обе ноги
+	require.Len(t, rates, 1)
+	assert.Equal(t, day(15), rates[0].Date)
+	assert.True(t, rates[0].Derived)
+	assert.Equal(t, "200", rates[0].Rate.String())
+	// Производный курс ссылается на провайдеров обеих ног
+	assert.Equal(t, "ecb,json", rates[0].Provider)
+
+	require.Len(t, rates[0].Legs, 2)
+	assert.Equal(t, "EUR", rates[0].Legs[0].BaseCurrency)
+	assert.Equal(t, "GBP", rates[0].Legs[0].TargetCurrency)
+	assert.True(t, rates[0].Legs[0].Inverted)
+	assert.Equal(t, "EUR", rates[0].Legs[1].BaseCurrency)
+	assert.Equal(t, "JPY", rates[0].Legs[1].TargetCurrency)
+	assert.False(t, rates[0].Legs[1].Inverted)
+}
+
+func TestGetCurrencyRatesInInterval_ConfigurablePivot(t *testing.T) {
+	// Курсы ЦБ хранятся как X->RUB
+	svc := NewCurrency(config.RatesConfig{PivotCurrency: "rub"}, &memoryRepository{rates: []dto.RateRecordDTO{
+		rate(day(15), "USD", "RUB", "90"),
+		rate(day(15), "CNY", "RUB", "12.5"),
+	}}, stubProvider{}, nil, nil, nil, slog.Default())
+
+	rates, err := svc.GetCurrencyRatesInInterval(context.Background(), interval("USD", "CNY"))
+
+	require.NoError(t, err)
+	require.Len(t, rates, 1)
+	assert.Equal(t, "7.2", rates[0].Rate.String())
+	assert.False(t, rates[0].Legs[0].Inverted)
+	assert.True(t, rates[0].Legs[1].Inverted)
+}
+
+func TestGetCurrencyRatesInInterval_NotDerivable(t *testing.T) {
+	svc := newTestService(rate(day(15), "EUR", "GBP", "0.8"))
+
+	rates, err := svc.GetCurrencyRatesInInterval(context.Background(), interval("GBP", "JPY"))
+
+	require.NoError(t, err)
+	assert.Empty(t, rates)
+}
@@ -2,88 +2,228 @@ package worker
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"my-currency-service/currency/internal/calendar"
 	"my-currency-service/currency/internal/config"
 	"my-currency-service/currency/internal/dto"
+	"strings"
 	"time"
 
 	"github.com/go-co-op/gocron"
 )
 
+const defaultJobTimeout = 30 * time.Second
+
+// Тег задачи сверки в планировщике
+const reconcileTag = "reconcile"
+
 type CurrencyService interface {
-	FetchAndSaveCurrencyRates(ctx context.Context, req *dto.CurrencyRequestDTO) error
+	FetchAndSaveCurrencyRates(ctx context.Context, req *dto.CurrencyRequestDTO) (dto.FetchResultDTO, error)
+	FindGaps(ctx context.Context, query *dto.GapQueryDTO) (*dto.GapReportDTO, error)
+	BackfillCurrencyRates(ctx context.Context, req *dto.CurrencyRequestDTO) (int, error)
+	Calendar() calendar.Calendar
+	ProviderName() string
 }
 
+// Locker elects the replica that runs a job when several replicas of the
+// worker share the database. Acquire is called before every run and
+// reports whether this replica holds the lock of the job.
+type Locker interface {
+	Acquire(ctx context.Context, name string) bool
+}
+
+// Время на захват лока задания
+const lockTimeout = 10 * time.Second
+
+// job is a configured fetch of one base currency against its targets.
+type job struct {
+	name     string
+	base     string
+	targets  []string
+	provider string
+	schedule string
+	timeout  time.Duration
+	service  CurrencyService
+	calendar calendar.Calendar
+	locker   Locker
+	history  RunRecorder
+	metrics  *Metrics
+	logger   *slog.Logger
+}
+
+// Currency runs one gocron job per entry of the worker config. A failing
+// job or pair is logged and does not affect the others. If reconciliation
+// is scheduled, missing fixings of the jobs are looked up and refetched.
 type Currency struct {
-	currencyService CurrencyService
-	cron            *gocron.Scheduler
-	schedule        string
-	baseCurrency    string
-	targetCurrency  string
-	logger          *slog.Logger
+	cron              *gocron.Scheduler
+	jobs              []*job
+	reconcileSchedule string
+	lookbackDays      int
+	locker            Locker
+	history           RunRecorder
+	retention         time.Duration
+	pruneSchedule     string
+	metrics           *Metrics
+	logger            *slog.Logger
 }
 
+// NewCurrency builds the jobs of cfg. services holds the service of every
+// provider set named in the jobs; the key is config.WorkerJobConfig.ProviderKey,
+// so "" is the default provider. A nil locker runs every job on this
+// replica; a nil history records no runs.
 func NewCurrency(
 	cfg config.WorkerConfig,
-	service CurrencyService,
+	services map[string]CurrencyService,
 	cron *gocron.Scheduler,
+	locker Locker,
+	history RunRecorder,
+	metrics *Metrics,
 	logger *slog.Logger,
-) *Currency {
-	return &Currency{
-		currencyService: service,
-		cron:            cron,
-		schedule:        cfg.Schedule,
-		baseCurrency:    cfg.CurrencyPair.BaseCurrency,
-		targetCurrency:  cfg.CurrencyPair.TargetCurrency,
-		logger:          logger,
+) (*Currency, error) {
+	if len(cfg.Jobs) == 0 {
+		return nil, errors.New("no worker jobs configured")
+	}
+
+	lookbackDays := cfg.Reconcile.LookbackDays
+	if lookbackDays <= 0 {
+		lookbackDays = defaultLookbackDays
 	}
+
+	retention := cfg.History.Retention
+	if retention <= 0 {
+		retention = defaultRetention
+	}
+	pruneSchedule := cfg.History.PruneSchedule
+	if pruneSchedule == "" {
+		pruneSchedule = defaultPruneSchedule
+	}
+
+	w := &Currency{
+		cron:              cron,
+		jobs:              make([]*job, 0, len(cfg.Jobs)),
+		reconcileSchedule: cfg.Reconcile.Schedule,
+		lookbackDays:      lookbackDays,
+		locker:            locker,
+		history:           history,
+		retention:         retention,
+		pruneSchedule:     pruneSchedule,
+		metrics:           metrics,
+		logger:            logger,
+	}
+
+	names := make(map[string]struct{}, len(cfg.Jobs))
+	for i, jobCfg := range cfg.Jobs {
+		j, err := newJob(jobCfg, services, logger)
+		if err != nil {
+			return nil, fmt.Errorf("worker job %d: %w", i, err)
+		}
+		if _, ok := names[j.name]; ok {
+			return nil, fmt.Errorf("worker job %d: duplicate name %q", i, j.name)
+		}
+		names[j.name] = struct{}{}
+
+		j.locker, j.history, j.metrics = locker, history, metrics
+		w.jobs = append(w.jobs, j)
+	}
+
+	return w, nil
 }
 
-func (w *Currency) StartFetchingCurrencyRates() error {
-	go func() {
-		ctx, cancel := context.WithTimeout(context.Background(), time.Second*5) // TODO: move to config
-		defer cancel()
-
-		currencyData := dto.CurrencyRequestDTO{
-			BaseCurrency:   w.baseCurrency,
-			TargetCurrency: w.targetCurrency,
-			DateFrom:       time.Now().UTC(),
-			DateTo:         time.Now().UTC(),
+func newJob(cfg config.WorkerJobConfig, services map[string]CurrencyService, logger *slog.Logger) (*job, error) {
+	base := strings.ToUpper(strings.TrimSpace(cfg.BaseCurrency))
+	if base == "" {
+		return nil, errors.New("base_currency is required")
+	}
+	if len(cfg.TargetCurrencies) == 0 {
+		return nil, errors.New("target_currencies is required")
+	}
+	if cfg.Schedule == "" {
+		return nil, errors.New("schedule is required")
+	}
+
+	if cfg.Provider != "" && len(cfg.Providers) > 0 {
+		return nil, errors.New("provider and providers are mutually exclusive")
+	}
+
+	service, ok := services[cfg.ProviderKey()]
+	if !ok {
+		return nil, fmt.Errorf("no service for provider %q", cfg.ProviderKey())
+	}
+
+	targets := make([]string, 0, len(cfg.TargetCurrencies))
+	for _, target := range cfg.TargetCurrencies {
+		targets = append(targets, strings.ToUpper(strings.TrimSpace(target)))
+	}
+
+	name := cfg.Name
+	if name == "" {
+		provider := cfg.ProviderKey()
+		if provider == "" {
+			provider = "default"
 		}
+		name = provider + ":" + base
+	}
 
-		err := w.currencyService.FetchAndSaveCurrencyRates(ctx, &currencyData)
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = defaultJobTimeout
+	}
 
+	return &job{
+		name:     name,
+		base:     base,
+		targets:  targets,
+		schedule: cfg.Schedule,
+		timeout:  timeout,
+		service:  service,
+		provider: service.ProviderName(),
+		calendar: service.Calendar(),
+		logger: logger.With(
+			slog.String("job", name),
+			slog.String("schedule", cfg.Schedule),
+		),
+	}, nil
+}
+
+// StartFetchingCurrencyRates registers the jobs and the reconciliation,
+// runs each of them once right away and starts the scheduler.
+func (w *Currency) StartFetchingCurrencyRates() error {
+	for _, j := range w.jobs {
+		// SingletonMode: медленный запуск не накладывается на следующий
+		_, err := w.cron.Cron(j.schedule).SingletonMode().Tag(j.name).Do(j.run, "schedule")
 		if err != nil {
-			w.logger.Error("Failed to fetch currency rate immediately on startup",
-				slog.Time("timestamp", time.Now().UTC()),
-				slog.Any("error", err))
+			return fmt.Errorf("cron.Do %s: %w", j.name, err)
 		}
+	}
 
-	}()
-
-	_, err := w.cron.Cron(w.schedule).Do(func() {
-		ctx, cancel := context.WithTimeout(context.Background(), time.Second*5) //TODO: move to config
-		defer cancel()
+	if w.reconcileSchedule != "" {
+		_, err := w.cron.Cron(w.reconcileSchedule).SingletonMode().Tag(reconcileTag).Do(w.reconcile, "schedule")
+		if err != nil {
+			return fmt.Errorf("cron.Do %s: %w", reconcileTag, err)
+		}
+	}
 
-		err := w.currencyService.FetchAndSaveCurrencyRates(ctx, &dto.CurrencyRequestDTO{
-			BaseCurrency:   w.baseCurrency,
-			TargetCurrency: w.targetCurrency,
-		})
+	if w.history != nil {
+		_, err := w.cron.Cron(w.pruneSchedule).SingletonMode().Tag(pruneTag).Do(w.prune, "schedule")
 		if err != nil {
-			w.logger.Error("Failed to fetch currency rate on schedule",
-				slog.Time("timestamp", time.Now().UTC()),
-				slog.Any("error", err),
-				slog.String("schedule", w.schedule))
+			return fmt.Errorf("cron.Do %s: %w", pruneTag, err)
 		}
-	})
+	}
 
-	if err != nil {
-		return fmt.Errorf("cron.Do: %w", err)
+	for _, j := range w.jobs {
+		go j.run("startup")
+	}
+	if w.reconcileSchedule != "" {
+		// Пропуски за время простоя закрываются сразу после старта
+		go w.reconcile("startup")
 	}
 
 	w.cron.StartAsync()
 
+	w.logger.Info("currency worker started", slog.Int("jobs", len(w.jobs)))
+
 	return nil
 }
 
@@ -91,3 +231,67 @@ func (w *Currency) Stop() error {
 	w.cron.Stop()
 	return nil
 }
+
+// run fetches every target of the job. Each pair gets its own timeout so
+// that a slow pair does not eat the time of the next one. The run is
+// skipped if the fetched period, yesterday and today, has no publishing day,
+// or if another replica holds the lock of the job.
+func (j *job) run(trigger string) {
+	start := time.Now()
+
+	if today := start.UTC(); len(calendar.PublishingDays(j.calendar, today.AddDate(0, 0, -1), today)) == 0 {
+		j.logger.Info("currency job skipped, no publishing day",
+			slog.String("trigger", trigger))
+		return
+	}
+
+	if !acquire(j.locker, j.name) {
+		j.logger.Debug("currency job skipped, lock is held by another replica",
+			slog.String("trigger", trigger))
+		return
+	}
+
+	var failed int
+	for _, target := range j.targets {
+		pairStart := time.Now()
+		result, err := j.fetch(target)
+		j.record(trigger, target, pairStart, result, err)
+
+		if err != nil {
+			failed++
+			j.logger.Error("Failed to fetch currency rate",
+				slog.String("trigger", trigger),
+				slog.String("base_currency", j.base),
+				slog.String("target_currency", target),
+				slog.Any("error", err))
+		}
+	}
+
+	j.logger.Info("currency job finished",
+		slog.String("trigger", trigger),
+		slog.Int("pairs", len(j.targets)),
+		slog.Int("failed", failed),
+		slog.Duration("duration", time.Since(start)))
+}
+
+// acquire reports whether this replica may run the job name.
+func acquire(locker Locker, name string) bool {
+	if locker == nil {
+		return true
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), lockTimeout)
+	defer cancel()
+
+	return locker.Acquire(ctx, name)
+}
+
+func (j *job) fetch(target string) (dto.FetchResultDTO, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), j.timeout)
+	defer cancel()
+
+	return j.service.FetchAndSaveCurrencyRates(ctx, &dto.CurrencyRequestDTO{
+		BaseCurrency:   j.base,
+		TargetCurrency: target,
+	})
+}
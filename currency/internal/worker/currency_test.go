@@ -0,0 +1,201 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"my-currency-service/currency/internal/calendar"
+	"my-currency-service/currency/internal/config"
+	"my-currency-service/currency/internal/dto"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-co-op/gocron"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingService запоминает запрошенные пары и падает на заданных целевых валютах
+type recordingService struct {
+	mu       sync.Mutex
+	pairs    []string
+	failOn   map[string]bool
+	deadline time.Duration
+	// Пустой — курсы публикуются каждый день
+	calendar calendar.Calendar
+	// Провайдер, приславший курсы; пустой — ProviderName
+	supplier string
+}
+
+func (s *recordingService) FetchAndSaveCurrencyRates(ctx context.Context, req *dto.CurrencyRequestDTO) (dto.FetchResultDTO, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		s.deadline = time.Until(deadline).Round(time.Second)
+	}
+	s.pairs = append(s.pairs, req.BaseCurrency+"/"+req.TargetCurrency)
+	if s.failOn[req.TargetCurrency] {
+		return dto.FetchResultDTO{}, errors.New("provider is down")
+	}
+	return dto.FetchResultDTO{Rows: 2, Provider: s.supplier}, nil
+}
+
+func (s *recordingService) FindGaps(context.Context, *dto.GapQueryDTO) (*dto.GapReportDTO, error) {
+	return &dto.GapReportDTO{}, nil
+}
+
+func (s *recordingService) BackfillCurrencyRates(context.Context, *dto.CurrencyRequestDTO) (int, error) {
+	return 0, nil
+}
+
+func (s *recordingService) ProviderName() string { return "stub" }
+
+func (s *recordingService) Calendar() calendar.Calendar {
+	if s.calendar == nil {
+		return calendar.EveryDay
+	}
+	return s.calendar
+}
+
+func (s *recordingService) requested() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.pairs...)
+}
+
+func TestCurrency_RunsEveryJob(t *testing.T) {
+	ecb := &recordingService{failOn: map[string]bool{"USD": true}}
+	cbr := &recordingService{}
+
+	w, err := NewCurrency(config.WorkerConfig{Jobs: []config.WorkerJobConfig{
+		{BaseCurrency: "eur", TargetCurrencies: []string{"usd", "gbp"}, Schedule: "@daily"},
+		{Name: "cbr-rub", Provider: "cbr", BaseCurrency: "USD", TargetCurrencies: []string{"RUB"}, Schedule: "0 12 * * 1-5", Timeout: time.Minute},
+	}}, map[string]CurrencyService{"": ecb, "cbr": cbr}, gocron.NewScheduler(time.UTC), nil, nil, nil, slog.Default())
+	require.NoError(t, err)
+
+	require.NoError(t, w.StartFetchingCurrencyRates())
+	defer func() { _ = w.Stop() }()
+
+	// Ошибка по USD не мешает загрузить GBP
+	assert.Eventually(t, func() bool { return len(ecb.requested()) == 2 && len(cbr.requested()) == 1 },
+		time.Second, 10*time.Millisecond)
+	assert.ElementsMatch(t, []string{"EUR/USD", "EUR/GBP"}, ecb.requested())
+	assert.Equal(t, []string{"USD/RUB"}, cbr.requested())
+
+	assert.Equal(t, defaultJobTimeout, ecb.deadline)
+	assert.Equal(t, time.Minute, cbr.deadline)
+
+	tags := w.cron.GetAllTags()
+	assert.ElementsMatch(t, []string{"default:EUR", "cbr-rub"}, tags)
+}
+
+func TestCurrency_SkipsNonPublishingDays(t *testing.T) {
+	closed := &recordingService{calendar: calendar.Weekdays{}}
+	open := &recordingService{}
+
+	w, err := NewCurrency(config.WorkerConfig{Jobs: []config.WorkerJobConfig{
+		{Provider: "closed", BaseCurrency: "EUR", TargetCurrencies: []string{"USD"}, Schedule: "@daily"},
+		{Provider: "open", BaseCurrency: "EUR", TargetCurrencies: []string{"USD"}, Schedule: "@daily"},
+	}}, map[string]CurrencyService{"closed": closed, "open": open}, gocron.NewScheduler(time.UTC), nil, nil, nil, slog.Default())
+	require.NoError(t, err)
+
+	w.jobs[0].run("test")
+	w.jobs[1].run("test")
+
+	assert.Empty(t, closed.requested())
+	assert.Equal(t, []string{"EUR/USD"}, open.requested())
+}
+
+// lockTable отдаёт лок задания первой запросившей реплике, как advisory-лок
+type lockTable struct {
+	mu      sync.Mutex
+	holders map[string]string
+}
+
+func (t *lockTable) release(replica string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for name, holder := range t.holders {
+		if holder == replica {
+			delete(t.holders, name)
+		}
+	}
+}
+
+type replicaLocker struct {
+	table   *lockTable
+	replica string
+}
+
+func (l replicaLocker) Acquire(_ context.Context, name string) bool {
+	l.table.mu.Lock()
+	defer l.table.mu.Unlock()
+	if _, ok := l.table.holders[name]; !ok {
+		l.table.holders[name] = l.replica
+	}
+	return l.table.holders[name] == l.replica
+}
+
+func TestCurrency_OneReplicaRunsJob(t *testing.T) {
+	table := &lockTable{holders: make(map[string]string)}
+	cfg := config.WorkerConfig{Jobs: []config.WorkerJobConfig{
+		{BaseCurrency: "EUR", TargetCurrencies: []string{"USD"}, Schedule: "@daily"},
+	}}
+
+	first, second := &recordingService{}, &recordingService{}
+	w1, err := NewCurrency(cfg, map[string]CurrencyService{"": first}, gocron.NewScheduler(time.UTC), replicaLocker{table, "a"}, nil, nil, slog.Default())
+	require.NoError(t, err)
+	w2, err := NewCurrency(cfg, map[string]CurrencyService{"": second}, gocron.NewScheduler(time.UTC), replicaLocker{table, "b"}, nil, nil, slog.Default())
+	require.NoError(t, err)
+
+	w1.jobs[0].run("test")
+	w2.jobs[0].run("test")
+	w1.jobs[0].run("test")
+
+	assert.Len(t, first.requested(), 2)
+	assert.Empty(t, second.requested())
+
+	// Реплика a остановилась, задание переходит к b
+	table.release("a")
+	w2.jobs[0].run("test")
+	w1.jobs[0].run("test")
+
+	assert.Len(t, first.requested(), 2)
+	assert.Len(t, second.requested(), 1)
+}
+
+func TestNewCurrency_InvalidJobs(t *testing.T) {
+	services := map[string]CurrencyService{"": &recordingService{}}
+	valid := config.WorkerJobConfig{BaseCurrency: "EUR", TargetCurrencies: []string{"USD"}, Schedule: "@daily"}
+
+	tests := []struct {
+		name string
+		jobs []config.WorkerJobConfig
+	}{
+		{"no jobs", nil},
+		{"no base", []config.WorkerJobConfig{{TargetCurrencies: []string{"USD"}, Schedule: "@daily"}}},
+		{"no targets", []config.WorkerJobConfig{{BaseCurrency: "EUR", Schedule: "@daily"}}},
+		{"no schedule", []config.WorkerJobConfig{{BaseCurrency: "EUR", TargetCurrencies: []string{"USD"}}}},
+		{"unknown provider", []config.WorkerJobConfig{{BaseCurrency: "EUR", TargetCurrencies: []string{"USD"}, Schedule: "@daily", Provider: "cbr"}}},
+		{"provider and providers", []config.WorkerJobConfig{{BaseCurrency: "EUR", TargetCurrencies: []string{"USD"}, Schedule: "@daily", Provider: "ecb", Providers: []string{"ecb", "json"}}}},
+		{"duplicate name", []config.WorkerJobConfig{valid, valid}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewCurrency(config.WorkerConfig{Jobs: tt.jobs}, services, gocron.NewScheduler(time.UTC), nil, nil, nil, slog.Default())
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestStart_InvalidSchedule(t *testing.T) {
+	w, err := NewCurrency(config.WorkerConfig{Jobs: []config.WorkerJobConfig{
+		{BaseCurrency: "EUR", TargetCurrencies: []string{"USD"}, Schedule: "every day"},
+	}}, map[string]CurrencyService{"": &recordingService{}}, gocron.NewScheduler(time.UTC), nil, nil, nil, slog.Default())
+	require.NoError(t, err)
+
+	assert.Error(t, w.StartFetchingCurrencyRates())
+}
@@ -0,0 +1,94 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"my-currency-service/currency/internal/dto"
+	"time"
+)
+
+const (
+	defaultRetention     = 90 * 24 * time.Hour
+	defaultPruneSchedule = "@daily"
+
+	// Тег задачи удаления старой истории в планировщике
+	pruneTag = "prune-job-runs"
+
+	// Время на запись и удаление истории
+	historyTimeout = 10 * time.Second
+)
+
+// RunRecorder stores the history of job runs.
+type RunRecorder interface {
+	SaveJobRun(ctx context.Context, run dto.JobRunDTO) error
+	DeleteJobRunsBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// record stores the fetch of target and, on success, moves the last
+// success gauge. The provider of the run is the one that supplied the
+// rates, falling back to the providers of the job. A failed write is
+// logged: history must not stop fetching.
+func (j *job) record(trigger, target string, start time.Time, result dto.FetchResultDTO, fetchErr error) {
+	finish := time.Now()
+
+	provider := result.Provider
+	if provider == "" {
+		provider = j.provider
+	}
+
+	run := dto.JobRunDTO{
+		Job:            j.name,
+		Trigger:        trigger,
+		Provider:       provider,
+		BaseCurrency:   j.base,
+		TargetCurrency: target,
+		StartedAt:      start.UTC(),
+		FinishedAt:     finish.UTC(),
+		Status:         dto.JobRunSuccess,
+		RowsWritten:    result.Rows,
+	}
+	if fetchErr != nil {
+		run.Status = dto.JobRunFailure
+		run.Error = fetchErr.Error()
+	} else {
+		j.metrics.setLastSuccess(j.name, j.base, target, finish)
+	}
+
+	if j.history == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), historyTimeout)
+	defer cancel()
+
+	if err := j.history.SaveJobRun(ctx, run); err != nil {
+		j.logger.Error("Failed to record job run",
+			slog.String("base_currency", j.base),
+			slog.String("target_currency", target),
+			slog.Any("error", err))
+	}
+}
+
+// prune deletes the history older than the retention period.
+func (w *Currency) prune(trigger string) {
+	if !acquire(w.locker, pruneTag) {
+		w.logger.Debug("job run pruning skipped, lock is held by another replica",
+			slog.String("trigger", trigger))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), historyTimeout)
+	defer cancel()
+
+	before := time.Now().Add(-w.retention)
+	deleted, err := w.history.DeleteJobRunsBefore(ctx, before)
+	if err != nil {
+		w.logger.Error("Failed to prune job runs", slog.String("trigger", trigger), slog.Any("error", err))
+		return
+	}
+
+	w.logger.Info("job runs pruned",
+		slog.String("trigger", trigger),
+		slog.Time("before", before),
+		slog.Int64("deleted", deleted))
+}
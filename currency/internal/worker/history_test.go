@@ -0,0 +1,104 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"my-currency-service/currency/internal/config"
+	"my-currency-service/currency/internal/dto"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-co-op/gocron"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryHistory хранит записанные запуски и границу последнего удаления
+type memoryHistory struct {
+	mu     sync.Mutex
+	runs   []dto.JobRunDTO
+	before time.Time
+}
+
+func (h *memoryHistory) SaveJobRun(_ context.Context, run dto.JobRunDTO) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.runs = append(h.runs, run)
+	return nil
+}
+
+func (h *memoryHistory) DeleteJobRunsBefore(_ context.Context, before time.Time) (int64, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.before = before
+	return 0, nil
+}
+
+func TestJob_RecordsRuns(t *testing.T) {
+	service := &recordingService{failOn: map[string]bool{"GBP": true}}
+	history := &memoryHistory{}
+	lastSuccess := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "test_last_success", Help: "test"},
+		[]string{"job", "base_currency", "target_currency"})
+
+	w, err := NewCurrency(config.WorkerConfig{Jobs: []config.WorkerJobConfig{
+		{Name: "ecb-eur", BaseCurrency: "EUR", TargetCurrencies: []string{"USD", "GBP"}, Schedule: "@daily"},
+	}}, map[string]CurrencyService{"": service}, gocron.NewScheduler(time.UTC), nil, history, NewMetrics(nil, lastSuccess), slog.Default())
+	require.NoError(t, err)
+
+	before := time.Now()
+	w.jobs[0].run("startup")
+
+	require.Len(t, history.runs, 2)
+
+	usd, gbp := history.runs[0], history.runs[1]
+	assert.Equal(t, "ecb-eur", usd.Job)
+	assert.Equal(t, "startup", usd.Trigger)
+	assert.Equal(t, "stub", usd.Provider)
+	assert.Equal(t, "USD", usd.TargetCurrency)
+	assert.Equal(t, dto.JobRunSuccess, usd.Status)
+	assert.Equal(t, 2, usd.RowsWritten)
+	assert.False(t, usd.FinishedAt.Before(usd.StartedAt))
+
+	assert.Equal(t, dto.JobRunFailure, gbp.Status)
+	assert.Equal(t, "provider is down", gbp.Error)
+	assert.Zero(t, gbp.RowsWritten)
+
+	assert.GreaterOrEqual(t, testutil.ToFloat64(lastSuccess.WithLabelValues("ecb-eur", "EUR", "USD")), float64(before.Unix()))
+	// Неудачная пара метку времени не получает
+	assert.Equal(t, 1, testutil.CollectAndCount(lastSuccess))
+}
+
+func TestJob_RecordsSupplyingProvider(t *testing.T) {
+	service := &recordingService{supplier: "json"}
+	history := &memoryHistory{}
+
+	w, err := NewCurrency(config.WorkerConfig{Jobs: []config.WorkerJobConfig{
+		{BaseCurrency: "EUR", TargetCurrencies: []string{"USD"}, Schedule: "@daily", Providers: []string{"ECB", "json"}},
+	}}, map[string]CurrencyService{"ecb,json": service}, gocron.NewScheduler(time.UTC), nil, history, nil, slog.Default())
+	require.NoError(t, err)
+
+	w.jobs[0].run("schedule")
+
+	require.Len(t, history.runs, 1)
+	assert.Equal(t, "ecb,json:EUR", history.runs[0].Job)
+	// Основной провайдер не ответил, курс пришёл от запасного
+	assert.Equal(t, "json", history.runs[0].Provider)
+}
+
+func TestPrune(t *testing.T) {
+	history := &memoryHistory{}
+
+	w, err := NewCurrency(config.WorkerConfig{
+		Jobs:    []config.WorkerJobConfig{{BaseCurrency: "EUR", TargetCurrencies: []string{"USD"}, Schedule: "@daily"}},
+		History: config.RunHistoryConfig{Enabled: true, Retention: 48 * time.Hour},
+	}, map[string]CurrencyService{"": &recordingService{}}, gocron.NewScheduler(time.UTC), nil, history, nil, slog.Default())
+	require.NoError(t, err)
+
+	w.prune("test")
+
+	assert.WithinDuration(t, time.Now().Add(-48*time.Hour), history.before, time.Minute)
+	assert.Equal(t, defaultPruneSchedule, w.pruneSchedule)
+}
@@ -0,0 +1,34 @@
+package worker
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics holds the collectors of the worker.
+type Metrics struct {
+	missing     *prometheus.GaugeVec
+	lastSuccess *prometheus.GaugeVec
+}
+
+// NewMetrics wraps the missing observations gauge and the gauge of the
+// last successful fetch as a Unix timestamp; the labels of both are job,
+// base_currency and target_currency.
+func NewMetrics(missing, lastSuccess *prometheus.GaugeVec) *Metrics {
+	return &Metrics{missing: missing, lastSuccess: lastSuccess}
+}
+
+func (m *Metrics) setLastSuccess(job, base, target string, at time.Time) {
+	if m == nil {
+		return
+	}
+	m.lastSuccess.WithLabelValues(job, base, target).Set(float64(at.Unix()))
+}
+
+func (m *Metrics) setMissing(job, base, target string, count int) {
+	if m == nil {
+		return
+	}
+	m.missing.WithLabelValues(job, base, target).Set(float64(count))
+}
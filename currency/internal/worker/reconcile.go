@@ -0,0 +1,132 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"my-currency-service/currency/internal/dto"
+	"time"
+)
+
+const defaultLookbackDays = 30
+
+// Пропуски ближе этого расстояния загружаются одним окном: между ними
+// только выходные и праздники, лишние дни провайдер не вернёт
+const maxRepairGap = 4 * 24 * time.Hour
+
+// reconcile looks for missing fixings of every job and refetches them.
+func (w *Currency) reconcile(trigger string) {
+	if !acquire(w.locker, reconcileTag) {
+		w.logger.Debug("reconciliation skipped, lock is held by another replica",
+			slog.String("trigger", trigger))
+		return
+	}
+
+	dateTo := truncateDay(time.Now().UTC()).AddDate(0, 0, -1)
+	dateFrom := dateTo.AddDate(0, 0, -w.lookbackDays)
+
+	for _, j := range w.jobs {
+		for _, target := range j.targets {
+			j.reconcilePair(target, dateFrom, dateTo, trigger, w.metrics)
+		}
+	}
+}
+
+func (j *job) reconcilePair(target string, dateFrom, dateTo time.Time, trigger string, metrics *Metrics) {
+	logger := j.logger.With(
+		slog.String("trigger", trigger),
+		slog.String("base_currency", j.base),
+		slog.String("target_currency", target))
+
+	report, err := j.findGaps(target, dateFrom, dateTo)
+	if err != nil {
+		logger.Error("Failed to check missing observations", slog.Any("error", err))
+		return
+	}
+
+	if len(report.Missing) > 0 {
+		logger.Warn("missing observations found, refetching",
+			slog.Int("missing", len(report.Missing)),
+			slog.Any("dates", formatDates(report.Missing)))
+
+		for _, window := range repairWindows(report.Missing) {
+			if err := j.backfill(target, window); err != nil {
+				logger.Error("Failed to refetch missing observations",
+					slog.String("window", window.String()),
+					slog.Any("error", err))
+			}
+		}
+
+		report, err = j.findGaps(target, dateFrom, dateTo)
+		if err != nil {
+			logger.Error("Failed to check missing observations", slog.Any("error", err))
+			return
+		}
+	}
+
+	metrics.setMissing(j.name, j.base, target, len(report.Missing))
+
+	if len(report.Missing) > 0 {
+		logger.Warn("observations are still missing after repair",
+			slog.Int("missing", len(report.Missing)),
+			slog.Any("dates", formatDates(report.Missing)))
+	}
+}
+
+func (j *job) findGaps(target string, dateFrom, dateTo time.Time) (*dto.GapReportDTO, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), j.timeout)
+	defer cancel()
+
+	return j.service.FindGaps(ctx, &dto.GapQueryDTO{
+		BaseCurrency:   j.base,
+		TargetCurrency: target,
+		DateFrom:       dateFrom,
+		DateTo:         dateTo,
+	})
+}
+
+func (j *job) backfill(target string, window repairWindow) error {
+	ctx, cancel := context.WithTimeout(context.Background(), j.timeout)
+	defer cancel()
+
+	_, err := j.service.BackfillCurrencyRates(ctx, &dto.CurrencyRequestDTO{
+		BaseCurrency:   j.base,
+		TargetCurrency: target,
+		DateFrom:       window.from,
+		DateTo:         window.to,
+	})
+	return err
+}
+
+// repairWindow is an inclusive range of days refetched with one request.
+type repairWindow struct {
+	from, to time.Time
+}
+
+func (w repairWindow) String() string {
+	return w.from.Format(time.DateOnly) + ".." + w.to.Format(time.DateOnly)
+}
+
+// repairWindows groups sorted missing days into windows.
+func repairWindows(missing []time.Time) []repairWindow {
+	var windows []repairWindow
+	for _, day := range missing {
+		if n := len(windows); n > 0 && day.Sub(windows[n-1].to) <= maxRepairGap {
+			windows[n-1].to = day
+			continue
+		}
+		windows = append(windows, repairWindow{from: day, to: day})
+	}
+	return windows
+}
+
+func formatDates(dates []time.Time) []string {
+	formatted := make([]string, len(dates))
+	for i, date := range dates {
+		formatted[i] = date.Format(time.DateOnly)
+	}
+	return formatted
+}
+
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
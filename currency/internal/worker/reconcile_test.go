@@ -0,0 +1,124 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"my-currency-service/currency/internal/config"
+	"my-currency-service/currency/internal/dto"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-co-op/gocron"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func date(s string) time.Time {
+	t, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// gapService хранит пропущенные дни по целевой валюте; дозагрузка
+// закрывает все дни окна, кроме unrecoverable
+type gapService struct {
+	recordingService
+
+	mu            sync.Mutex
+	missing       map[string][]time.Time
+	unrecoverable map[time.Time]bool
+	windows       []string
+	queries       []dto.GapQueryDTO
+}
+
+func (s *gapService) FindGaps(_ context.Context, query *dto.GapQueryDTO) (*dto.GapReportDTO, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.queries = append(s.queries, *query)
+	return &dto.GapReportDTO{
+		BaseCurrency:   query.BaseCurrency,
+		TargetCurrency: query.TargetCurrency,
+		Missing:        append([]time.Time(nil), s.missing[query.TargetCurrency]...),
+	}, nil
+}
+
+func (s *gapService) BackfillCurrencyRates(_ context.Context, req *dto.CurrencyRequestDTO) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.windows = append(s.windows, req.TargetCurrency+" "+repairWindow{from: req.DateFrom, to: req.DateTo}.String())
+
+	var left []time.Time
+	for _, day := range s.missing[req.TargetCurrency] {
+		if s.unrecoverable[day] || day.Before(req.DateFrom) || day.After(req.DateTo) {
+			left = append(left, day)
+		}
+	}
+	s.missing[req.TargetCurrency] = left
+	return 1, nil
+}
+
+func TestReconcile(t *testing.T) {
+	service := &gapService{
+		missing: map[string][]time.Time{
+			// Пятница и понедельник — одно окно, следующий понедельник — другое
+			"USD": {date("2025-02-28"), date("2025-03-03"), date("2025-03-10")},
+		},
+		unrecoverable: map[time.Time]bool{date("2025-03-10"): true},
+	}
+	missing := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "test_missing", Help: "test"},
+		[]string{"job", "base_currency", "target_currency"})
+
+	w, err := NewCurrency(config.WorkerConfig{
+		Jobs: []config.WorkerJobConfig{
+			{Name: "ecb-eur", BaseCurrency: "EUR", TargetCurrencies: []string{"USD", "GBP"}, Schedule: "@daily"},
+		},
+		Reconcile: config.ReconcileConfig{Schedule: "0 6 * * *", LookbackDays: 10},
+	}, map[string]CurrencyService{"": service}, gocron.NewScheduler(time.UTC), nil, nil, NewMetrics(missing, nil), slog.Default())
+	require.NoError(t, err)
+
+	w.reconcile("test")
+
+	assert.Equal(t, []string{
+		"USD 2025-02-28..2025-03-03",
+		"USD 2025-03-10..2025-03-10",
+	}, service.windows)
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(missing.WithLabelValues("ecb-eur", "EUR", "USD")))
+	assert.Equal(t, 0.0, testutil.ToFloat64(missing.WithLabelValues("ecb-eur", "EUR", "GBP")))
+
+	// Проверяется период до вчерашнего дня включительно
+	yesterday := truncateDay(time.Now().UTC()).AddDate(0, 0, -1)
+	require.NotEmpty(t, service.queries)
+	assert.Equal(t, yesterday, service.queries[0].DateTo)
+	assert.Equal(t, yesterday.AddDate(0, 0, -10), service.queries[0].DateFrom)
+}
+
+func TestReconcile_Scheduled(t *testing.T) {
+	w, err := NewCurrency(config.WorkerConfig{
+		Jobs:      []config.WorkerJobConfig{{BaseCurrency: "EUR", TargetCurrencies: []string{"USD"}, Schedule: "@daily"}},
+		Reconcile: config.ReconcileConfig{Schedule: "0 6 * * *"},
+	}, map[string]CurrencyService{"": &gapService{}}, gocron.NewScheduler(time.UTC), nil, nil, nil, slog.Default())
+	require.NoError(t, err)
+
+	require.NoError(t, w.StartFetchingCurrencyRates())
+	defer func() { _ = w.Stop() }()
+
+	assert.Contains(t, w.cron.GetAllTags(), reconcileTag)
+	assert.Equal(t, defaultLookbackDays, w.lookbackDays)
+}
+
+func TestRepairWindows(t *testing.T) {
+	windows := repairWindows([]time.Time{date("2025-01-02"), date("2025-01-03"), date("2025-01-06"), date("2025-01-20")})
+
+	require.Len(t, windows, 2)
+	assert.Equal(t, "2025-01-02..2025-01-06", windows[0].String())
+	assert.Equal(t, "2025-01-20..2025-01-20", windows[1].String())
+	assert.Empty(t, repairWindows(nil))
+}
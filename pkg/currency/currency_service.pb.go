@@ -9,6 +9,7 @@ package currency
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
 	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
@@ -143,28 +144,1313 @@ func (x *GetRateResponse) GetRates() []*RateRecord {
 }
 
 type RateRecord struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Date  *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=date,proto3" json:"date,omitempty"`
+	// Rounded to float32, kept for compatibility. Prefer exact_rate.
+	Rate float32 `protobuf:"fixed32,2,opt,name=rate,proto3" json:"rate,omitempty"`
+	// Exact decimal rate as a string, e.g. "157.7096".
+	ExactRate string `protobuf:"bytes,3,opt,name=exact_rate,json=exactRate,proto3" json:"exact_rate,omitempty"`
+	// Set when the rate is not stored but derived from legs: an inverse
+	// or a cross rate through the pivot currency.
+	Derived bool `protobuf:"varint,4,opt,name=derived,proto3" json:"derived,omitempty"`
+	// Stored observations the rate is derived from, in path order.
+	Legs []*RateLeg `protobuf:"bytes,5,rep,name=legs,proto3" json:"legs,omitempty"`
+	// Provider the stored value was fetched from, e.g. "ecb". A derived rate
+	// lists the providers of its legs separated by commas. Empty for rates
+	// stored before the provider was recorded.
+	Provider string `protobuf:"bytes,6,opt,name=provider,proto3" json:"provider,omitempty"`
+	// When the value was fetched from the provider; for a derived rate, the
+	// oldest fetch of its legs.
+	FetchedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=fetched_at,json=fetchedAt,proto3" json:"fetched_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RateRecord) Reset() {
+	*x = RateRecord{}
+	mi := &file_proto_currency_currency_service_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RateRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RateRecord) ProtoMessage() {}
+
+func (x *RateRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_currency_currency_service_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RateRecord.ProtoReflect.Descriptor instead.
+func (*RateRecord) Descriptor() ([]byte, []int) {
+	return file_proto_currency_currency_service_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *RateRecord) GetDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Date
+	}
+	return nil
+}
+
+func (x *RateRecord) GetRate() float32 {
+	if x != nil {
+		return x.Rate
+	}
+	return 0
+}
+
+func (x *RateRecord) GetExactRate() string {
+	if x != nil {
+		return x.ExactRate
+	}
+	return ""
+}
+
+func (x *RateRecord) GetDerived() bool {
+	if x != nil {
+		return x.Derived
+	}
+	return false
+}
+
+func (x *RateRecord) GetLegs() []*RateLeg {
+	if x != nil {
+		return x.Legs
+	}
+	return nil
+}
+
+func (x *RateRecord) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *RateRecord) GetFetchedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.FetchedAt
+	}
+	return nil
+}
+
+// RateLeg is a stored observation; an inverted leg contributes 1/rate.
+type RateLeg struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BaseCurrency  string                 `protobuf:"bytes,1,opt,name=base_currency,json=baseCurrency,proto3" json:"base_currency,omitempty"`
+	Currency      string                 `protobuf:"bytes,2,opt,name=currency,proto3" json:"currency,omitempty"`
+	Rate          string                 `protobuf:"bytes,3,opt,name=rate,proto3" json:"rate,omitempty"`
+	Inverted      bool                   `protobuf:"varint,4,opt,name=inverted,proto3" json:"inverted,omitempty"`
+	Provider      string                 `protobuf:"bytes,5,opt,name=provider,proto3" json:"provider,omitempty"`
+	FetchedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=fetched_at,json=fetchedAt,proto3" json:"fetched_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RateLeg) Reset() {
+	*x = RateLeg{}
+	mi := &file_proto_currency_currency_service_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RateLeg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RateLeg) ProtoMessage() {}
+
+func (x *RateLeg) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_currency_currency_service_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RateLeg.ProtoReflect.Descriptor instead.
+func (*RateLeg) Descriptor() ([]byte, []int) {
+	return file_proto_currency_currency_service_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *RateLeg) GetBaseCurrency() string {
+	if x != nil {
+		return x.BaseCurrency
+	}
+	return ""
+}
+
+func (x *RateLeg) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *RateLeg) GetRate() string {
+	if x != nil {
+		return x.Rate
+	}
+	return ""
+}
+
+func (x *RateLeg) GetInverted() bool {
+	if x != nil {
+		return x.Inverted
+	}
+	return false
+}
+
+func (x *RateLeg) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *RateLeg) GetFetchedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.FetchedAt
+	}
+	return nil
+}
+
+type ConvertRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Decimal amount in the source currency, e.g. "100.50".
+	Amount string `protobuf:"bytes,1,opt,name=amount,proto3" json:"amount,omitempty"`
+	From   string `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	To     string `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`
+	// Rate date; the fixing that applies on it by the provider calendar is
+	// used, or the latest one before it if that is missing. Defaults to now.
+	AsOf          *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=as_of,json=asOf,proto3" json:"as_of,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConvertRequest) Reset() {
+	*x = ConvertRequest{}
+	mi := &file_proto_currency_currency_service_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConvertRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConvertRequest) ProtoMessage() {}
+
+func (x *ConvertRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_currency_currency_service_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConvertRequest.ProtoReflect.Descriptor instead.
+func (*ConvertRequest) Descriptor() ([]byte, []int) {
+	return file_proto_currency_currency_service_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ConvertRequest) GetAmount() string {
+	if x != nil {
+		return x.Amount
+	}
+	return ""
+}
+
+func (x *ConvertRequest) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *ConvertRequest) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+func (x *ConvertRequest) GetAsOf() *timestamppb.Timestamp {
+	if x != nil {
+		return x.AsOf
+	}
+	return nil
+}
+
+type ConvertResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Converted amount rounded to the ISO 4217 minor units of the target currency.
+	// Not rounded if the target currency has no minor units, e.g. XAU.
+	Amount string `protobuf:"bytes,1,opt,name=amount,proto3" json:"amount,omitempty"`
+	From   string `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	To     string `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`
+	// Exact rate used for the conversion.
+	Rate string `protobuf:"bytes,4,opt,name=rate,proto3" json:"rate,omitempty"`
+	// Observation date of the rate.
+	RateDate *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=rate_date,json=rateDate,proto3" json:"rate_date,omitempty"`
+	// ISO 4217 minor units of the target currency; -1 if it has none ("N.A.").
+	MinorUnits int32 `protobuf:"varint,6,opt,name=minor_units,json=minorUnits,proto3" json:"minor_units,omitempty"`
+	// Rounding applied to amount: "HALF_AWAY_FROM_ZERO" or "NONE".
+	RoundingMode    string `protobuf:"bytes,7,opt,name=rounding_mode,json=roundingMode,proto3" json:"rounding_mode,omitempty"`
+	UnroundedAmount string `protobuf:"bytes,8,opt,name=unrounded_amount,json=unroundedAmount,proto3" json:"unrounded_amount,omitempty"`
+	// Fixing that applies on as_of; rate_date is earlier if it is missing.
+	FixingDate    *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=fixing_date,json=fixingDate,proto3" json:"fixing_date,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConvertResponse) Reset() {
+	*x = ConvertResponse{}
+	mi := &file_proto_currency_currency_service_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConvertResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConvertResponse) ProtoMessage() {}
+
+func (x *ConvertResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_currency_currency_service_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConvertResponse.ProtoReflect.Descriptor instead.
+func (*ConvertResponse) Descriptor() ([]byte, []int) {
+	return file_proto_currency_currency_service_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ConvertResponse) GetAmount() string {
+	if x != nil {
+		return x.Amount
+	}
+	return ""
+}
+
+func (x *ConvertResponse) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *ConvertResponse) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+func (x *ConvertResponse) GetRate() string {
+	if x != nil {
+		return x.Rate
+	}
+	return ""
+}
+
+func (x *ConvertResponse) GetRateDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RateDate
+	}
+	return nil
+}
+
+func (x *ConvertResponse) GetMinorUnits() int32 {
+	if x != nil {
+		return x.MinorUnits
+	}
+	return 0
+}
+
+func (x *ConvertResponse) GetRoundingMode() string {
+	if x != nil {
+		return x.RoundingMode
+	}
+	return ""
+}
+
+func (x *ConvertResponse) GetUnroundedAmount() string {
+	if x != nil {
+		return x.UnroundedAmount
+	}
+	return ""
+}
+
+func (x *ConvertResponse) GetFixingDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.FixingDate
+	}
+	return nil
+}
+
+type GetLatestRateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Currency      string                 `protobuf:"bytes,1,opt,name=currency,proto3" json:"currency,omitempty"`
+	BaseCurrency  string                 `protobuf:"bytes,2,opt,name=base_currency,json=baseCurrency,proto3" json:"base_currency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLatestRateRequest) Reset() {
+	*x = GetLatestRateRequest{}
+	mi := &file_proto_currency_currency_service_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLatestRateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLatestRateRequest) ProtoMessage() {}
+
+func (x *GetLatestRateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_currency_currency_service_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLatestRateRequest.ProtoReflect.Descriptor instead.
+func (*GetLatestRateRequest) Descriptor() ([]byte, []int) {
+	return file_proto_currency_currency_service_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetLatestRateRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *GetLatestRateRequest) GetBaseCurrency() string {
+	if x != nil {
+		return x.BaseCurrency
+	}
+	return ""
+}
+
+type GetLatestRateResponse struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Currency     string                 `protobuf:"bytes,1,opt,name=currency,proto3" json:"currency,omitempty"`
+	BaseCurrency string                 `protobuf:"bytes,2,opt,name=base_currency,json=baseCurrency,proto3" json:"base_currency,omitempty"`
+	// Newest observation; rate.date is the observation date.
+	Rate *RateRecord `protobuf:"bytes,3,opt,name=rate,proto3" json:"rate,omitempty"`
+	// When the observation was last stored by the worker; unset if unknown.
+	FetchedAt *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=fetched_at,json=fetchedAt,proto3" json:"fetched_at,omitempty"`
+	// Time passed since the observation date.
+	Age *durationpb.Duration `protobuf:"bytes,5,opt,name=age,proto3" json:"age,omitempty"`
+	// Freshness threshold configured for the pair.
+	MaxAge *durationpb.Duration `protobuf:"bytes,6,opt,name=max_age,json=maxAge,proto3" json:"max_age,omitempty"`
+	// Set when a fixing the provider calendar expected more than max_age ago
+	// is missing. Holidays without a fixing do not make a rate stale.
+	Stale bool `protobuf:"varint,7,opt,name=stale,proto3" json:"stale,omitempty"`
+	// Latest fixing the provider calendar expects by now; rate.date is
+	// earlier until it is published.
+	FixingDate    *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=fixing_date,json=fixingDate,proto3" json:"fixing_date,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLatestRateResponse) Reset() {
+	*x = GetLatestRateResponse{}
+	mi := &file_proto_currency_currency_service_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLatestRateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLatestRateResponse) ProtoMessage() {}
+
+func (x *GetLatestRateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_currency_currency_service_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLatestRateResponse.ProtoReflect.Descriptor instead.
+func (*GetLatestRateResponse) Descriptor() ([]byte, []int) {
+	return file_proto_currency_currency_service_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetLatestRateResponse) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *GetLatestRateResponse) GetBaseCurrency() string {
+	if x != nil {
+		return x.BaseCurrency
+	}
+	return ""
+}
+
+func (x *GetLatestRateResponse) GetRate() *RateRecord {
+	if x != nil {
+		return x.Rate
+	}
+	return nil
+}
+
+func (x *GetLatestRateResponse) GetFetchedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.FetchedAt
+	}
+	return nil
+}
+
+func (x *GetLatestRateResponse) GetAge() *durationpb.Duration {
+	if x != nil {
+		return x.Age
+	}
+	return nil
+}
+
+func (x *GetLatestRateResponse) GetMaxAge() *durationpb.Duration {
+	if x != nil {
+		return x.MaxAge
+	}
+	return nil
+}
+
+func (x *GetLatestRateResponse) GetStale() bool {
+	if x != nil {
+		return x.Stale
+	}
+	return false
+}
+
+func (x *GetLatestRateResponse) GetFixingDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.FixingDate
+	}
+	return nil
+}
+
+type BatchGetRatesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Queries       []*RateQuery           `protobuf:"bytes,1,rep,name=queries,proto3" json:"queries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchGetRatesRequest) Reset() {
+	*x = BatchGetRatesRequest{}
+	mi := &file_proto_currency_currency_service_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchGetRatesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchGetRatesRequest) ProtoMessage() {}
+
+func (x *BatchGetRatesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_currency_currency_service_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchGetRatesRequest.ProtoReflect.Descriptor instead.
+func (*BatchGetRatesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_currency_currency_service_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *BatchGetRatesRequest) GetQueries() []*RateQuery {
+	if x != nil {
+		return x.Queries
+	}
+	return nil
+}
+
+// RateQuery asks for the rate applicable on date: the latest observation
+// on or before the fixing that applies on date by the provider calendar.
+type RateQuery struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BaseCurrency  string                 `protobuf:"bytes,1,opt,name=base_currency,json=baseCurrency,proto3" json:"base_currency,omitempty"`
+	Currency      string                 `protobuf:"bytes,2,opt,name=currency,proto3" json:"currency,omitempty"`
+	Date          *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=date,proto3" json:"date,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RateQuery) Reset() {
+	*x = RateQuery{}
+	mi := &file_proto_currency_currency_service_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RateQuery) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RateQuery) ProtoMessage() {}
+
+func (x *RateQuery) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_currency_currency_service_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RateQuery.ProtoReflect.Descriptor instead.
+func (*RateQuery) Descriptor() ([]byte, []int) {
+	return file_proto_currency_currency_service_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *RateQuery) GetBaseCurrency() string {
+	if x != nil {
+		return x.BaseCurrency
+	}
+	return ""
+}
+
+func (x *RateQuery) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *RateQuery) GetDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Date
+	}
+	return nil
+}
+
+type BatchGetRatesResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// One result per query, in request order.
+	Results       []*BatchRateResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchGetRatesResponse) Reset() {
+	*x = BatchGetRatesResponse{}
+	mi := &file_proto_currency_currency_service_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchGetRatesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchGetRatesResponse) ProtoMessage() {}
+
+func (x *BatchGetRatesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_currency_currency_service_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchGetRatesResponse.ProtoReflect.Descriptor instead.
+func (*BatchGetRatesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_currency_currency_service_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *BatchGetRatesResponse) GetResults() []*BatchRateResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type BatchRateResult struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Query *RateQuery             `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	// Types that are valid to be assigned to Result:
+	//
+	//	*BatchRateResult_Rate
+	//	*BatchRateResult_Error
+	Result isBatchRateResult_Result `protobuf_oneof:"result"`
+	// Fixing that applies on query.date; unset for invalid queries.
+	FixingDate    *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=fixing_date,json=fixingDate,proto3" json:"fixing_date,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchRateResult) Reset() {
+	*x = BatchRateResult{}
+	mi := &file_proto_currency_currency_service_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchRateResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchRateResult) ProtoMessage() {}
+
+func (x *BatchRateResult) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_currency_currency_service_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchRateResult.ProtoReflect.Descriptor instead.
+func (*BatchRateResult) Descriptor() ([]byte, []int) {
+	return file_proto_currency_currency_service_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *BatchRateResult) GetQuery() *RateQuery {
+	if x != nil {
+		return x.Query
+	}
+	return nil
+}
+
+func (x *BatchRateResult) GetResult() isBatchRateResult_Result {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *BatchRateResult) GetRate() *RateRecord {
+	if x != nil {
+		if x, ok := x.Result.(*BatchRateResult_Rate); ok {
+			return x.Rate
+		}
+	}
+	return nil
+}
+
+func (x *BatchRateResult) GetError() *BatchRateError {
+	if x != nil {
+		if x, ok := x.Result.(*BatchRateResult_Error); ok {
+			return x.Error
+		}
+	}
+	return nil
+}
+
+func (x *BatchRateResult) GetFixingDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.FixingDate
+	}
+	return nil
+}
+
+type isBatchRateResult_Result interface {
+	isBatchRateResult_Result()
+}
+
+type BatchRateResult_Rate struct {
+	Rate *RateRecord `protobuf:"bytes,2,opt,name=rate,proto3,oneof"`
+}
+
+type BatchRateResult_Error struct {
+	Error *BatchRateError `protobuf:"bytes,3,opt,name=error,proto3,oneof"`
+}
+
+func (*BatchRateResult_Rate) isBatchRateResult_Result() {}
+
+func (*BatchRateResult_Error) isBatchRateResult_Result() {}
+
+type BatchRateError struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// google.rpc.Code value, e.g. 5 for NOT_FOUND.
+	Code          int32  `protobuf:"varint,1,opt,name=code,proto3" json:"code,omitempty"`
+	Message       string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchRateError) Reset() {
+	*x = BatchRateError{}
+	mi := &file_proto_currency_currency_service_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchRateError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchRateError) ProtoMessage() {}
+
+func (x *BatchRateError) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_currency_currency_service_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchRateError.ProtoReflect.Descriptor instead.
+func (*BatchRateError) Descriptor() ([]byte, []int) {
+	return file_proto_currency_currency_service_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *BatchRateError) GetCode() int32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *BatchRateError) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type SubscribeRatesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Pairs         []*CurrencyPair        `protobuf:"bytes,1,rep,name=pairs,proto3" json:"pairs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscribeRatesRequest) Reset() {
+	*x = SubscribeRatesRequest{}
+	mi := &file_proto_currency_currency_service_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscribeRatesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeRatesRequest) ProtoMessage() {}
+
+func (x *SubscribeRatesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_currency_currency_service_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeRatesRequest.ProtoReflect.Descriptor instead.
+func (*SubscribeRatesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_currency_currency_service_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *SubscribeRatesRequest) GetPairs() []*CurrencyPair {
+	if x != nil {
+		return x.Pairs
+	}
+	return nil
+}
+
+type CurrencyPair struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Defaults to USD.
+	BaseCurrency  string `protobuf:"bytes,1,opt,name=base_currency,json=baseCurrency,proto3" json:"base_currency,omitempty"`
+	Currency      string `protobuf:"bytes,2,opt,name=currency,proto3" json:"currency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CurrencyPair) Reset() {
+	*x = CurrencyPair{}
+	mi := &file_proto_currency_currency_service_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CurrencyPair) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CurrencyPair) ProtoMessage() {}
+
+func (x *CurrencyPair) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_currency_currency_service_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CurrencyPair.ProtoReflect.Descriptor instead.
+func (*CurrencyPair) Descriptor() ([]byte, []int) {
+	return file_proto_currency_currency_service_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *CurrencyPair) GetBaseCurrency() string {
+	if x != nil {
+		return x.BaseCurrency
+	}
+	return ""
+}
+
+func (x *CurrencyPair) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+// SubscribeRatesResponse carries one stored observation. A stream that
+// falls too far behind is closed with RESOURCE_EXHAUSTED; the client
+// should resubscribe and catch up with GetRate.
+type SubscribeRatesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BaseCurrency  string                 `protobuf:"bytes,1,opt,name=base_currency,json=baseCurrency,proto3" json:"base_currency,omitempty"`
+	Currency      string                 `protobuf:"bytes,2,opt,name=currency,proto3" json:"currency,omitempty"`
+	Rate          *RateRecord            `protobuf:"bytes,3,opt,name=rate,proto3" json:"rate,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscribeRatesResponse) Reset() {
+	*x = SubscribeRatesResponse{}
+	mi := &file_proto_currency_currency_service_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscribeRatesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeRatesResponse) ProtoMessage() {}
+
+func (x *SubscribeRatesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_currency_currency_service_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeRatesResponse.ProtoReflect.Descriptor instead.
+func (*SubscribeRatesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_currency_currency_service_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *SubscribeRatesResponse) GetBaseCurrency() string {
+	if x != nil {
+		return x.BaseCurrency
+	}
+	return ""
+}
+
+func (x *SubscribeRatesResponse) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *SubscribeRatesResponse) GetRate() *RateRecord {
+	if x != nil {
+		return x.Rate
+	}
+	return nil
+}
+
+type GetGapReportRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Pairs []*CurrencyPair        `protobuf:"bytes,1,rep,name=pairs,proto3" json:"pairs,omitempty"`
+	// Provider whose publishing calendar is checked, e.g. "cbr". Defaults
+	// to the provider of the server.
+	Provider string `protobuf:"bytes,2,opt,name=provider,proto3" json:"provider,omitempty"`
+	// Defaults to 30 days before date_to.
+	DateFrom *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=date_from,json=dateFrom,proto3" json:"date_from,omitempty"`
+	// Defaults to yesterday: today's fixing may not be published yet.
+	DateTo        *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=date_to,json=dateTo,proto3" json:"date_to,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetGapReportRequest) Reset() {
+	*x = GetGapReportRequest{}
+	mi := &file_proto_currency_currency_service_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetGapReportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetGapReportRequest) ProtoMessage() {}
+
+func (x *GetGapReportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_currency_currency_service_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetGapReportRequest.ProtoReflect.Descriptor instead.
+func (*GetGapReportRequest) Descriptor() ([]byte, []int) {
+	return file_proto_currency_currency_service_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *GetGapReportRequest) GetPairs() []*CurrencyPair {
+	if x != nil {
+		return x.Pairs
+	}
+	return nil
+}
+
+func (x *GetGapReportRequest) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *GetGapReportRequest) GetDateFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DateFrom
+	}
+	return nil
+}
+
+func (x *GetGapReportRequest) GetDateTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DateTo
+	}
+	return nil
+}
+
+type GetGapReportResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// One report per pair, in request order.
+	Reports       []*GapReport `protobuf:"bytes,1,rep,name=reports,proto3" json:"reports,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetGapReportResponse) Reset() {
+	*x = GetGapReportResponse{}
+	mi := &file_proto_currency_currency_service_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetGapReportResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetGapReportResponse) ProtoMessage() {}
+
+func (x *GetGapReportResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_currency_currency_service_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetGapReportResponse.ProtoReflect.Descriptor instead.
+func (*GetGapReportResponse) Descriptor() ([]byte, []int) {
+	return file_proto_currency_currency_service_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *GetGapReportResponse) GetReports() []*GapReport {
+	if x != nil {
+		return x.Reports
+	}
+	return nil
+}
+
+type GapReport struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Provider     string                 `protobuf:"bytes,1,opt,name=provider,proto3" json:"provider,omitempty"`
+	BaseCurrency string                 `protobuf:"bytes,2,opt,name=base_currency,json=baseCurrency,proto3" json:"base_currency,omitempty"`
+	Currency     string                 `protobuf:"bytes,3,opt,name=currency,proto3" json:"currency,omitempty"`
+	DateFrom     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=date_from,json=dateFrom,proto3" json:"date_from,omitempty"`
+	DateTo       *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=date_to,json=dateTo,proto3" json:"date_to,omitempty"`
+	// Publishing days in the period.
+	ExpectedDays int32 `protobuf:"varint,6,opt,name=expected_days,json=expectedDays,proto3" json:"expected_days,omitempty"`
+	// Publishing days without a stored observation.
+	MissingDates  []*timestamppb.Timestamp `protobuf:"bytes,7,rep,name=missing_dates,json=missingDates,proto3" json:"missing_dates,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GapReport) Reset() {
+	*x = GapReport{}
+	mi := &file_proto_currency_currency_service_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GapReport) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GapReport) ProtoMessage() {}
+
+func (x *GapReport) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_currency_currency_service_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GapReport.ProtoReflect.Descriptor instead.
+func (*GapReport) Descriptor() ([]byte, []int) {
+	return file_proto_currency_currency_service_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *GapReport) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *GapReport) GetBaseCurrency() string {
+	if x != nil {
+		return x.BaseCurrency
+	}
+	return ""
+}
+
+func (x *GapReport) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *GapReport) GetDateFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DateFrom
+	}
+	return nil
+}
+
+func (x *GapReport) GetDateTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DateTo
+	}
+	return nil
+}
+
+func (x *GapReport) GetExpectedDays() int32 {
+	if x != nil {
+		return x.ExpectedDays
+	}
+	return 0
+}
+
+func (x *GapReport) GetMissingDates() []*timestamppb.Timestamp {
+	if x != nil {
+		return x.MissingDates
+	}
+	return nil
+}
+
+// GetJobRunsRequest filters the run history; empty fields match every run.
+type GetJobRunsRequest struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Job          string                 `protobuf:"bytes,1,opt,name=job,proto3" json:"job,omitempty"`
+	BaseCurrency string                 `protobuf:"bytes,2,opt,name=base_currency,json=baseCurrency,proto3" json:"base_currency,omitempty"`
+	Currency     string                 `protobuf:"bytes,3,opt,name=currency,proto3" json:"currency,omitempty"`
+	// "success" or "failure".
+	Status string `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	// Defaults to 100, at most 1000.
+	Limit         int32 `protobuf:"varint,5,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetJobRunsRequest) Reset() {
+	*x = GetJobRunsRequest{}
+	mi := &file_proto_currency_currency_service_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetJobRunsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetJobRunsRequest) ProtoMessage() {}
+
+func (x *GetJobRunsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_currency_currency_service_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetJobRunsRequest.ProtoReflect.Descriptor instead.
+func (*GetJobRunsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_currency_currency_service_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *GetJobRunsRequest) GetJob() string {
+	if x != nil {
+		return x.Job
+	}
+	return ""
+}
+
+func (x *GetJobRunsRequest) GetBaseCurrency() string {
+	if x != nil {
+		return x.BaseCurrency
+	}
+	return ""
+}
+
+func (x *GetJobRunsRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *GetJobRunsRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *GetJobRunsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type GetJobRunsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Date          *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=date,proto3" json:"date,omitempty"`
-	Rate          float32                `protobuf:"fixed32,2,opt,name=rate,proto3" json:"rate,omitempty"`
+	Runs          []*JobRun              `protobuf:"bytes,1,rep,name=runs,proto3" json:"runs,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RateRecord) Reset() {
-	*x = RateRecord{}
-	mi := &file_proto_currency_currency_service_proto_msgTypes[2]
+func (x *GetJobRunsResponse) Reset() {
+	*x = GetJobRunsResponse{}
+	mi := &file_proto_currency_currency_service_proto_msgTypes[20]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RateRecord) String() string {
+func (x *GetJobRunsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RateRecord) ProtoMessage() {}
+func (*GetJobRunsResponse) ProtoMessage() {}
 
-func (x *RateRecord) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_currency_currency_service_proto_msgTypes[2]
+func (x *GetJobRunsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_currency_currency_service_proto_msgTypes[20]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -175,21 +1461,149 @@ func (x *RateRecord) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RateRecord.ProtoReflect.Descriptor instead.
-func (*RateRecord) Descriptor() ([]byte, []int) {
-	return file_proto_currency_currency_service_proto_rawDescGZIP(), []int{2}
+// Deprecated: Use GetJobRunsResponse.ProtoReflect.Descriptor instead.
+func (*GetJobRunsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_currency_currency_service_proto_rawDescGZIP(), []int{20}
 }
 
-func (x *RateRecord) GetDate() *timestamppb.Timestamp {
+func (x *GetJobRunsResponse) GetRuns() []*JobRun {
 	if x != nil {
-		return x.Date
+		return x.Runs
 	}
 	return nil
 }
 
-func (x *RateRecord) GetRate() float32 {
+// JobRun is the fetch of one pair in one run of a worker job.
+type JobRun struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Job   string                 `protobuf:"bytes,2,opt,name=job,proto3" json:"job,omitempty"`
+	// "schedule" or "startup".
+	Trigger      string                 `protobuf:"bytes,3,opt,name=trigger,proto3" json:"trigger,omitempty"`
+	Provider     string                 `protobuf:"bytes,4,opt,name=provider,proto3" json:"provider,omitempty"`
+	BaseCurrency string                 `protobuf:"bytes,5,opt,name=base_currency,json=baseCurrency,proto3" json:"base_currency,omitempty"`
+	Currency     string                 `protobuf:"bytes,6,opt,name=currency,proto3" json:"currency,omitempty"`
+	StartedAt    *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	FinishedAt   *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=finished_at,json=finishedAt,proto3" json:"finished_at,omitempty"`
+	Duration     *durationpb.Duration   `protobuf:"bytes,9,opt,name=duration,proto3" json:"duration,omitempty"`
+	// "success" or "failure".
+	Status        string `protobuf:"bytes,10,opt,name=status,proto3" json:"status,omitempty"`
+	Error         string `protobuf:"bytes,11,opt,name=error,proto3" json:"error,omitempty"`
+	RowsWritten   int32  `protobuf:"varint,12,opt,name=rows_written,json=rowsWritten,proto3" json:"rows_written,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JobRun) Reset() {
+	*x = JobRun{}
+	mi := &file_proto_currency_currency_service_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JobRun) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobRun) ProtoMessage() {}
+
+func (x *JobRun) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_currency_currency_service_proto_msgTypes[21]
 	if x != nil {
-		return x.Rate
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobRun.ProtoReflect.Descriptor instead.
+func (*JobRun) Descriptor() ([]byte, []int) {
+	return file_proto_currency_currency_service_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *JobRun) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *JobRun) GetJob() string {
+	if x != nil {
+		return x.Job
+	}
+	return ""
+}
+
+func (x *JobRun) GetTrigger() string {
+	if x != nil {
+		return x.Trigger
+	}
+	return ""
+}
+
+func (x *JobRun) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *JobRun) GetBaseCurrency() string {
+	if x != nil {
+		return x.BaseCurrency
+	}
+	return ""
+}
+
+func (x *JobRun) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *JobRun) GetStartedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartedAt
+	}
+	return nil
+}
+
+func (x *JobRun) GetFinishedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.FinishedAt
+	}
+	return nil
+}
+
+func (x *JobRun) GetDuration() *durationpb.Duration {
+	if x != nil {
+		return x.Duration
+	}
+	return nil
+}
+
+func (x *JobRun) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *JobRun) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *JobRun) GetRowsWritten() int32 {
+	if x != nil {
+		return x.RowsWritten
 	}
 	return 0
 }
@@ -198,7 +1612,7 @@ var File_proto_currency_currency_service_proto protoreflect.FileDescriptor
 
 const file_proto_currency_currency_service_proto_rawDesc = "" +
 	"\n" +
-	"%proto/currency/currency_service.proto\x12\bcurrency\x1a\x1fgoogle/protobuf/timestamp.proto\"\xbf\x01\n" +
+	"%proto/currency/currency_service.proto\x12\bcurrency\x1a\x1egoogle/protobuf/duration.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xbf\x01\n" +
 	"\x0eGetRateRequest\x12\x1a\n" +
 	"\bcurrency\x18\x01 \x01(\tR\bcurrency\x127\n" +
 	"\tdata_from\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\bdataFrom\x123\n" +
@@ -206,13 +1620,132 @@ const file_proto_currency_currency_service_proto_rawDesc = "" +
 	"\rbase_currency\x18\x04 \x01(\tR\fbaseCurrency\"Y\n" +
 	"\x0fGetRateResponse\x12\x1a\n" +
 	"\bcurrency\x18\x01 \x01(\tR\bcurrency\x12*\n" +
-	"\x05rates\x18\x02 \x03(\v2\x14.currency.RateRecordR\x05rates\"P\n" +
+	"\x05rates\x18\x02 \x03(\v2\x14.currency.RateRecordR\x05rates\"\x87\x02\n" +
 	"\n" +
 	"RateRecord\x12.\n" +
 	"\x04date\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x04date\x12\x12\n" +
-	"\x04rate\x18\x02 \x01(\x02R\x04rate2Q\n" +
+	"\x04rate\x18\x02 \x01(\x02R\x04rate\x12\x1d\n" +
+	"\n" +
+	"exact_rate\x18\x03 \x01(\tR\texactRate\x12\x18\n" +
+	"\aderived\x18\x04 \x01(\bR\aderived\x12%\n" +
+	"\x04legs\x18\x05 \x03(\v2\x11.currency.RateLegR\x04legs\x12\x1a\n" +
+	"\bprovider\x18\x06 \x01(\tR\bprovider\x129\n" +
+	"\n" +
+	"fetched_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tfetchedAt\"\xd1\x01\n" +
+	"\aRateLeg\x12#\n" +
+	"\rbase_currency\x18\x01 \x01(\tR\fbaseCurrency\x12\x1a\n" +
+	"\bcurrency\x18\x02 \x01(\tR\bcurrency\x12\x12\n" +
+	"\x04rate\x18\x03 \x01(\tR\x04rate\x12\x1a\n" +
+	"\binverted\x18\x04 \x01(\bR\binverted\x12\x1a\n" +
+	"\bprovider\x18\x05 \x01(\tR\bprovider\x129\n" +
+	"\n" +
+	"fetched_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tfetchedAt\"}\n" +
+	"\x0eConvertRequest\x12\x16\n" +
+	"\x06amount\x18\x01 \x01(\tR\x06amount\x12\x12\n" +
+	"\x04from\x18\x02 \x01(\tR\x04from\x12\x0e\n" +
+	"\x02to\x18\x03 \x01(\tR\x02to\x12/\n" +
+	"\x05as_of\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x04asOf\"\xc8\x02\n" +
+	"\x0fConvertResponse\x12\x16\n" +
+	"\x06amount\x18\x01 \x01(\tR\x06amount\x12\x12\n" +
+	"\x04from\x18\x02 \x01(\tR\x04from\x12\x0e\n" +
+	"\x02to\x18\x03 \x01(\tR\x02to\x12\x12\n" +
+	"\x04rate\x18\x04 \x01(\tR\x04rate\x127\n" +
+	"\trate_date\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\brateDate\x12\x1f\n" +
+	"\vminor_units\x18\x06 \x01(\x05R\n" +
+	"minorUnits\x12#\n" +
+	"\rrounding_mode\x18\a \x01(\tR\froundingMode\x12)\n" +
+	"\x10unrounded_amount\x18\b \x01(\tR\x0funroundedAmount\x12;\n" +
+	"\vfixing_date\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"fixingDate\"W\n" +
+	"\x14GetLatestRateRequest\x12\x1a\n" +
+	"\bcurrency\x18\x01 \x01(\tR\bcurrency\x12#\n" +
+	"\rbase_currency\x18\x02 \x01(\tR\fbaseCurrency\"\xf1\x02\n" +
+	"\x15GetLatestRateResponse\x12\x1a\n" +
+	"\bcurrency\x18\x01 \x01(\tR\bcurrency\x12#\n" +
+	"\rbase_currency\x18\x02 \x01(\tR\fbaseCurrency\x12(\n" +
+	"\x04rate\x18\x03 \x01(\v2\x14.currency.RateRecordR\x04rate\x129\n" +
+	"\n" +
+	"fetched_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tfetchedAt\x12+\n" +
+	"\x03age\x18\x05 \x01(\v2\x19.google.protobuf.DurationR\x03age\x122\n" +
+	"\amax_age\x18\x06 \x01(\v2\x19.google.protobuf.DurationR\x06maxAge\x12\x14\n" +
+	"\x05stale\x18\a \x01(\bR\x05stale\x12;\n" +
+	"\vfixing_date\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"fixingDate\"E\n" +
+	"\x14BatchGetRatesRequest\x12-\n" +
+	"\aqueries\x18\x01 \x03(\v2\x13.currency.RateQueryR\aqueries\"|\n" +
+	"\tRateQuery\x12#\n" +
+	"\rbase_currency\x18\x01 \x01(\tR\fbaseCurrency\x12\x1a\n" +
+	"\bcurrency\x18\x02 \x01(\tR\bcurrency\x12.\n" +
+	"\x04date\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x04date\"L\n" +
+	"\x15BatchGetRatesResponse\x123\n" +
+	"\aresults\x18\x01 \x03(\v2\x19.currency.BatchRateResultR\aresults\"\xe1\x01\n" +
+	"\x0fBatchRateResult\x12)\n" +
+	"\x05query\x18\x01 \x01(\v2\x13.currency.RateQueryR\x05query\x12*\n" +
+	"\x04rate\x18\x02 \x01(\v2\x14.currency.RateRecordH\x00R\x04rate\x120\n" +
+	"\x05error\x18\x03 \x01(\v2\x18.currency.BatchRateErrorH\x00R\x05error\x12;\n" +
+	"\vfixing_date\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"fixingDateB\b\n" +
+	"\x06result\">\n" +
+	"\x0eBatchRateError\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\x05R\x04code\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"E\n" +
+	"\x15SubscribeRatesRequest\x12,\n" +
+	"\x05pairs\x18\x01 \x03(\v2\x16.currency.CurrencyPairR\x05pairs\"O\n" +
+	"\fCurrencyPair\x12#\n" +
+	"\rbase_currency\x18\x01 \x01(\tR\fbaseCurrency\x12\x1a\n" +
+	"\bcurrency\x18\x02 \x01(\tR\bcurrency\"\x83\x01\n" +
+	"\x16SubscribeRatesResponse\x12#\n" +
+	"\rbase_currency\x18\x01 \x01(\tR\fbaseCurrency\x12\x1a\n" +
+	"\bcurrency\x18\x02 \x01(\tR\bcurrency\x12(\n" +
+	"\x04rate\x18\x03 \x01(\v2\x14.currency.RateRecordR\x04rate\"\xcd\x01\n" +
+	"\x13GetGapReportRequest\x12,\n" +
+	"\x05pairs\x18\x01 \x03(\v2\x16.currency.CurrencyPairR\x05pairs\x12\x1a\n" +
+	"\bprovider\x18\x02 \x01(\tR\bprovider\x127\n" +
+	"\tdate_from\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\bdateFrom\x123\n" +
+	"\adate_to\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x06dateTo\"E\n" +
+	"\x14GetGapReportResponse\x12-\n" +
+	"\areports\x18\x01 \x03(\v2\x13.currency.GapReportR\areports\"\xbc\x02\n" +
+	"\tGapReport\x12\x1a\n" +
+	"\bprovider\x18\x01 \x01(\tR\bprovider\x12#\n" +
+	"\rbase_currency\x18\x02 \x01(\tR\fbaseCurrency\x12\x1a\n" +
+	"\bcurrency\x18\x03 \x01(\tR\bcurrency\x127\n" +
+	"\tdate_from\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\bdateFrom\x123\n" +
+	"\adate_to\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x06dateTo\x12#\n" +
+	"\rexpected_days\x18\x06 \x01(\x05R\fexpectedDays\x12?\n" +
+	"\rmissing_dates\x18\a \x03(\v2\x1a.google.protobuf.TimestampR\fmissingDates\"\x94\x01\n" +
+	"\x11GetJobRunsRequest\x12\x10\n" +
+	"\x03job\x18\x01 \x01(\tR\x03job\x12#\n" +
+	"\rbase_currency\x18\x02 \x01(\tR\fbaseCurrency\x12\x1a\n" +
+	"\bcurrency\x18\x03 \x01(\tR\bcurrency\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12\x14\n" +
+	"\x05limit\x18\x05 \x01(\x05R\x05limit\":\n" +
+	"\x12GetJobRunsResponse\x12$\n" +
+	"\x04runs\x18\x01 \x03(\v2\x10.currency.JobRunR\x04runs\"\xa1\x03\n" +
+	"\x06JobRun\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x10\n" +
+	"\x03job\x18\x02 \x01(\tR\x03job\x12\x18\n" +
+	"\atrigger\x18\x03 \x01(\tR\atrigger\x12\x1a\n" +
+	"\bprovider\x18\x04 \x01(\tR\bprovider\x12#\n" +
+	"\rbase_currency\x18\x05 \x01(\tR\fbaseCurrency\x12\x1a\n" +
+	"\bcurrency\x18\x06 \x01(\tR\bcurrency\x129\n" +
+	"\n" +
+	"started_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tstartedAt\x12;\n" +
+	"\vfinished_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"finishedAt\x125\n" +
+	"\bduration\x18\t \x01(\v2\x19.google.protobuf.DurationR\bduration\x12\x16\n" +
+	"\x06status\x18\n" +
+	" \x01(\tR\x06status\x12\x14\n" +
+	"\x05error\x18\v \x01(\tR\x05error\x12!\n" +
+	"\frows_written\x18\f \x01(\x05R\vrowsWritten2\xa4\x04\n" +
 	"\x0fCurrencyService\x12>\n" +
-	"\aGetRate\x12\x18.currency.GetRateRequest\x1a\x19.currency.GetRateResponseB\x0eZ\fpkg/currencyb\x06proto3"
+	"\aGetRate\x12\x18.currency.GetRateRequest\x1a\x19.currency.GetRateResponse\x12>\n" +
+	"\aConvert\x12\x18.currency.ConvertRequest\x1a\x19.currency.ConvertResponse\x12P\n" +
+	"\rGetLatestRate\x12\x1e.currency.GetLatestRateRequest\x1a\x1f.currency.GetLatestRateResponse\x12P\n" +
+	"\rBatchGetRates\x12\x1e.currency.BatchGetRatesRequest\x1a\x1f.currency.BatchGetRatesResponse\x12U\n" +
+	"\x0eSubscribeRates\x12\x1f.currency.SubscribeRatesRequest\x1a .currency.SubscribeRatesResponse0\x01\x12M\n" +
+	"\fGetGapReport\x12\x1d.currency.GetGapReportRequest\x1a\x1e.currency.GetGapReportResponse\x12G\n" +
+	"\n" +
+	"GetJobRuns\x12\x1b.currency.GetJobRunsRequest\x1a\x1c.currency.GetJobRunsResponseB\x0eZ\fpkg/currencyb\x06proto3"
 
 var (
 	file_proto_currency_currency_service_proto_rawDescOnce sync.Once
@@ -226,25 +1759,88 @@ func file_proto_currency_currency_service_proto_rawDescGZIP() []byte {
 	return file_proto_currency_currency_service_proto_rawDescData
 }
 
-var file_proto_currency_currency_service_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_proto_currency_currency_service_proto_msgTypes = make([]protoimpl.MessageInfo, 22)
 var file_proto_currency_currency_service_proto_goTypes = []any{
-	(*GetRateRequest)(nil),        // 0: currency.GetRateRequest
-	(*GetRateResponse)(nil),       // 1: currency.GetRateResponse
-	(*RateRecord)(nil),            // 2: currency.RateRecord
-	(*timestamppb.Timestamp)(nil), // 3: google.protobuf.Timestamp
+	(*GetRateRequest)(nil),         // 0: currency.GetRateRequest
+	(*GetRateResponse)(nil),        // 1: currency.GetRateResponse
+	(*RateRecord)(nil),             // 2: currency.RateRecord
+	(*RateLeg)(nil),                // 3: currency.RateLeg
+	(*ConvertRequest)(nil),         // 4: currency.ConvertRequest
+	(*ConvertResponse)(nil),        // 5: currency.ConvertResponse
+	(*GetLatestRateRequest)(nil),   // 6: currency.GetLatestRateRequest
+	(*GetLatestRateResponse)(nil),  // 7: currency.GetLatestRateResponse
+	(*BatchGetRatesRequest)(nil),   // 8: currency.BatchGetRatesRequest
+	(*RateQuery)(nil),              // 9: currency.RateQuery
+	(*BatchGetRatesResponse)(nil),  // 10: currency.BatchGetRatesResponse
+	(*BatchRateResult)(nil),        // 11: currency.BatchRateResult
+	(*BatchRateError)(nil),         // 12: currency.BatchRateError
+	(*SubscribeRatesRequest)(nil),  // 13: currency.SubscribeRatesRequest
+	(*CurrencyPair)(nil),           // 14: currency.CurrencyPair
+	(*SubscribeRatesResponse)(nil), // 15: currency.SubscribeRatesResponse
+	(*GetGapReportRequest)(nil),    // 16: currency.GetGapReportRequest
+	(*GetGapReportResponse)(nil),   // 17: currency.GetGapReportResponse
+	(*GapReport)(nil),              // 18: currency.GapReport
+	(*GetJobRunsRequest)(nil),      // 19: currency.GetJobRunsRequest
+	(*GetJobRunsResponse)(nil),     // 20: currency.GetJobRunsResponse
+	(*JobRun)(nil),                 // 21: currency.JobRun
+	(*timestamppb.Timestamp)(nil),  // 22: google.protobuf.Timestamp
+	(*durationpb.Duration)(nil),    // 23: google.protobuf.Duration
 }
 var file_proto_currency_currency_service_proto_depIdxs = []int32{
-	3, // 0: currency.GetRateRequest.data_from:type_name -> google.protobuf.Timestamp
-	3, // 1: currency.GetRateRequest.date_to:type_name -> google.protobuf.Timestamp
-	2, // 2: currency.GetRateResponse.rates:type_name -> currency.RateRecord
-	3, // 3: currency.RateRecord.date:type_name -> google.protobuf.Timestamp
-	0, // 4: currency.CurrencyService.GetRate:input_type -> currency.GetRateRequest
-	1, // 5: currency.CurrencyService.GetRate:output_type -> currency.GetRateResponse
-	5, // [5:6] is the sub-list for method output_type
-	4, // [4:5] is the sub-list for method input_type
-	4, // [4:4] is the sub-list for extension type_name
-	4, // [4:4] is the sub-list for extension extendee
-	0, // [0:4] is the sub-list for field type_name
+	22, // 0: currency.GetRateRequest.data_from:type_name -> google.protobuf.Timestamp
+	22, // 1: currency.GetRateRequest.date_to:type_name -> google.protobuf.Timestamp
+	2,  // 2: currency.GetRateResponse.rates:type_name -> currency.RateRecord
+	22, // 3: currency.RateRecord.date:type_name -> google.protobuf.Timestamp
+	3,  // 4: currency.RateRecord.legs:type_name -> currency.RateLeg
+	22, // 5: currency.RateRecord.fetched_at:type_name -> google.protobuf.Timestamp
+	22, // 6: currency.RateLeg.fetched_at:type_name -> google.protobuf.Timestamp
+	22, // 7: currency.ConvertRequest.as_of:type_name -> google.protobuf.Timestamp
+	22, // 8: currency.ConvertResponse.rate_date:type_name -> google.protobuf.Timestamp
+	22, // 9: currency.ConvertResponse.fixing_date:type_name -> google.protobuf.Timestamp
+	2,  // 10: currency.GetLatestRateResponse.rate:type_name -> currency.RateRecord
+	22, // 11: currency.GetLatestRateResponse.fetched_at:type_name -> google.protobuf.Timestamp
+	23, // 12: currency.GetLatestRateResponse.age:type_name -> google.protobuf.Duration
+	23, // 13: currency.GetLatestRateResponse.max_age:type_name -> google.protobuf.Duration
+	22, // 14: currency.GetLatestRateResponse.fixing_date:type_name -> google.protobuf.Timestamp
+	9,  // 15: currency.BatchGetRatesRequest.queries:type_name -> currency.RateQuery
+	22, // 16: currency.RateQuery.date:type_name -> google.protobuf.Timestamp
+	11, // 17: currency.BatchGetRatesResponse.results:type_name -> currency.BatchRateResult
+	9,  // 18: currency.BatchRateResult.query:type_name -> currency.RateQuery
+	2,  // 19: currency.BatchRateResult.rate:type_name -> currency.RateRecord
+	12, // 20: currency.BatchRateResult.error:type_name -> currency.BatchRateError
+	22, // 21: currency.BatchRateResult.fixing_date:type_name -> google.protobuf.Timestamp
+	14, // 22: currency.SubscribeRatesRequest.pairs:type_name -> currency.CurrencyPair
+	2,  // 23: currency.SubscribeRatesResponse.rate:type_name -> currency.RateRecord
+	14, // 24: currency.GetGapReportRequest.pairs:type_name -> currency.CurrencyPair
+	22, // 25: currency.GetGapReportRequest.date_from:type_name -> google.protobuf.Timestamp
+	22, // 26: currency.GetGapReportRequest.date_to:type_name -> google.protobuf.Timestamp
+	18, // 27: currency.GetGapReportResponse.reports:type_name -> currency.GapReport
+	22, // 28: currency.GapReport.date_from:type_name -> google.protobuf.Timestamp
+	22, // 29: currency.GapReport.date_to:type_name -> google.protobuf.Timestamp
+	22, // 30: currency.GapReport.missing_dates:type_name -> google.protobuf.Timestamp
+	21, // 31: currency.GetJobRunsResponse.runs:type_name -> currency.JobRun
+	22, // 32: currency.JobRun.started_at:type_name -> google.protobuf.Timestamp
+	22, // 33: currency.JobRun.finished_at:type_name -> google.protobuf.Timestamp
+	23, // 34: currency.JobRun.duration:type_name -> google.protobuf.Duration
+	0,  // 35: currency.CurrencyService.GetRate:input_type -> currency.GetRateRequest
+	4,  // 36: currency.CurrencyService.Convert:input_type -> currency.ConvertRequest
+	6,  // 37: currency.CurrencyService.GetLatestRate:input_type -> currency.GetLatestRateRequest
+	8,  // 38: currency.CurrencyService.BatchGetRates:input_type -> currency.BatchGetRatesRequest
+	13, // 39: currency.CurrencyService.SubscribeRates:input_type -> currency.SubscribeRatesRequest
+	16, // 40: currency.CurrencyService.GetGapReport:input_type -> currency.GetGapReportRequest
+	19, // 41: currency.CurrencyService.GetJobRuns:input_type -> currency.GetJobRunsRequest
+	1,  // 42: currency.CurrencyService.GetRate:output_type -> currency.GetRateResponse
+	5,  // 43: currency.CurrencyService.Convert:output_type -> currency.ConvertResponse
+	7,  // 44: currency.CurrencyService.GetLatestRate:output_type -> currency.GetLatestRateResponse
+	10, // 45: currency.CurrencyService.BatchGetRates:output_type -> currency.BatchGetRatesResponse
+	15, // 46: currency.CurrencyService.SubscribeRates:output_type -> currency.SubscribeRatesResponse
+	17, // 47: currency.CurrencyService.GetGapReport:output_type -> currency.GetGapReportResponse
+	20, // 48: currency.CurrencyService.GetJobRuns:output_type -> currency.GetJobRunsResponse
+	42, // [42:49] is the sub-list for method output_type
+	35, // [35:42] is the sub-list for method input_type
+	35, // [35:35] is the sub-list for extension type_name
+	35, // [35:35] is the sub-list for extension extendee
+	0,  // [0:35] is the sub-list for field type_name
 }
 
 func init() { file_proto_currency_currency_service_proto_init() }
@@ -252,13 +1848,17 @@ func file_proto_currency_currency_service_proto_init() {
 	if File_proto_currency_currency_service_proto != nil {
 		return
 	}
+	file_proto_currency_currency_service_proto_msgTypes[11].OneofWrappers = []any{
+		(*BatchRateResult_Rate)(nil),
+		(*BatchRateResult_Error)(nil),
+	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_currency_currency_service_proto_rawDesc), len(file_proto_currency_currency_service_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   3,
+			NumMessages:   22,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -19,7 +19,13 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	CurrencyService_GetRate_FullMethodName = "/currency.CurrencyService/GetRate"
+	CurrencyService_GetRate_FullMethodName        = "/currency.CurrencyService/GetRate"
+	CurrencyService_Convert_FullMethodName        = "/currency.CurrencyService/Convert"
+	CurrencyService_GetLatestRate_FullMethodName  = "/currency.CurrencyService/GetLatestRate"
+	CurrencyService_BatchGetRates_FullMethodName  = "/currency.CurrencyService/BatchGetRates"
+	CurrencyService_SubscribeRates_FullMethodName = "/currency.CurrencyService/SubscribeRates"
+	CurrencyService_GetGapReport_FullMethodName   = "/currency.CurrencyService/GetGapReport"
+	CurrencyService_GetJobRuns_FullMethodName     = "/currency.CurrencyService/GetJobRuns"
 )
 
 // CurrencyServiceClient is the client API for CurrencyService service.
@@ -27,6 +33,17 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type CurrencyServiceClient interface {
 	GetRate(ctx context.Context, in *GetRateRequest, opts ...grpc.CallOption) (*GetRateResponse, error)
+	Convert(ctx context.Context, in *ConvertRequest, opts ...grpc.CallOption) (*ConvertResponse, error)
+	GetLatestRate(ctx context.Context, in *GetLatestRateRequest, opts ...grpc.CallOption) (*GetLatestRateResponse, error)
+	BatchGetRates(ctx context.Context, in *BatchGetRatesRequest, opts ...grpc.CallOption) (*BatchGetRatesResponse, error)
+	// Streams observations of the requested pairs as the worker stores them.
+	SubscribeRates(ctx context.Context, in *SubscribeRatesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[SubscribeRatesResponse], error)
+	// Lists publishing days without a stored observation. Requires the
+	// rates:admin scope.
+	GetGapReport(ctx context.Context, in *GetGapReportRequest, opts ...grpc.CallOption) (*GetGapReportResponse, error)
+	// Lists recorded runs of the worker jobs, newest first. Requires the
+	// rates:admin scope.
+	GetJobRuns(ctx context.Context, in *GetJobRunsRequest, opts ...grpc.CallOption) (*GetJobRunsResponse, error)
 }
 
 type currencyServiceClient struct {
@@ -47,11 +64,91 @@ func (c *currencyServiceClient) GetRate(ctx context.Context, in *GetRateRequest,
 	return out, nil
 }
 
+func (c *currencyServiceClient) Convert(ctx context.Context, in *ConvertRequest, opts ...grpc.CallOption) (*ConvertResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ConvertResponse)
+	err := c.cc.Invoke(ctx, CurrencyService_Convert_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *currencyServiceClient) GetLatestRate(ctx context.Context, in *GetLatestRateRequest, opts ...grpc.CallOption) (*GetLatestRateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetLatestRateResponse)
+	err := c.cc.Invoke(ctx, CurrencyService_GetLatestRate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *currencyServiceClient) BatchGetRates(ctx context.Context, in *BatchGetRatesRequest, opts ...grpc.CallOption) (*BatchGetRatesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchGetRatesResponse)
+	err := c.cc.Invoke(ctx, CurrencyService_BatchGetRates_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *currencyServiceClient) SubscribeRates(ctx context.Context, in *SubscribeRatesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[SubscribeRatesResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &CurrencyService_ServiceDesc.Streams[0], CurrencyService_SubscribeRates_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SubscribeRatesRequest, SubscribeRatesResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CurrencyService_SubscribeRatesClient = grpc.ServerStreamingClient[SubscribeRatesResponse]
+
+func (c *currencyServiceClient) GetGapReport(ctx context.Context, in *GetGapReportRequest, opts ...grpc.CallOption) (*GetGapReportResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetGapReportResponse)
+	err := c.cc.Invoke(ctx, CurrencyService_GetGapReport_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *currencyServiceClient) GetJobRuns(ctx context.Context, in *GetJobRunsRequest, opts ...grpc.CallOption) (*GetJobRunsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetJobRunsResponse)
+	err := c.cc.Invoke(ctx, CurrencyService_GetJobRuns_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // CurrencyServiceServer is the server API for CurrencyService service.
 // All implementations must embed UnimplementedCurrencyServiceServer
 // for forward compatibility.
 type CurrencyServiceServer interface {
 	GetRate(context.Context, *GetRateRequest) (*GetRateResponse, error)
+	Convert(context.Context, *ConvertRequest) (*ConvertResponse, error)
+	GetLatestRate(context.Context, *GetLatestRateRequest) (*GetLatestRateResponse, error)
+	BatchGetRates(context.Context, *BatchGetRatesRequest) (*BatchGetRatesResponse, error)
+	// Streams observations of the requested pairs as the worker stores them.
+	SubscribeRates(*SubscribeRatesRequest, grpc.ServerStreamingServer[SubscribeRatesResponse]) error
+	// Lists publishing days without a stored observation. Requires the
+	// rates:admin scope.
+	GetGapReport(context.Context, *GetGapReportRequest) (*GetGapReportResponse, error)
+	// Lists recorded runs of the worker jobs, newest first. Requires the
+	// rates:admin scope.
+	GetJobRuns(context.Context, *GetJobRunsRequest) (*GetJobRunsResponse, error)
 	mustEmbedUnimplementedCurrencyServiceServer()
 }
 
@@ -65,6 +162,24 @@ type UnimplementedCurrencyServiceServer struct{}
 func (UnimplementedCurrencyServiceServer) GetRate(context.Context, *GetRateRequest) (*GetRateResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method GetRate not implemented")
 }
+func (UnimplementedCurrencyServiceServer) Convert(context.Context, *ConvertRequest) (*ConvertResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Convert not implemented")
+}
+func (UnimplementedCurrencyServiceServer) GetLatestRate(context.Context, *GetLatestRateRequest) (*GetLatestRateResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetLatestRate not implemented")
+}
+func (UnimplementedCurrencyServiceServer) BatchGetRates(context.Context, *BatchGetRatesRequest) (*BatchGetRatesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method BatchGetRates not implemented")
+}
+func (UnimplementedCurrencyServiceServer) SubscribeRates(*SubscribeRatesRequest, grpc.ServerStreamingServer[SubscribeRatesResponse]) error {
+	return status.Error(codes.Unimplemented, "method SubscribeRates not implemented")
+}
+func (UnimplementedCurrencyServiceServer) GetGapReport(context.Context, *GetGapReportRequest) (*GetGapReportResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetGapReport not implemented")
+}
+func (UnimplementedCurrencyServiceServer) GetJobRuns(context.Context, *GetJobRunsRequest) (*GetJobRunsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetJobRuns not implemented")
+}
 func (UnimplementedCurrencyServiceServer) mustEmbedUnimplementedCurrencyServiceServer() {}
 func (UnimplementedCurrencyServiceServer) testEmbeddedByValue()                         {}
 
@@ -104,6 +219,107 @@ func _CurrencyService_GetRate_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CurrencyService_Convert_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConvertRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CurrencyServiceServer).Convert(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CurrencyService_Convert_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CurrencyServiceServer).Convert(ctx, req.(*ConvertRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CurrencyService_GetLatestRate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLatestRateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CurrencyServiceServer).GetLatestRate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CurrencyService_GetLatestRate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CurrencyServiceServer).GetLatestRate(ctx, req.(*GetLatestRateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CurrencyService_BatchGetRates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchGetRatesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CurrencyServiceServer).BatchGetRates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CurrencyService_BatchGetRates_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CurrencyServiceServer).BatchGetRates(ctx, req.(*BatchGetRatesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CurrencyService_SubscribeRates_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeRatesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CurrencyServiceServer).SubscribeRates(m, &grpc.GenericServerStream[SubscribeRatesRequest, SubscribeRatesResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CurrencyService_SubscribeRatesServer = grpc.ServerStreamingServer[SubscribeRatesResponse]
+
+func _CurrencyService_GetGapReport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetGapReportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CurrencyServiceServer).GetGapReport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CurrencyService_GetGapReport_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CurrencyServiceServer).GetGapReport(ctx, req.(*GetGapReportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CurrencyService_GetJobRuns_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetJobRunsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CurrencyServiceServer).GetJobRuns(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CurrencyService_GetJobRuns_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CurrencyServiceServer).GetJobRuns(ctx, req.(*GetJobRunsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // CurrencyService_ServiceDesc is the grpc.ServiceDesc for CurrencyService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -115,7 +331,33 @@ var CurrencyService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetRate",
 			Handler:    _CurrencyService_GetRate_Handler,
 		},
+		{
+			MethodName: "Convert",
+			Handler:    _CurrencyService_Convert_Handler,
+		},
+		{
+			MethodName: "GetLatestRate",
+			Handler:    _CurrencyService_GetLatestRate_Handler,
+		},
+		{
+			MethodName: "BatchGetRates",
+			Handler:    _CurrencyService_BatchGetRates_Handler,
+		},
+		{
+			MethodName: "GetGapReport",
+			Handler:    _CurrencyService_GetGapReport_Handler,
+		},
+		{
+			MethodName: "GetJobRuns",
+			Handler:    _CurrencyService_GetJobRuns_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubscribeRates",
+			Handler:       _CurrencyService_SubscribeRates_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/currency/currency_service.proto",
 }